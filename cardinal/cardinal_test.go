@@ -25,6 +25,15 @@ func TestNewWorld(t *testing.T) {
 	assert.Assert(t, err != nil)
 }
 
+func TestNewEmbeddedWorld(t *testing.T) {
+	_, err := cardinal.NewEmbeddedWorld("", cardinal.WithNamespace("testnamespace"))
+	assert.ErrorContains(t, err, "data directory is required")
+
+	world, err := cardinal.NewEmbeddedWorld(t.TempDir(), cardinal.WithNamespace("testnamespace"))
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.RegisterComponent[Foo](world))
+}
+
 func TestCanQueryInsideSystem(t *testing.T) {
 	testutils.SetTestTimeout(t, 10*time.Second)
 
@@ -3,8 +3,11 @@ package ecs_test
 import (
 	"context"
 	"encoding/binary"
+	"errors"
+	"maps"
 	"sort"
 	"testing"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 	"gotest.tools/v3/assert"
@@ -12,6 +15,8 @@ import (
 
 	"github.com/cometbft/cometbft/libs/rand"
 	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
 	"pkg.world.dev/world-engine/cardinal/shard"
 	"pkg.world.dev/world-engine/chain/x/shard/types"
 	"pkg.world.dev/world-engine/sign"
@@ -20,39 +25,49 @@ import (
 var _ shard.Adapter = &DummyAdapter{}
 
 type DummyAdapter struct {
-	txs map[uint64][]*types.Transaction
+	epochs map[uint64]*types.Epoch
 }
 
-func (d *DummyAdapter) Submit(_ context.Context, p *sign.Transaction, txID, tick uint64) error {
-	sp := &shardv1.Transaction{
-		PersonaTag: p.PersonaTag,
-		Namespace:  p.Namespace,
-		Nonce:      p.Nonce,
-		Signature:  p.Signature,
-		Body:       p.Body,
+func (d *DummyAdapter) SubmitEpoch(_ context.Context, epoch *shard.Epoch) error {
+	txs := make([]*types.Transaction, 0, len(epoch.Txs))
+	for _, tx := range epoch.Txs {
+		sp := &shardv1.Transaction{
+			PersonaTag: tx.Tx.PersonaTag,
+			Namespace:  tx.Tx.Namespace,
+			Nonce:      tx.Tx.Nonce,
+			Signature:  tx.Tx.Signature,
+			Body:       tx.Tx.Body,
+		}
+		bz, err := proto.Marshal(sp)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, &types.Transaction{
+			TxId:                 tx.TxID,
+			GameShardTransaction: bz,
+		})
 	}
-	bz, err := proto.Marshal(sp)
-	if err != nil {
-		return err
+	if d.epochs == nil {
+		d.epochs = make(map[uint64]*types.Epoch)
 	}
-	if d.txs[tick] == nil {
-		d.txs[tick] = make([]*types.Transaction, 0)
+	// Like the base shard, an epoch that is submitted again replaces the epoch that was submitted before.
+	d.epochs[epoch.Epoch] = &types.Epoch{
+		Epoch:         epoch.Epoch,
+		Txs:           txs,
+		Timestamp:     epoch.Timestamp,
+		PrevStateHash: epoch.PrevStateHash,
 	}
-	d.txs[tick] = append(d.txs[tick], &types.Transaction{
-		TxId:                 txID,
-		GameShardTransaction: bz,
-	})
 	return nil
 }
 
 func (d *DummyAdapter) QueryTransactions(_ context.Context, request *types.QueryTransactionsRequest,
 ) (*types.QueryTransactionsResponse, error) {
-	tickedTxs := make([]*types.Epoch, 0, len(d.txs))
-	for tick, txs := range d.txs {
-		tickedTxs = append(tickedTxs, &types.Epoch{
-			Epoch: tick,
-			Txs:   txs,
-		})
+	tickedTxs := make([]*types.Epoch, 0, len(d.epochs))
+	for tick, epoch := range d.epochs {
+		if tick < request.StartEpoch {
+			continue
+		}
+		tickedTxs = append(tickedTxs, epoch)
 	}
 	sort.Slice(tickedTxs, func(i, j int) bool {
 		return tickedTxs[i].Epoch < tickedTxs[j].Epoch
@@ -84,12 +99,21 @@ type SendEnergyTransaction struct {
 
 type SendEnergyTransactionResponse struct{}
 
+// submitTransaction submits an epoch with a single transaction to the adapter.
+func submitTransaction(t *testing.T, adapter shard.WriteAdapter, tick uint64, txID uint64, tx *sign.Transaction) {
+	err := adapter.SubmitEpoch(context.Background(), &shard.Epoch{
+		Epoch: tick,
+		Txs:   []shard.EpochTransaction{{TxID: txID, Tx: tx}},
+	})
+	assert.NilError(t, err)
+}
+
 // TestWorld_RecoverFromChain tests that after submitting transactions to the chain, they can be queried, re-ran,
 // and end up with the same game state as before.
 func TestWorld_RecoverFromChain(t *testing.T) {
 	// setup world and transactions
 	ctx := context.Background()
-	adapter := &DummyAdapter{txs: make(map[uint64][]*types.Transaction, 0)}
+	adapter := &DummyAdapter{}
 	w := ecs.NewTestWorld(t, ecs.WithAdapter(adapter))
 	sendEnergyTx := ecs.NewTransactionType[SendEnergyTransaction, SendEnergyTransactionResponse]("send_energy")
 	err := w.RegisterTransactions(sendEnergyTx)
@@ -112,8 +136,7 @@ func TestWorld_RecoverFromChain(t *testing.T) {
 	for i := 0; i <= 10; i++ {
 		payload := generateRandomTransaction(t, namespace, sendEnergyTx)
 		payloads = append(payloads, payload)
-		err = adapter.Submit(ctx, payload, uint64(sendEnergyTx.ID()), uint64(i+i)) // final tick should be 10+10 = 20
-		assert.NilError(t, err)
+		submitTransaction(t, adapter, uint64(i+i), uint64(sendEnergyTx.ID()), payload) // final tick should be 10+10 = 20
 	}
 
 	err = w.LoadGameState()
@@ -143,14 +166,195 @@ func generateRandomTransaction(t *testing.T, ns string, tx *ecs.TransactionType[
 	}
 }
 
-func TestWorld_RecoverShouldErrorIfTickExists(t *testing.T) {
-	// setup world and transactions
+func TestWorld_RecoverFromExistingTick(t *testing.T) {
 	ctx := context.Background()
 	adapter := &DummyAdapter{}
 	w := ecs.NewTestWorld(t, ecs.WithAdapter(adapter))
+	sendEnergyTx := ecs.NewTransactionType[SendEnergyTransaction, SendEnergyTransactionResponse]("send_energy")
+	assert.NilError(t, w.RegisterTransactions(sendEnergyTx))
+
+	timesSendEnergyRan := 0
+	w.AddSystem(func(wCtx ecs.WorldContext) error {
+		timesSendEnergyRan += len(sendEnergyTx.In(wCtx))
+		return nil
+	})
+	for i := 0; i <= 10; i++ {
+		payload := generateRandomTransaction(t, "game1", sendEnergyTx)
+		submitTransaction(t, adapter, uint64(i+i), uint64(sendEnergyTx.ID()), payload)
+	}
+
+	// pretend the first 11 ticks (0 through 10) have already been run.
 	assert.NilError(t, w.LoadGameState())
+	for i := 0; i < 11; i++ {
+		assert.NilError(t, w.Tick(ctx))
+	}
+
+	assert.NilError(t, w.RecoverFromChain(ctx))
+	assert.Equal(t, uint64(21), w.CurrentTick())
+	// only the transactions from ticks 12, 14, 16, 18 and 20 should have been recovered.
+	assert.Equal(t, 5, timesSendEnergyRan)
+}
+
+func TestWorld_RecoverFromChainReplaysTimestamps(t *testing.T) {
+	ctx := context.Background()
+	adapter := &DummyAdapter{}
+	newWorld := func() (*ecs.World, *[]time.Time, *ecs.TransactionType[SendEnergyTransaction,
+		SendEnergyTransactionResponse]) {
+		w := ecs.NewTestWorld(t, ecs.WithAdapter(adapter))
+		sendEnergyTx := ecs.NewTransactionType[SendEnergyTransaction, SendEnergyTransactionResponse]("send_energy")
+		assert.NilError(t, w.RegisterTransactions(sendEnergyTx))
+		var timestamps []time.Time
+		w.AddSystem(func(wCtx ecs.WorldContext) error {
+			timestamps = append(timestamps, wCtx.Timestamp())
+			return nil
+		})
+		assert.NilError(t, w.LoadGameState())
+		return w, &timestamps, sendEnergyTx
+	}
+
+	// Epochs are submitted to the base shard along with their timestamps.
+	live, liveTimestamps, sendEnergyTx := newWorld()
+	sendEnergyTx.AddToQueue(live, SendEnergyTransaction{Amount: 1}, generateRandomTransaction(t, "game1", sendEnergyTx))
+	assert.NilError(t, live.Tick(ctx))
+	assert.NilError(t, live.Tick(ctx))
+	time.Sleep(5 * time.Millisecond)
+	sendEnergyTx.AddToQueue(live, SendEnergyTransaction{Amount: 2}, generateRandomTransaction(t, "game1", sendEnergyTx))
+	assert.NilError(t, live.Tick(ctx))
+	assert.NilError(t, live.Tick(ctx))
+	assert.NilError(t, live.SubmitQueuedEpochs(ctx))
+	assert.Equal(t, 2, len(adapter.epochs))
+
+	recovered, recoveredTimestamps, _ := newWorld()
+	assert.NilError(t, recovered.RecoverFromChain(ctx))
+	assert.Equal(t, live.CurrentTick()-1, recovered.CurrentTick())
+	// Tick 1 had no transactions, so it was exactly one tick interval after tick 0 when it was first run as well.
+	assert.Equal(t, (*liveTimestamps)[0].Add(ecs.DefaultTickInterval), (*liveTimestamps)[1])
+	assert.Equal(t, (*liveTimestamps)[2].Add(ecs.DefaultTickInterval), (*liveTimestamps)[3])
+	assert.DeepEqual(t, (*liveTimestamps)[:3], *recoveredTimestamps)
+}
+
+// flakyAdapter fails the given number of submissions before it starts submitting to the DummyAdapter. If lost is
+// set, the given number of submissions are stored, but the response is lost.
+type flakyAdapter struct {
+	*DummyAdapter
+	failures int
+	lost     int
+}
+
+func (f *flakyAdapter) SubmitEpoch(ctx context.Context, epoch *shard.Epoch) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("base shard is unavailable")
+	}
+	if err := f.DummyAdapter.SubmitEpoch(ctx, epoch); err != nil {
+		return err
+	}
+	if f.lost > 0 {
+		f.lost--
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func TestTickDoesNotWaitForTheBaseShard(t *testing.T) {
+	ctx := context.Background()
+	adapter := &flakyAdapter{DummyAdapter: &DummyAdapter{}, failures: 100}
+	w := ecs.NewTestWorld(t, ecs.WithAdapter(adapter))
+	sendEnergyTx := ecs.NewTransactionType[SendEnergyTransaction, SendEnergyTransactionResponse]("send_energy")
+	assert.NilError(t, w.RegisterTransactions(sendEnergyTx))
+	assert.NilError(t, w.LoadGameState())
+
+	sendEnergyTx.AddToQueue(w, SendEnergyTransaction{Amount: 1}, generateRandomTransaction(t, "game1", sendEnergyTx))
 	assert.NilError(t, w.Tick(ctx))
+	assert.NilError(t, w.Tick(ctx))
+	assert.ErrorContains(t, w.SubmitQueuedEpochs(ctx), "base shard is unavailable")
+	assert.Equal(t, 0, len(adapter.epochs))
 
-	err := w.RecoverFromChain(ctx)
-	assert.ErrorContains(t, err, "world recovery should not occur in a world with existing state")
+	// The epoch stays queued until the base shard is available again.
+	adapter.failures = 0
+	assert.NilError(t, w.SubmitQueuedEpochs(ctx))
+	assert.Equal(t, 1, len(adapter.epochs))
+	assert.Equal(t, 1, len(adapter.epochs[0].Txs))
+	adapter.epochs = nil
+	assert.NilError(t, w.SubmitQueuedEpochs(ctx))
+	assert.Equal(t, 0, len(adapter.epochs))
+}
+
+func TestWorld_RecoverFromChainAfterPartialSubmit(t *testing.T) {
+	ctx := context.Background()
+	adapter := &flakyAdapter{DummyAdapter: &DummyAdapter{}}
+	failSystem := false
+	timestamps := map[uint64]time.Time{}
+	newWorld := func(kv ecb.KVStore) (*ecs.World, *ecs.TransactionType[SendEnergyTransaction,
+		SendEnergyTransactionResponse]) {
+		manager, err := ecb.NewManagerWithKVStore(kv)
+		assert.NilError(t, err)
+		w, err := ecs.NewWorld(ecb.NewKVNonceStorage(kv), manager, ecs.WithAdapter(adapter))
+		assert.NilError(t, err)
+		sendEnergyTx := ecs.NewTransactionType[SendEnergyTransaction, SendEnergyTransactionResponse]("send_energy")
+		assert.NilError(t, w.RegisterTransactions(sendEnergyTx))
+		assert.NilError(t, ecs.RegisterComponent[EnergyComponent](w))
+		w.AddSystem(func(wCtx ecs.WorldContext) error {
+			timestamps[wCtx.CurrentTick()] = wCtx.Timestamp()
+			for _, tx := range sendEnergyTx.In(wCtx) {
+				if _, err := component.Create(wCtx, EnergyComponent{Amt: int64(tx.Value.Amount)}); err != nil {
+					return err
+				}
+			}
+			if failSystem {
+				return errors.New("system failed")
+			}
+			return nil
+		})
+		assert.NilError(t, w.LoadGameState())
+		return w, sendEnergyTx
+	}
+
+	// queue adds a transaction whose value matches its body, so it has the same effect when it is recovered.
+	queue := func(w *ecs.World, tx *ecs.TransactionType[SendEnergyTransaction, SendEnergyTransactionResponse]) {
+		payload := generateRandomTransaction(t, "game1", tx)
+		value, err := tx.Decode(payload.Body)
+		assert.NilError(t, err)
+		tx.AddToQueue(w, value.(SendEnergyTransaction), payload)
+	}
+
+	kv := ecb.NewMemoryKVStore()
+	live, sendEnergyTx := newWorld(kv)
+	queue(live, sendEnergyTx)
+	assert.NilError(t, live.Tick(ctx))
+	assert.NilError(t, live.Tick(ctx))
+	// The epoch is stored by the base shard, but the world does not find out.
+	adapter.lost = 1
+	assert.ErrorContains(t, live.SubmitQueuedEpochs(ctx), "connection reset")
+	assert.Equal(t, 1, len(adapter.epochs))
+
+	// The world stops in the middle of a tick.
+	failSystem = true
+	queue(live, sendEnergyTx)
+	assert.ErrorContains(t, live.Tick(ctx), "system failed")
+	failSystem = false
+
+	// The incomplete tick is recovered when the world is restarted, and the epoch whose response was lost is submitted
+	// again without duplicating its transactions.
+	restarted, _ := newWorld(kv)
+	assert.Equal(t, uint64(3), restarted.CurrentTick())
+	assert.NilError(t, restarted.Tick(ctx))
+	assert.NilError(t, restarted.SubmitQueuedEpochs(ctx))
+	assert.Equal(t, 2, len(adapter.epochs))
+	assert.Equal(t, 1, len(adapter.epochs[0].Txs))
+	assert.Equal(t, 1, len(adapter.epochs[2].Txs))
+	liveTimestamps := maps.Clone(timestamps)
+
+	// A world recovered from the base shard ends up with the same state and timestamps.
+	recovered, _ := newWorld(ecb.NewMemoryKVStore())
+	assert.NilError(t, recovered.RecoverFromChain(ctx))
+	assert.Equal(t, uint64(3), recovered.CurrentTick())
+	for tick := uint64(0); tick < 3; tick++ {
+		assert.Equal(t, liveTimestamps[tick], timestamps[tick])
+	}
+	want, err := restarted.StateHash(2)
+	assert.NilError(t, err)
+	got, err := recovered.StateHash(2)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, want, got)
 }
@@ -31,7 +31,7 @@ func CreateMany(wCtx ecs.WorldContext, num int, components ...metadata.Component
 		}
 		acc = append(acc, c)
 	}
-	entityIds, err := world.StoreManager().CreateManyEntities(num, acc...)
+	entityIds, err := wCtx.StoreManager().CreateManyEntities(num, acc...)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +42,7 @@ func CreateMany(wCtx ecs.WorldContext, num int, components ...metadata.Component
 			if err != nil {
 				return nil, errors.New("must register component before creating an entity")
 			}
-			err = world.StoreManager().SetComponentForEntity(c, id, comp)
+			err = wCtx.StoreManager().SetComponentForEntity(c, id, comp)
 			if err != nil {
 				return nil, err
 			}
@@ -63,7 +63,7 @@ func RemoveComponentFrom[T metadata.Component](wCtx ecs.WorldContext, id entity.
 	if err != nil {
 		return errors.New("must register component")
 	}
-	return w.StoreManager().RemoveComponentFromEntity(c, id)
+	return wCtx.StoreManager().RemoveComponentFromEntity(c, id)
 }
 
 func AddComponentTo[T metadata.Component](wCtx ecs.WorldContext, id entity.ID) error {
@@ -77,7 +77,7 @@ func AddComponentTo[T metadata.Component](wCtx ecs.WorldContext, id entity.ID) e
 	if err != nil {
 		return errors.New("must register component")
 	}
-	return w.StoreManager().AddComponentToEntity(c, id)
+	return wCtx.StoreManager().AddComponentToEntity(c, id)
 }
 
 // GetComponent returns component data from the entity.
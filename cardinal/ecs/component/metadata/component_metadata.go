@@ -2,8 +2,10 @@ package metadata
 
 import (
 	"fmt"
-	"pkg.world.dev/world-engine/cardinal/ecs/codec"
 	"reflect"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/codec"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 )
 
 type (
@@ -21,14 +23,59 @@ type (
 		Encode(any) ([]byte, error)
 		Decode([]byte) (any, error)
 		Name() string
+
+		// AddIndex marks the given field of the component struct as indexed, so stores can find entities by the value
+		// of the field.
+		AddIndex(field string) error
+		// Indexes returns the indexed fields of the component struct.
+		Indexes() []string
+		// IndexKeys returns the keys of the given field of the given component value in the field's index. Slice and
+		// array fields have one key per element, and any other field has exactly one key. See IndexKey.
+		IndexKeys(value any, field string) ([]string, error)
 	}
 
 	Component interface {
 		// Name returns the name of the component.
 		Name() string
 	}
+
+	// Relation is a component that refers to another entity, e.g. an item that refers to the inventory it is in.
+	// Stores keep an index of the entities that refer to each entity through each relation, and only allow a
+	// relation to be set to an entity that exists.
+	Relation interface {
+		Component
+		// Target returns the entity this component refers to. ok is false if the component does not refer to any
+		// entity. This must be the case for the default value of the component, since entity IDs start at 0.
+		Target() (target entity.ID, ok bool)
+	}
 )
 
+// IsRelation returns true if the values of the given component type are Relations.
+func IsRelation(c ComponentMetadata) bool {
+	bz, err := c.New()
+	if err != nil {
+		return false
+	}
+	value, err := c.Decode(bz)
+	if err != nil {
+		return false
+	}
+	_, ok := value.(Relation)
+	return ok
+}
+
+// RelationTarget returns the entity the given value of a relation component refers to. Values may be either the
+// component struct or a pointer to it. hasTarget is false if the value does not refer to any entity, and isRelation is
+// false if the value is not a Relation.
+func RelationTarget(value any) (target entity.ID, hasTarget, isRelation bool) {
+	rel, ok := value.(Relation)
+	if !ok {
+		return 0, false, false
+	}
+	target, hasTarget = rel.Target()
+	return target, hasTarget, true
+}
+
 // NewComponentMetadata creates a new component type.
 // The function is used to create a new component of the type.
 func NewComponentMetadata[T Component](opts ...ComponentOption[T]) ComponentMetadata {
@@ -48,6 +95,7 @@ type componentMetadata[T any] struct {
 	typ        reflect.Type
 	name       string
 	defaultVal interface{}
+	indexes    []string
 }
 
 // SetID set's this component's ID. It must be unique across the world object.
@@ -181,3 +181,35 @@ func TestMultipleCallsToCreateSupported(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, 99, val.Val)
 }
+
+type Position struct {
+	X int `json:"x,omitempty"`
+}
+
+type ComponentWithFields struct {
+	Position
+	Velocity *Position        `json:"velocity"`
+	Tags     map[string]int   `json:"tags"`
+	Ignored  int              `json:"-"`
+	Items    []Position       `json:"items"`
+	Extra    any              `json:"extra,omitempty"`
+	Named    Position         `json:"named,omitempty"`
+	Nested   map[string]*bool `json:"nested"`
+}
+
+func (ComponentWithFields) Name() string { return "with_fields" }
+
+func TestHasJSONField(t *testing.T) {
+	c := metadata.NewComponentMetadata[ComponentWithFields]()
+	for _, path := range [][]string{
+		{"x"}, {"velocity", "x"}, {"tags", "anything"}, {"items"}, {"extra", "a", "b"}, {"named", "x"},
+		{"nested", "key"},
+	} {
+		assert.Check(t, metadata.HasJSONField(c, path), path)
+	}
+	for _, path := range [][]string{
+		{"X"}, {"Position"}, {"Ignored"}, {"velocity", "y"}, {"items", "x"}, {"named", "x", "y"}, {"missing"},
+	} {
+		assert.Check(t, !metadata.HasJSONField(c, path), path)
+	}
+}
@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// HasJSONField returns true if values of the given component can have a field at the given path of JSON names, e.g.
+// []string{"pos", "x"} for the x field of the pos field. The path cannot be checked past a map, an interface or a type
+// with its own JSON encoding, so any path into them is accepted.
+func HasJSONField(c ComponentMetadata, path []string) bool {
+	bz, err := c.New()
+	if err != nil {
+		return false
+	}
+	value, err := c.Decode(bz)
+	if err != nil {
+		return false
+	}
+	return hasJSONField(reflect.TypeOf(value), path)
+}
+
+func hasJSONField(t reflect.Type, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	if t == nil || t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return hasJSONField(t.Elem(), path)
+	case reflect.Map, reflect.Interface:
+		return true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				// The fields of an embedded struct without a JSON name are promoted.
+				if hasJSONField(field.Type, path) {
+					return true
+				}
+			} else if name == path[0] && hasJSONField(field.Type, path[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonFieldName returns the name encoding/json gives the given struct field. The name is empty for an embedded struct
+// whose fields are promoted, and false is returned if the field is not encoded.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tagName := ""
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		tagName, _, _ = strings.Cut(tag, ",")
+		if tagName == "-" {
+			return "", false
+		}
+	}
+	if tagName != "" {
+		return tagName, field.IsExported() || field.Anonymous
+	}
+	if field.Anonymous {
+		t := field.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct {
+			return "", true
+		}
+	}
+	return field.Name, field.IsExported()
+}
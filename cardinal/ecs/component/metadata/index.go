@@ -0,0 +1,95 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// IndexKey returns the key of the given field value in an index. Field values and search values with the same JSON
+// encoding have the same key, e.g. an int field with a value of 5 matches a search for int64(5).
+func IndexKey(value any) (string, error) {
+	bz, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
+// IndexedJSONField returns the indexed field of the given component that is encoded with the given JSON name. false is
+// returned if none of the component's indexed fields have that name.
+func IndexedJSONField(c ComponentMetadata, jsonName string) (string, bool) {
+	if len(c.Indexes()) == 0 {
+		return "", false
+	}
+	bz, err := c.New()
+	if err != nil {
+		return "", false
+	}
+	value, err := c.Decode(bz)
+	if err != nil {
+		return "", false
+	}
+	v := reflect.Indirect(reflect.ValueOf(value))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return "", false
+	}
+	for _, field := range c.Indexes() {
+		structField, ok := v.Type().FieldByName(field)
+		if !ok {
+			continue
+		}
+		if name, ok := jsonFieldName(structField); ok && name == jsonName {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+func (c *componentMetadata[T]) AddIndex(field string) error {
+	if c.typ == nil || c.typ.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot index field %q of component %s: only struct fields can be indexed", field, c.name)
+	}
+	structField, ok := c.typ.FieldByName(field)
+	if !ok || !structField.IsExported() {
+		return fmt.Errorf("cannot index field %q of component %s: no such exported field", field, c.name)
+	}
+	for _, index := range c.indexes {
+		if index == field {
+			return fmt.Errorf("field %q of component %s is already indexed", field, c.name)
+		}
+	}
+	c.indexes = append(c.indexes, field)
+	return nil
+}
+
+func (c *componentMetadata[T]) Indexes() []string {
+	return c.indexes
+}
+
+func (c *componentMetadata[T]) IndexKeys(value any, field string) ([]string, error) {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	if !v.IsValid() || v.Type() != c.typ {
+		return nil, fmt.Errorf("cannot index %T as component %s", value, c.name)
+	}
+	fieldValue := v.FieldByName(field)
+	if !fieldValue.IsValid() {
+		return nil, fmt.Errorf("component %s has no field %q", c.name, field)
+	}
+	if kind := fieldValue.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		key, err := IndexKey(fieldValue.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return []string{key}, nil
+	}
+	keys := make([]string, 0, fieldValue.Len())
+	for i := 0; i < fieldValue.Len(); i++ {
+		key, err := IndexKey(fieldValue.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
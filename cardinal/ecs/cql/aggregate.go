@@ -0,0 +1,207 @@
+package cql
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+)
+
+// Aggregate is an aggregate function in a SELECT clause. COUNT(*) counts entities, and COUNT(field) counts the
+// entities whose field is not null. SUM and AVG ignore values that are not numbers, and MIN and MAX ignore nulls.
+type Aggregate struct {
+	// Function is one of COUNT, SUM, MIN, MAX and AVG.
+	Function string
+	// field is nil for COUNT(*).
+	field *fieldRef
+}
+
+// AggregateResponse is a row of an aggregate query. Group is the values of the GROUP BY fields, and Values has one
+// value per aggregate in the SELECT clause. Aggregates that have no values to summarize, e.g. the SUM of zero
+// numbers, are null.
+type AggregateResponse struct {
+	Group  []any `json:"group,omitempty"`
+	Values []any `json:"values"`
+}
+
+// IsCount returns true if the query only counts the entities that match its component filter, so the entities do not
+// have to be visited one by one. A query with an OFFSET skips its only row, so it is not a plain count.
+func (q *Query) IsCount() bool {
+	return len(q.Aggregates) == 1 && q.Aggregates[0].Function == "COUNT" && q.Aggregates[0].field == nil &&
+		q.Where == nil && len(q.groupBy) == 0 && q.Offset == 0
+}
+
+// Aggregator computes the aggregates of a query over the entities that are added to it.
+type Aggregator struct {
+	query  *Query
+	groups map[string]*aggregateGroup
+}
+
+type aggregateGroup struct {
+	key    []any
+	states []aggregateState
+}
+
+type aggregateState struct {
+	count int
+	sum   *big.Rat
+	// best is the smallest or largest value so far for MIN and MAX.
+	best any
+}
+
+// NewAggregator returns an Aggregator for the query's aggregates.
+func (q *Query) NewAggregator() *Aggregator {
+	return &Aggregator{query: q, groups: map[string]*aggregateGroup{}}
+}
+
+// Add adds an entity that matches the query to its group. componentJSON returns the JSON encoded value of the given
+// component of the entity, or nil if the entity does not have the component.
+func (a *Aggregator) Add(componentJSON func(metadata.ComponentMetadata) (json.RawMessage, error)) error {
+	values := &componentValues{
+		componentJSON: componentJSON,
+		decoded:       map[metadata.TypeID]any{},
+	}
+	key := make([]any, 0, len(a.query.groupBy))
+	for _, field := range a.query.groupBy {
+		value, _, err := values.getJSON(field)
+		if err != nil {
+			return err
+		}
+		key = append(key, value)
+	}
+	groupID, err := groupKey(key)
+	if err != nil {
+		return err
+	}
+	group, ok := a.groups[groupID]
+	if !ok {
+		group = &aggregateGroup{key: key, states: make([]aggregateState, len(a.query.Aggregates))}
+		a.groups[groupID] = group
+	}
+	for i, aggregate := range a.query.Aggregates {
+		if aggregate.field == nil {
+			group.states[i].count++
+			continue
+		}
+		value, _, err := values.getJSON(*aggregate.field)
+		if err != nil {
+			return err
+		}
+		group.states[i].add(aggregate.Function, value)
+	}
+	return nil
+}
+
+func (s *aggregateState) add(function string, value any) {
+	if value == nil {
+		return
+	}
+	switch function {
+	case "COUNT":
+		s.count++
+	case "SUM", "AVG":
+		number, ok := value.(json.Number)
+		if !ok {
+			return
+		}
+		r, ok := new(big.Rat).SetString(number.String())
+		if !ok {
+			return
+		}
+		if s.sum == nil {
+			s.sum = new(big.Rat)
+		}
+		s.sum.Add(s.sum, r)
+		s.count++
+	case "MIN":
+		if s.best == nil || compareJSONValues(value, s.best) < 0 {
+			s.best = value
+		}
+	case "MAX":
+		if s.best == nil || compareJSONValues(value, s.best) > 0 {
+			s.best = value
+		}
+	}
+}
+
+func (s *aggregateState) result(function string) any {
+	switch function {
+	case "COUNT":
+		return s.count
+	case "SUM":
+		if s.sum == nil {
+			return nil
+		}
+		return ratToJSONNumber(s.sum)
+	case "AVG":
+		if s.count == 0 {
+			return nil
+		}
+		avg := new(big.Rat).Quo(s.sum, new(big.Rat).SetInt64(int64(s.count)))
+		f, _ := avg.Float64()
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return s.best
+}
+
+// Results returns one row per group, sorted by the GROUP BY values, and paginated by the query's LIMIT and OFFSET. A
+// query without GROUP BY has exactly one row, even if no entities were added.
+func (a *Aggregator) Results() []AggregateResponse {
+	if len(a.groups) == 0 && len(a.query.groupBy) == 0 {
+		a.groups[""] = &aggregateGroup{states: make([]aggregateState, len(a.query.Aggregates))}
+	}
+	groups := make([]*aggregateGroup, 0, len(a.groups))
+	for _, group := range a.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		for k := range groups[i].key {
+			if cmp := compareJSONValues(groups[i].key[k], groups[j].key[k]); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+	start := min(a.query.Offset, len(groups))
+	end := len(groups)
+	if a.query.Limit > 0 {
+		end = min(start+a.query.Limit, end)
+	}
+	rows := make([]AggregateResponse, 0, end-start)
+	for _, group := range groups[start:end] {
+		row := AggregateResponse{Group: group.key, Values: make([]any, 0, len(group.states))}
+		for i, aggregate := range a.query.Aggregates {
+			row.Values = append(row.Values, group.states[i].result(aggregate.Function))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// groupKey returns a string that is equal for equal GROUP BY values. Numbers are compared by value, so 1 and 1.0 are
+// in the same group.
+func groupKey(values []any) (string, error) {
+	canonical := make([]any, 0, len(values))
+	for _, value := range values {
+		if number, ok := value.(json.Number); ok {
+			if r, ok := new(big.Rat).SetString(number.String()); ok {
+				canonical = append(canonical, []any{"number", r.RatString()})
+				continue
+			}
+		}
+		canonical = append(canonical, []any{"value", value})
+	}
+	bz, err := json.Marshal(canonical)
+	return string(bz), err
+}
+
+// ratToJSONNumber formats integers exactly, and other numbers as the closest float64.
+func ratToJSONNumber(r *big.Rat) json.Number {
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+	f, _ := r.Float64()
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
 	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	"pkg.world.dev/world-engine/cardinal/ecs/filter"
@@ -19,7 +20,7 @@ const (
 	opOr
 )
 
-var operatorMap = map[string]cqlOperator{"&": opAnd, "|": opOr}
+var operatorMap = map[string]cqlOperator{"&": opAnd, "|": opOr, "AND": opAnd, "OR": opOr}
 
 // Capture basically tells the parser library how to transform a string token that's parsed into the operator type.
 func (o *cqlOperator) Capture(s []string) error {
@@ -71,6 +72,33 @@ type cqlTerm struct {
 	Right []*cqlOpFactor `@@*`
 }
 
+type cqlOrdering struct {
+	Field     *cqlField `@@`
+	Direction string    `@("ASC" | "DESC")?`
+}
+
+type cqlAggregate struct {
+	Function string    `@("COUNT" | "SUM" | "MIN" | "MAX" | "AVG") "("`
+	All      bool      `( @"*"`
+	Field    *cqlField `| @@ ) ")"`
+}
+
+type cqlSelectItem struct {
+	Aggregate *cqlAggregate `@@`
+	Component *cqlComponent `| @@`
+}
+
+type cqlQuery struct {
+	Count   *cqlTerm         `("COUNT" "(" @@ ")")?`
+	Select  []*cqlSelectItem `("SELECT" @@ ("," @@)* "FROM")?`
+	Filter  *cqlTerm         `@@?`
+	Where   *cqlCondition    `("WHERE" @@)?`
+	GroupBy []*cqlField      `("GROUP" "BY" @@ ("," @@)*)?`
+	OrderBy []*cqlOrdering   `("ORDER" "BY" @@ ("," @@)*)?`
+	Limit   *int             `("LIMIT" @Number)?`
+	Offset  *int             `("OFFSET" @Number)?`
+}
+
 // Display
 
 func (o cqlOperator) String() string {
@@ -137,7 +165,20 @@ func (t *cqlTerm) String() string {
 	return strings.Join(out, " ")
 }
 
-var internalCQLParser = participle.MustBuild[cqlTerm]()
+var cqlLexer = lexer.MustSimple([]lexer.SimpleRule{
+	{Name: "Whitespace", Pattern: `\s+`},
+	{Name: "Number", Pattern: `[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?`},
+	{Name: "String", Pattern: `"(\\.|[^"\\])*"|'(\\.|[^'\\])*'`},
+	{Name: "Ident", Pattern: `[\p{L}_][\p{L}\p{N}_]*`},
+	{Name: "Operator", Pattern: `<=|>=|!=|<>|[!&|()=<>,.*]`},
+})
+
+var (
+	internalCQLParser = participle.MustBuild[cqlTerm](
+		participle.Lexer(cqlLexer), participle.Elide("Whitespace"), participle.Unquote("String"))
+	internalCQLQueryParser = participle.MustBuild[cqlQuery](
+		participle.Lexer(cqlLexer), participle.Elide("Whitespace"), participle.Unquote("String"))
+)
 
 // TODO: Value is sum type is represented as a product type. There is a case where multiple properties are filled out.
 // Only one property may not be nil, The parser should prevent this from happening but for safety this should eventually
@@ -227,17 +268,150 @@ func termToComponentFilter(term *cqlTerm, stringToComponent func(string) (metada
 	return acc, nil
 }
 
+// Parse parses a CQL expression that only filters entities by their components. Use ParseQuery for expressions that
+// have a WHERE clause.
 func Parse(cqlText string, stringToComponent func(string) (metadata.ComponentMetadata, error),
 ) (filter.ComponentFilter, error) {
-	term, err := internalCQLParser.ParseString("", cqlText)
+	query, err := ParseQuery(cqlText, stringToComponent)
+	if err != nil {
+		return nil, err
+	}
+	if query.Where != nil || query.Select != nil || query.Aggregates != nil || query.OrderBy != nil ||
+		query.Limit != 0 || query.Offset != 0 {
+		return nil, errors.New("only component filters are supported here")
+	}
+	return query.Filter, nil
+}
+
+// Query is a parsed CQL query. It filters entities by their components, and then by the values of those components.
+// The matching entities can be sorted and paginated, or summarized by aggregate functions.
+type Query struct {
+	// Select is the components whose values are returned for each entity, or nil if every component is returned.
+	Select []metadata.ComponentMetadata
+	// Aggregates is the aggregate functions in the SELECT clause. If there are any, the query returns one row per
+	// group instead of the matching entities. See Aggregator.
+	Aggregates []Aggregate
+	Filter     filter.ComponentFilter
+	// Where is nil if the query does not have a WHERE clause.
+	Where   *Condition
+	groupBy []fieldRef
+	OrderBy []Ordering
+	// Limit is the maximum number of entities or groups in a page, or 0 if there is no limit.
+	Limit  int
+	Offset int
+
+	text string
+}
+
+// Ordering is a field in an ORDER BY clause.
+type Ordering struct {
+	field      fieldRef
+	Descending bool
+}
+
+// ParseQuery parses a CQL query, e.g.
+//
+//	SELECT Health, Position FROM CONTAINS(Health) WHERE Health.hp < 10 ORDER BY Health.hp DESC LIMIT 50 OFFSET 100
+//	SELECT COUNT(*), AVG(Player.level) FROM CONTAINS(Player) GROUP BY Player.team
+//
+// Everything but the component filter is optional. COUNT(filter) is short for SELECT COUNT(*) FROM filter.
+//
+//nolint:gocognit // its a list of clauses.
+func ParseQuery(cqlText string, stringToComponent func(string) (metadata.ComponentMetadata, error),
+) (*Query, error) {
+	parsed, err := internalCQLQueryParser.ParseString("", cqlText)
 	if err != nil {
 		return nil, err
 	}
-	resultFilter, err := termToComponentFilter(term, stringToComponent)
+	if parsed.Count != nil {
+		if parsed.Select != nil || parsed.Filter != nil {
+			return nil, errors.New("COUNT(filter) cannot have a SELECT clause or another filter")
+		}
+		parsed.Filter = parsed.Count
+		parsed.Select = []*cqlSelectItem{{Aggregate: &cqlAggregate{Function: "COUNT", All: true}}}
+	}
+	if parsed.Filter == nil {
+		return nil, errors.New("a component filter is required")
+	}
+	resultFilter, err := termToComponentFilter(parsed.Filter, stringToComponent)
 	if err != nil {
 		return nil, err
 	}
-	return resultFilter, nil
+	query := &Query{Filter: resultFilter, text: cqlText}
+	if err = query.setSelect(parsed.Select, parsed.GroupBy, stringToComponent); err != nil {
+		return nil, err
+	}
+	if parsed.Where != nil {
+		root, err := conditionToPredicate(parsed.Where, stringToComponent)
+		if err != nil {
+			return nil, err
+		}
+		query.Where = &Condition{root: root}
+	}
+	if query.Aggregates != nil && parsed.OrderBy != nil {
+		return nil, errors.New("ORDER BY cannot be used with aggregates, groups are sorted by their GROUP BY values")
+	}
+	for _, ordering := range parsed.OrderBy {
+		field, err := toFieldRef(ordering.Field, stringToComponent)
+		if err != nil {
+			return nil, err
+		}
+		query.OrderBy = append(query.OrderBy, Ordering{field: field, Descending: ordering.Direction == "DESC"})
+	}
+	if parsed.Limit != nil {
+		if *parsed.Limit <= 0 {
+			return nil, errors.New("LIMIT must be positive")
+		}
+		query.Limit = *parsed.Limit
+	}
+	if parsed.Offset != nil {
+		if *parsed.Offset < 0 {
+			return nil, errors.New("OFFSET cannot be negative")
+		}
+		query.Offset = *parsed.Offset
+	}
+	return query, nil
+}
+
+// setSelect sets the components or aggregates the query selects, and the fields the aggregates are grouped by.
+func (q *Query) setSelect(items []*cqlSelectItem, groupBy []*cqlField,
+	stringToComponent func(string) (metadata.ComponentMetadata, error),
+) error {
+	for _, item := range items {
+		if item.Component != nil {
+			comp, err := stringToComponent(item.Component.Name)
+			if err != nil {
+				return err
+			}
+			q.Select = append(q.Select, comp)
+			continue
+		}
+		aggregate := Aggregate{Function: item.Aggregate.Function}
+		if item.Aggregate.Field != nil {
+			field, err := toFieldRef(item.Aggregate.Field, stringToComponent)
+			if err != nil {
+				return err
+			}
+			aggregate.field = &field
+		} else if aggregate.Function != "COUNT" {
+			return fmt.Errorf("%s needs a field", aggregate.Function)
+		}
+		q.Aggregates = append(q.Aggregates, aggregate)
+	}
+	if q.Select != nil && q.Aggregates != nil {
+		return errors.New("components and aggregates cannot be selected together")
+	}
+	if groupBy != nil && q.Aggregates == nil {
+		return errors.New("GROUP BY needs an aggregate in the SELECT clause")
+	}
+	for _, field := range groupBy {
+		ref, err := toFieldRef(field, stringToComponent)
+		if err != nil {
+			return err
+		}
+		q.groupBy = append(q.groupBy, ref)
+	}
+	return nil
 }
 
 type QueryRequest struct {
@@ -1,12 +1,16 @@
 package cql
 
 import (
+	"encoding/json"
+	"math/rand"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"gotest.tools/v3/assert"
 
 	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	"pkg.world.dev/world-engine/cardinal/ecs/filter"
 )
 
@@ -94,3 +98,197 @@ func TestParser(t *testing.T) {
 		)
 	assert.Assert(t, reflect.DeepEqual(testResult2, result))
 }
+
+type Health struct {
+	HP    int `json:"hp"`
+	Class string
+	Stats struct {
+		Armor float64 `json:"armor"`
+	} `json:"stats"`
+}
+
+func (Health) Name() string { return "Health" }
+
+func TestWhereClauses(t *testing.T) {
+	health := metadata.NewComponentMetadata[Health]()
+	assert.NilError(t, health.SetID(1))
+	assert.NilError(t, health.AddIndex("Class"))
+	assert.NilError(t, health.AddIndex("HP"))
+	empty := metadata.NewComponentMetadata[EmptyComponent]()
+	assert.NilError(t, empty.SetID(2))
+	stringToComponent := func(name string) (metadata.ComponentMetadata, error) {
+		if name == health.Name() {
+			return health, nil
+		}
+		return empty, nil
+	}
+	entity := func(value string) func(metadata.ComponentMetadata) (json.RawMessage, error) {
+		return func(c metadata.ComponentMetadata) (json.RawMessage, error) {
+			if c.ID() != health.ID() {
+				return nil, nil
+			}
+			return json.RawMessage(value), nil
+		}
+	}
+	weak := entity(`{"hp": 5, "Class": "mage", "stats": {"armor": 1.5}}`)
+	strong := entity(`{"hp": 100, "Class": "knight", "stats": {"armor": 30}}`)
+
+	for _, tc := range []struct {
+		where        string
+		weak, strong bool
+	}{
+		{where: "Health.hp < 10", weak: true},
+		{where: "Health.hp >= 5 AND Health.hp <= 100", weak: true, strong: true},
+		{where: "Health.hp BETWEEN 6 AND 100", strong: true},
+		{where: "Health.stats.armor > 1.25 AND Health.stats.armor < 2", weak: true},
+		{where: `Health.Class = "knight" OR Health.Class = 'mage' AND Health.hp > 10`, strong: true},
+		{where: `(Health.Class = "knight" OR Health.Class = 'mage') AND Health.hp > 10`, strong: true},
+		{where: `NOT Health.Class = "knight"`, weak: true},
+		{where: `Health.Class != 5`, weak: true, strong: true},
+	} {
+		query, err := ParseQuery("CONTAINS(Health) WHERE "+tc.where, stringToComponent)
+		assert.NilError(t, err, tc.where)
+		ok, err := query.Where.Matches(weak)
+		assert.NilError(t, err)
+		assert.Equal(t, tc.weak, ok, tc.where)
+		ok, err = query.Where.Matches(strong)
+		assert.NilError(t, err)
+		assert.Equal(t, tc.strong, ok, tc.where)
+	}
+
+	// Only equality predicates that every match must satisfy can be looked up in an index.
+	query, err := ParseQuery(`CONTAINS(Health) WHERE Health.Class = "mage" AND Health.hp = 5.0 AND Health.hp < 7`,
+		stringToComponent)
+	assert.NilError(t, err)
+	assert.Assert(t, reflect.DeepEqual([]IndexLookup{
+		{Component: health, Field: "Class", Value: json.RawMessage(`"mage"`)},
+		{Component: health, Field: "HP", Value: json.RawMessage(`5`)},
+	}, query.Where.IndexLookups()))
+	query, err = ParseQuery(`CONTAINS(Health) WHERE Health.Class = "mage" OR Health.hp = 5`, stringToComponent)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(query.Where.IndexLookups()))
+
+	_, err = ParseQuery("CONTAINS(Health) WHERE Health.hp < false", stringToComponent)
+	assert.ErrorContains(t, err, "can only compare")
+	for _, missing := range []string{"Health.missing = 1", "Health.hp.value = 1", "Health.Stats.armor = 1",
+		"emptyComponent.hp = 5"} {
+		_, err = ParseQuery("CONTAINS(Health) WHERE "+missing, stringToComponent)
+		assert.ErrorContains(t, err, "has no field", missing)
+	}
+	_, err = ParseQuery("CONTAINS(Health) WHERE Health.hp BETWEEN 1 AND 'z'", stringToComponent)
+	assert.ErrorContains(t, err, "BETWEEN")
+	_, err = Parse("CONTAINS(Health) WHERE Health.hp = 1", stringToComponent)
+	assert.ErrorContains(t, err, "only component filters")
+}
+
+func TestSelectOrderByAndLimit(t *testing.T) {
+	health := metadata.NewComponentMetadata[Health]()
+	stringToComponent := func(name string) (metadata.ComponentMetadata, error) {
+		return health, nil
+	}
+	query, err := ParseQuery("SELECT Health FROM CONTAINS(Health) ORDER BY Health.Class, Health.hp DESC LIMIT 2 OFFSET 1",
+		stringToComponent)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(query.Select))
+	assert.Equal(t, 2, query.Limit)
+	assert.Equal(t, 1, query.Offset)
+	assert.Assert(t, !query.OrderBy[0].Descending && query.OrderBy[1].Descending)
+
+	_, err = ParseQuery("CONTAINS(Health) LIMIT 0", stringToComponent)
+	assert.ErrorContains(t, err, "LIMIT")
+	_, err = Parse("CONTAINS(Health) LIMIT 1", stringToComponent)
+	assert.ErrorContains(t, err, "only component filters")
+	_, _, err = query.Page(nil, "not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestPagerKeepsOnlyThePageButMatchesAFullSort(t *testing.T) {
+	health := metadata.NewComponentMetadata[Health]()
+	stringToComponent := func(name string) (metadata.ComponentMetadata, error) {
+		return health, nil
+	}
+	query, err := ParseQuery("SELECT Health FROM CONTAINS(Health) ORDER BY Health.hp DESC LIMIT 3 OFFSET 2",
+		stringToComponent)
+	assert.NilError(t, err)
+	r := rand.New(rand.NewSource(1))
+	var matches []Match
+	for _, id := range r.Perm(20) {
+		matches = append(matches, Match{ID: entity.ID(id), SortKey: []any{json.Number(strconv.Itoa(id % 4))}})
+	}
+	// The same page, in the order a full sort puts it: highest hp first, then lowest ID.
+	want := []entity.ID{11, 15, 19}
+
+	pager, err := query.NewPager("")
+	assert.NilError(t, err)
+	for _, match := range matches {
+		pager.Add(match)
+	}
+	// One more match than OFFSET + LIMIT is kept, to know whether there is a next page.
+	assert.Equal(t, 6, pager.matches.Len())
+	assert.Assert(t, pager.IsFull())
+	page, cursor, err := pager.Page()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, want, matchIDs(page))
+
+	// Every page that follows a cursor has the next matches, until there are none left.
+	var all []entity.ID
+	cursor = ""
+	for {
+		page, cursor, err = query.Page(matches, cursor)
+		assert.NilError(t, err)
+		all = append(all, matchIDs(page)...)
+		if cursor == "" {
+			break
+		}
+	}
+	assert.DeepEqual(t, []entity.ID{3, 7, 11, 15, 19, 2, 6, 10, 14, 18, 1, 5, 9, 13, 17, 0, 4, 8, 12, 16}[2:], all)
+}
+
+func matchIDs(matches []Match) []entity.ID {
+	ids := make([]entity.ID, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+	return ids
+}
+
+func TestAggregates(t *testing.T) {
+	health := metadata.NewComponentMetadata[Health]()
+	stringToComponent := func(name string) (metadata.ComponentMetadata, error) {
+		return health, nil
+	}
+	query, err := ParseQuery("SELECT COUNT(*), COUNT(Health.Class), MIN(Health.Class) FROM CONTAINS(Health) "+
+		"GROUP BY Health.hp", stringToComponent)
+	assert.NilError(t, err)
+	aggregator := query.NewAggregator()
+	for _, value := range []string{`{"hp": 1, "Class": "b"}`, `{"hp": 1.0, "Class": "a"}`, `{"hp": "1"}`} {
+		assert.NilError(t, aggregator.Add(func(metadata.ComponentMetadata) (json.RawMessage, error) {
+			return json.RawMessage(value), nil
+		}))
+	}
+	// Numbers are grouped by value, and numbers sort before strings.
+	assert.Assert(t, reflect.DeepEqual([]AggregateResponse{
+		{Group: []any{json.Number("1")}, Values: []any{2, 2, "a"}},
+		{Group: []any{"1"}, Values: []any{1, 0, nil}},
+	}, aggregator.Results()))
+
+	for _, invalid := range []string{
+		"SELECT SUM(*) FROM CONTAINS(Health)",
+		"SELECT Health, COUNT(*) FROM CONTAINS(Health)",
+		"SELECT Health FROM CONTAINS(Health) GROUP BY Health.hp",
+		"SELECT COUNT(*) FROM CONTAINS(Health) ORDER BY Health.hp",
+		"COUNT(CONTAINS(Health)) CONTAINS(Health)",
+		"SELECT SUM(Health.missing) FROM CONTAINS(Health)",
+		"SELECT COUNT(*) FROM CONTAINS(Health) GROUP BY Health.missing",
+	} {
+		_, err = ParseQuery(invalid, stringToComponent)
+		assert.Check(t, err != nil, invalid)
+	}
+	query, err = ParseQuery("COUNT(CONTAINS(Health))", stringToComponent)
+	assert.NilError(t, err)
+	assert.Check(t, query.IsCount())
+	query, err = ParseQuery("COUNT(CONTAINS(Health)) OFFSET 1", stringToComponent)
+	assert.NilError(t, err)
+	assert.Check(t, !query.IsCount())
+	assert.Equal(t, 0, len(query.NewAggregator().Results()))
+}
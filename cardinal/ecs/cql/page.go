@@ -0,0 +1,256 @@
+package cql
+
+import (
+	"bytes"
+	"container/heap"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Match is an entity that matches a query, along with the values of the query's ORDER BY fields.
+type Match struct {
+	ID      entity.ID
+	SortKey []any
+}
+
+// cursor marks the last entity of a page. The next page starts after it.
+type cursor struct {
+	Query   string    `json:"query"`
+	SortKey []any     `json:"sortKey"`
+	ID      entity.ID `json:"id"`
+}
+
+// SortKey returns the values of the query's ORDER BY fields for an entity. componentJSON returns the JSON encoded value
+// of the given component of the entity, or nil if the entity does not have the component.
+func (q *Query) SortKey(componentJSON func(metadata.ComponentMetadata) (json.RawMessage, error)) ([]any, error) {
+	if len(q.OrderBy) == 0 {
+		return nil, nil
+	}
+	values := &componentValues{
+		componentJSON: componentJSON,
+		decoded:       map[metadata.TypeID]any{},
+	}
+	key := make([]any, 0, len(q.OrderBy))
+	for _, ordering := range q.OrderBy {
+		value, _, err := values.getJSON(ordering.field)
+		if err != nil {
+			return nil, err
+		}
+		key = append(key, value)
+	}
+	return key, nil
+}
+
+// Page sorts the given matches and returns the page that the query's LIMIT and OFFSET select. Matches are sorted by
+// the ORDER BY fields and then by entity ID. Entities that do not have a field sort as if the field were null, and
+// values of different types sort in the order null, numbers, strings, booleans, then objects and arrays.
+//
+// If cursor is not empty, the page starts after the entity the cursor marks instead of at OFFSET. nextCursor is empty
+// if there are no more matches after the page.
+func (q *Query) Page(matches []Match, cursor string) (page []Match, nextCursor string, err error) {
+	pager, err := q.NewPager(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, match := range matches {
+		pager.Add(match)
+	}
+	return pager.Page()
+}
+
+// Pager selects the page of a query (see Query.Page) from matches that are added one at a time. If the query has a
+// LIMIT, only the matches that can still be on the page are kept, in a heap, so adding n matches costs O(n log k)
+// where k is OFFSET + LIMIT.
+type Pager struct {
+	q     *Query
+	after *Match
+	// size is the number of matches that are kept, or 0 to keep every match. One more match than the page needs is
+	// kept, so it is known whether there is a next page.
+	size    int
+	matches matchHeap
+}
+
+// NewPager returns a Pager for the page of the query that starts after the entity the cursor marks, or at OFFSET if
+// the cursor is empty.
+func (q *Query) NewPager(cursor string) (*Pager, error) {
+	p := &Pager{q: q, matches: matchHeap{q: q}}
+	start := q.Offset
+	if cursor != "" {
+		after, err := q.decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		p.after = &after
+		start = 0
+	}
+	if q.Limit > 0 {
+		p.size = start + q.Limit + 1
+	}
+	return p, nil
+}
+
+// IsBeforeCursor reports whether the given match sorts at or before the entity the cursor marks, so it can never be on
+// the page.
+func (p *Pager) IsBeforeCursor(match Match) bool {
+	return p.after != nil && p.q.compareMatches(match, *p.after) <= 0
+}
+
+// Add adds a match of the query.
+func (p *Pager) Add(match Match) {
+	if p.IsBeforeCursor(match) {
+		return
+	}
+	if p.size > 0 && len(p.matches.matches) == p.size {
+		if p.q.compareMatches(match, p.matches.matches[0]) >= 0 {
+			return
+		}
+		p.matches.matches[0] = match
+		heap.Fix(&p.matches, 0)
+		return
+	}
+	heap.Push(&p.matches, match)
+}
+
+// IsFull reports whether every match that can be on the page has been found, if matches are added in the order they
+// sort in. Callers that add matches in that order (e.g. queries without ORDER BY, in order of entity ID) can stop once
+// the pager is full.
+func (p *Pager) IsFull() bool {
+	return p.size > 0 && len(p.matches.matches) == p.size
+}
+
+// Page returns the page of the matches that were added, and the cursor of the next page.
+func (p *Pager) Page() (page []Match, nextCursor string, err error) {
+	matches := p.matches.matches
+	sort.Slice(matches, func(i, j int) bool {
+		return p.q.compareMatches(matches[i], matches[j]) < 0
+	})
+	start := 0
+	if p.after == nil {
+		start = min(p.q.Offset, len(matches))
+	}
+	end := len(matches)
+	if p.q.Limit > 0 && start+p.q.Limit < end {
+		end = start + p.q.Limit
+		if nextCursor, err = p.q.encodeCursor(matches[end-1]); err != nil {
+			return nil, "", err
+		}
+	}
+	return matches[start:end], nextCursor, nil
+}
+
+// matchHeap is a max-heap of matches in the query's sort order, so the last match of the page is at the top.
+type matchHeap struct {
+	q       *Query
+	matches []Match
+}
+
+func (h *matchHeap) Len() int { return len(h.matches) }
+
+func (h *matchHeap) Less(i, j int) bool { return h.q.compareMatches(h.matches[i], h.matches[j]) > 0 }
+
+func (h *matchHeap) Swap(i, j int) { h.matches[i], h.matches[j] = h.matches[j], h.matches[i] }
+
+func (h *matchHeap) Push(x any) {
+	match, _ := x.(Match)
+	h.matches = append(h.matches, match)
+}
+
+func (h *matchHeap) Pop() any {
+	last := h.matches[len(h.matches)-1]
+	h.matches = h.matches[:len(h.matches)-1]
+	return last
+}
+
+func (q *Query) compareMatches(a, b Match) int {
+	for i, ordering := range q.OrderBy {
+		cmp := compareJSONValues(a.SortKey[i], b.SortKey[i])
+		if ordering.Descending {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	switch {
+	case a.ID < b.ID:
+		return -1
+	case a.ID > b.ID:
+		return 1
+	}
+	return 0
+}
+
+// compareJSONValues compares two values decoded from JSON with json.Number numbers.
+func compareJSONValues(a, b any) int {
+	left, _, errLeft := literalFromJSON(a)
+	right, _, errRight := literalFromJSON(b)
+	if errLeft != nil || errRight != nil || left.kind == kindOther && right.kind == kindOther {
+		leftJSON, _ := json.Marshal(a)
+		rightJSON, _ := json.Marshal(b)
+		return bytes.Compare(leftJSON, rightJSON)
+	}
+	if left.kind != right.kind {
+		return int(left.kind) - int(right.kind)
+	}
+	switch left.kind {
+	case kindNumber:
+		return left.number.Cmp(right.number)
+	case kindString:
+		return strings.Compare(left.str, right.str)
+	case kindBool:
+		switch {
+		case left.boolean == right.boolean:
+			return 0
+		case left.boolean:
+			return 1
+		}
+		return -1
+	case kindNull, kindOther:
+	}
+	return 0
+}
+
+// queryHash identifies the query a cursor belongs to.
+func (q *Query) queryHash() string {
+	sum := sha256.Sum256([]byte(q.text))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (q *Query) encodeCursor(last Match) (string, error) {
+	bz, err := json.Marshal(cursor{Query: q.queryHash(), SortKey: last.SortKey, ID: last.ID})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bz), nil
+}
+
+func (q *Query) decodeCursor(text string) (Match, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return Match{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bz))
+	decoder.UseNumber()
+	var c cursor
+	if err = decoder.Decode(&c); err != nil {
+		return Match{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if c.Query != q.queryHash() {
+		return Match{}, fmt.Errorf("%w: the cursor belongs to a different query", ErrInvalidCursor)
+	}
+	if len(c.SortKey) != len(q.OrderBy) {
+		return Match{}, fmt.Errorf("%w: the cursor does not match the ORDER BY clause", ErrInvalidCursor)
+	}
+	return Match{ID: c.ID, SortKey: c.SortKey}, nil
+}
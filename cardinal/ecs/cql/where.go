@@ -0,0 +1,438 @@
+package cql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+)
+
+type cqlField struct {
+	Component string   `@Ident`
+	Path      []string `("." @Ident)+`
+}
+
+type cqlLiteral struct {
+	Number *string `@Number`
+	String *string `| @String`
+	Bool   *string `| @("true" | "false")`
+	Null   bool    `| @"null"`
+}
+
+type cqlComparison struct {
+	Operator string      `@("=" | "!=" | "<>" | "<=" | ">=" | "<" | ">")`
+	Value    *cqlLiteral `@@`
+}
+
+type cqlBetween struct {
+	Low  *cqlLiteral `"BETWEEN" @@`
+	High *cqlLiteral `"AND" @@`
+}
+
+type cqlPredicate struct {
+	Field      *cqlField      `@@`
+	Between    *cqlBetween    `( @@`
+	Comparison *cqlComparison `| @@ )`
+}
+
+type cqlConditionFactor struct {
+	Not          *cqlConditionFactor `"NOT" @@`
+	Subcondition *cqlCondition       `| "(" @@ ")"`
+	Predicate    *cqlPredicate       `| @@`
+}
+
+type cqlOpCondition struct {
+	Operator  cqlOperator         `@("AND" | "OR")`
+	Condition *cqlConditionFactor `@@`
+}
+
+type cqlCondition struct {
+	Left  *cqlConditionFactor `@@`
+	Right []*cqlOpCondition   `@@*`
+}
+
+// Condition is a parsed WHERE clause. Predicates compare a field of a component to a literal, e.g. Health.hp < 10.
+// Fields are named by their JSON names, and nested fields are separated by dots. A field the component does not have
+// is a parse error. A predicate on a component the entity does not have, or on a field that is missing from the
+// entity's value, e.g. inside a map, is false. Values of different types are never equal.
+type Condition struct {
+	root predicate
+}
+
+// Matches returns true if an entity matches the condition. componentJSON returns the JSON encoded value of the given
+// component of the entity, or nil if the entity does not have the component.
+func (c *Condition) Matches(componentJSON func(metadata.ComponentMetadata) (json.RawMessage, error)) (bool, error) {
+	values := &componentValues{
+		componentJSON: componentJSON,
+		decoded:       map[metadata.TypeID]any{},
+	}
+	return c.root.matches(values)
+}
+
+// IndexLookup is an equality predicate on an indexed field that every matching entity must satisfy. Value is the JSON
+// encoding of the literal the field is compared to.
+type IndexLookup struct {
+	Component metadata.ComponentMetadata
+	Field     string
+	Value     json.RawMessage
+}
+
+// IndexLookups returns the equality predicates on indexed fields that every entity that matches the condition must
+// satisfy, so the candidates can be found in the indexes instead of checking every entity. Entities found this way
+// must still be checked with Matches.
+func (c *Condition) IndexLookups() []IndexLookup {
+	conjuncts := []predicate{c.root}
+	if and, ok := c.root.(andPredicate); ok {
+		conjuncts = and
+	}
+	var lookups []IndexLookup
+	for _, conjunct := range conjuncts {
+		cmp, ok := conjunct.(*comparison)
+		if !ok || cmp.operator != "=" || len(cmp.field.path) != 1 || cmp.value.kind == kindNull {
+			continue
+		}
+		field, ok := metadata.IndexedJSONField(cmp.field.component, cmp.field.path[0])
+		if !ok {
+			continue
+		}
+		value, err := cmp.value.indexValue()
+		if err != nil {
+			continue
+		}
+		lookups = append(lookups, IndexLookup{Component: cmp.field.component, Field: field, Value: value})
+	}
+	return lookups
+}
+
+type predicate interface {
+	matches(values *componentValues) (bool, error)
+}
+
+type andPredicate []predicate
+
+func (p andPredicate) matches(values *componentValues) (bool, error) {
+	for _, sub := range p {
+		ok, err := sub.matches(values)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+type orPredicate []predicate
+
+func (p orPredicate) matches(values *componentValues) (bool, error) {
+	for _, sub := range p {
+		ok, err := sub.matches(values)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+type notPredicate struct {
+	sub predicate
+}
+
+func (p notPredicate) matches(values *componentValues) (bool, error) {
+	ok, err := p.sub.matches(values)
+	return !ok, err
+}
+
+type fieldRef struct {
+	component metadata.ComponentMetadata
+	path      []string
+}
+
+type comparison struct {
+	field    fieldRef
+	operator string
+	value    literal
+}
+
+func (p *comparison) matches(values *componentValues) (bool, error) {
+	fieldValue, ok, err := values.get(p.field)
+	if err != nil || !ok {
+		return false, err
+	}
+	if fieldValue.kind != p.value.kind {
+		return p.operator == "!=" || p.operator == "<>", nil
+	}
+	cmp, ordered := fieldValue.compare(p.value)
+	switch p.operator {
+	case "=":
+		return cmp == 0, nil
+	case "!=", "<>":
+		return cmp != 0, nil
+	case "<":
+		return ordered && cmp < 0, nil
+	case "<=":
+		return ordered && cmp <= 0, nil
+	case ">":
+		return ordered && cmp > 0, nil
+	case ">=":
+		return ordered && cmp >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", p.operator)
+}
+
+type between struct {
+	field     fieldRef
+	low, high literal
+}
+
+func (p *between) matches(values *componentValues) (bool, error) {
+	fieldValue, ok, err := values.get(p.field)
+	if err != nil || !ok || fieldValue.kind != p.low.kind {
+		return false, err
+	}
+	low, _ := fieldValue.compare(p.low)
+	high, _ := fieldValue.compare(p.high)
+	return low >= 0 && high <= 0, nil
+}
+
+// componentValues decodes the components of a single entity the first time a predicate needs them.
+type componentValues struct {
+	componentJSON func(metadata.ComponentMetadata) (json.RawMessage, error)
+	decoded       map[metadata.TypeID]any
+}
+
+// get returns the value of the given field. false is returned if the entity does not have the component or the field.
+func (v *componentValues) get(field fieldRef) (literal, bool, error) {
+	value, ok, err := v.getJSON(field)
+	if err != nil || !ok {
+		return literal{}, false, err
+	}
+	return literalFromJSON(value)
+}
+
+// getJSON returns the decoded JSON value of the given field. Numbers are decoded as json.Number. false is returned if
+// the entity does not have the component or the field.
+func (v *componentValues) getJSON(field fieldRef) (any, bool, error) {
+	value, ok := v.decoded[field.component.ID()]
+	if !ok {
+		bz, err := v.componentJSON(field.component)
+		if err != nil {
+			return nil, false, err
+		}
+		if bz != nil {
+			decoder := json.NewDecoder(bytes.NewReader(bz))
+			decoder.UseNumber()
+			if err = decoder.Decode(&value); err != nil {
+				return nil, false, err
+			}
+		}
+		v.decoded[field.component.ID()] = value
+	}
+	if value == nil {
+		return nil, false, nil
+	}
+	for _, name := range field.path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false, nil
+		}
+		if value, ok = object[name]; !ok {
+			return nil, false, nil
+		}
+	}
+	return value, true, nil
+}
+
+type literalKind int
+
+const (
+	kindNull literalKind = iota
+	kindNumber
+	kindString
+	kindBool
+	// kindOther is an object or an array, which cannot be compared to a literal.
+	kindOther
+)
+
+type literal struct {
+	kind    literalKind
+	number  *big.Rat
+	str     string
+	boolean bool
+}
+
+func literalFromJSON(value any) (literal, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return literal{kind: kindNull}, true, nil
+	case json.Number:
+		number, ok := new(big.Rat).SetString(v.String())
+		if !ok {
+			return literal{}, false, fmt.Errorf("invalid number %q", v)
+		}
+		return literal{kind: kindNumber, number: number}, true, nil
+	case string:
+		return literal{kind: kindString, str: v}, true, nil
+	case bool:
+		return literal{kind: kindBool, boolean: v}, true, nil
+	}
+	return literal{kind: kindOther}, true, nil
+}
+
+// compare compares two literals of the same kind. false is returned if the kind has no order.
+func (l literal) compare(other literal) (int, bool) {
+	switch l.kind {
+	case kindNumber:
+		return l.number.Cmp(other.number), true
+	case kindString:
+		return strings.Compare(l.str, other.str), true
+	case kindBool:
+		if l.boolean == other.boolean {
+			return 0, false
+		}
+		return 1, false
+	case kindNull:
+		return 0, false
+	case kindOther:
+	}
+	return 1, false
+}
+
+// maxExactInteger is the smallest integer that encoding/json formats with an exponent.
+var maxExactInteger = new(big.Rat).SetFloat64(1e21)
+
+// indexValue returns the JSON encoding that a field with the same value has in an index.
+func (l literal) indexValue() (json.RawMessage, error) {
+	switch l.kind {
+	case kindNumber:
+		if l.number.IsInt() && new(big.Rat).Abs(l.number).Cmp(maxExactInteger) < 0 {
+			return json.RawMessage(l.number.Num().String()), nil
+		}
+		f, _ := l.number.Float64()
+		return json.Marshal(f)
+	case kindString:
+		return json.Marshal(l.str)
+	case kindBool:
+		return json.Marshal(l.boolean)
+	case kindNull, kindOther:
+	}
+	return nil, errors.New("literal cannot be looked up in an index")
+}
+
+func toLiteral(value *cqlLiteral) (literal, error) {
+	switch {
+	case value.Number != nil:
+		number, ok := new(big.Rat).SetString(*value.Number)
+		if !ok {
+			return literal{}, fmt.Errorf("invalid number %q", *value.Number)
+		}
+		return literal{kind: kindNumber, number: number}, nil
+	case value.String != nil:
+		return literal{kind: kindString, str: *value.String}, nil
+	case value.Bool != nil:
+		return literal{kind: kindBool, boolean: *value.Bool == "true"}, nil
+	case value.Null:
+		return literal{kind: kindNull}, nil
+	}
+	return literal{}, errors.New("unknown error during conversion from CQL AST to literal")
+}
+
+func toFieldRef(field *cqlField, stringToComponent func(string) (metadata.ComponentMetadata, error)) (
+	fieldRef, error,
+) {
+	comp, err := stringToComponent(field.Component)
+	if err != nil {
+		return fieldRef{}, err
+	}
+	if !metadata.HasJSONField(comp, field.Path) {
+		return fieldRef{}, fmt.Errorf("component %s has no field %s", field.Component, strings.Join(field.Path, "."))
+	}
+	return fieldRef{component: comp, path: field.Path}, nil
+}
+
+func fieldPredicateToPredicate(pred *cqlPredicate, stringToComponent func(string) (metadata.ComponentMetadata, error)) (
+	predicate, error,
+) {
+	field, err := toFieldRef(pred.Field, stringToComponent)
+	if err != nil {
+		return nil, err
+	}
+	if pred.Between != nil {
+		low, err := toLiteral(pred.Between.Low)
+		if err != nil {
+			return nil, err
+		}
+		high, err := toLiteral(pred.Between.High)
+		if err != nil {
+			return nil, err
+		}
+		if low.kind != high.kind || (low.kind != kindNumber && low.kind != kindString) {
+			return nil, errors.New("BETWEEN needs two numbers or two strings")
+		}
+		return &between{field: field, low: low, high: high}, nil
+	}
+	value, err := toLiteral(pred.Comparison.Value)
+	if err != nil {
+		return nil, err
+	}
+	operator := pred.Comparison.Operator
+	if operator != "=" && operator != "!=" && operator != "<>" && value.kind != kindNumber && value.kind != kindString {
+		return nil, fmt.Errorf("%s can only compare numbers and strings", operator)
+	}
+	return &comparison{field: field, operator: operator, value: value}, nil
+}
+
+func factorToPredicate(factor *cqlConditionFactor, stringToComponent func(string) (metadata.ComponentMetadata, error),
+) (predicate, error) {
+	switch {
+	case factor.Not != nil:
+		sub, err := factorToPredicate(factor.Not, stringToComponent)
+		if err != nil {
+			return nil, err
+		}
+		return notPredicate{sub: sub}, nil
+	case factor.Subcondition != nil:
+		return conditionToPredicate(factor.Subcondition, stringToComponent)
+	case factor.Predicate != nil:
+		return fieldPredicateToPredicate(factor.Predicate, stringToComponent)
+	}
+	return nil, errors.New("unknown error during conversion from CQL AST to Condition")
+}
+
+// conditionToPredicate converts a WHERE clause to a predicate. Like in SQL, AND binds more tightly than OR.
+func conditionToPredicate(condition *cqlCondition, stringToComponent func(string) (metadata.ComponentMetadata, error),
+) (predicate, error) {
+	left, err := factorToPredicate(condition.Left, stringToComponent)
+	if err != nil {
+		return nil, err
+	}
+	groups := []andPredicate{{left}}
+	for _, right := range condition.Right {
+		sub, err := factorToPredicate(right.Condition, stringToComponent)
+		if err != nil {
+			return nil, err
+		}
+		switch right.Operator {
+		case opAnd:
+			groups[len(groups)-1] = append(groups[len(groups)-1], sub)
+		case opOr:
+			groups = append(groups, andPredicate{sub})
+		default:
+			return nil, errors.New("invalid operator")
+		}
+	}
+	or := make(orPredicate, 0, len(groups))
+	for _, group := range groups {
+		if len(group) == 1 {
+			or = append(or, group[0])
+		} else {
+			or = append(or, group)
+		}
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
@@ -0,0 +1,191 @@
+package ecs
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/cql"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/filter"
+)
+
+// SearchCQL calls callback for every entity that matches the given CQL query. If you would like to stop the
+// iteration, return false to the callback. Equality predicates on indexed fields are looked up in the indexes (see
+// WithIndex), in which case entities are visited in order of ID. The rest of the WHERE clause is checked against the
+// component values of each entity.
+func SearchCQL(wCtx WorldContext, query *cql.Query, callback SearchCallBackFn) error {
+	search := newCQLSearch(query)
+	if query.Where == nil {
+		return search.Each(wCtx, callback)
+	}
+	var matchErr error
+	err := search.Each(wCtx, func(id entity.ID) bool {
+		ok, err := matchesCQLWhere(wCtx, query, id)
+		if err != nil {
+			matchErr = err
+			return false
+		}
+		return !ok || callback(id)
+	})
+	if err != nil {
+		return err
+	}
+	return matchErr
+}
+
+// newCQLSearch returns the search of the entities that match the query's filter and the equality predicates on
+// indexed fields. The rest of the WHERE clause still has to be checked with matchesCQLWhere.
+func newCQLSearch(query *cql.Query) *Search {
+	search := NewSearch(query.Filter)
+	if query.Where == nil {
+		return search
+	}
+	for _, lookup := range query.Where.IndexLookups() {
+		search = search.Where(lookup.Component.Name()+"."+lookup.Field, lookup.Value)
+	}
+	return search
+}
+
+// matchesCQLWhere reports whether the component values of the given entity match the query's WHERE clause.
+func matchesCQLWhere(wCtx WorldContext, query *cql.Query, id entity.ID) (bool, error) {
+	if query.Where == nil {
+		return true, nil
+	}
+	return query.Where.Matches(func(c metadata.ComponentMetadata) (json.RawMessage, error) {
+		return getComponentJSONIfPresent(wCtx, c, id)
+	})
+}
+
+// getComponentJSONIfPresent returns the JSON encoded value of the given component, or nil if the entity does not have
+// the component.
+func getComponentJSONIfPresent(wCtx WorldContext, c metadata.ComponentMetadata, id entity.ID) (
+	json.RawMessage, error) {
+	reader := wCtx.StoreReader()
+	comps, err := reader.GetComponentTypesForEntity(id)
+	if err != nil {
+		return nil, err
+	}
+	if !filter.MatchComponentMetaData(comps, c) {
+		return nil, nil
+	}
+	bz, err := reader.GetComponentForEntityInRawJSON(c, id)
+	if errors.Is(err, ecb.ErrKeyNotFound) {
+		// This value has never been saved, so it has the default value.
+		return c.New()
+	}
+	return bz, err
+}
+
+// QueryCQL returns the entities that match the given CQL query, sorted and paginated as the query asks (see
+// cql.Query.Page). cursor continues from the end of a previous page, and nextCursor is empty if there are no more
+// pages.
+//
+// Without ORDER BY, the entities are checked in order of ID, and the search stops as soon as the page and the first
+// entity after it were found. With ORDER BY, every entity is checked, but only the matches that can still be on the
+// page are kept.
+func QueryCQL(wCtx WorldContext, query *cql.Query, cursor string) (ids []entity.ID, nextCursor string, err error) {
+	pager, err := query.NewPager(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(query.OrderBy) == 0 {
+		err = queryCQLInIDOrder(wCtx, query, pager)
+	} else {
+		err = queryCQLWithOrderBy(wCtx, query, pager)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	page, nextCursor, err := pager.Page()
+	if err != nil {
+		return nil, "", err
+	}
+	ids = make([]entity.ID, 0, len(page))
+	for _, match := range page {
+		ids = append(ids, match.ID)
+	}
+	return ids, nextCursor, nil
+}
+
+// queryCQLInIDOrder adds the matches of a query without ORDER BY to the pager in order of ID. Only the IDs of the
+// candidates are collected up front; the WHERE clause is checked one entity at a time until the pager is full.
+func queryCQLInIDOrder(wCtx WorldContext, query *cql.Query, pager *cql.Pager) error {
+	var candidates []entity.ID
+	err := newCQLSearch(query).Each(wCtx, func(id entity.ID) bool {
+		if !pager.IsBeforeCursor(cql.Match{ID: id}) {
+			candidates = append(candidates, id)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i] < candidates[j]
+	})
+	for _, id := range candidates {
+		if pager.IsFull() {
+			break
+		}
+		ok, err := matchesCQLWhere(wCtx, query, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			pager.Add(cql.Match{ID: id})
+		}
+	}
+	return nil
+}
+
+// queryCQLWithOrderBy adds every match of a query with ORDER BY to the pager.
+func queryCQLWithOrderBy(wCtx WorldContext, query *cql.Query, pager *cql.Pager) error {
+	var keyErr error
+	err := SearchCQL(wCtx, query, func(id entity.ID) bool {
+		key, err := query.SortKey(func(c metadata.ComponentMetadata) (json.RawMessage, error) {
+			return getComponentJSONIfPresent(wCtx, c, id)
+		})
+		if err != nil {
+			keyErr = err
+			return false
+		}
+		pager.Add(cql.Match{ID: id, SortKey: key})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return keyErr
+}
+
+// AggregateCQL computes the aggregates in the SELECT clause of the given CQL query over the entities that match it. See
+// cql.Aggregator.Results.
+func AggregateCQL(wCtx WorldContext, query *cql.Query) ([]cql.AggregateResponse, error) {
+	aggregator := query.NewAggregator()
+	if query.IsCount() {
+		count, err := NewSearch(query.Filter).Count(wCtx)
+		if err != nil {
+			return nil, err
+		}
+		rows := aggregator.Results()
+		rows[0].Values[0] = count
+		return rows, nil
+	}
+	var addErr error
+	err := SearchCQL(wCtx, query, func(id entity.ID) bool {
+		addErr = aggregator.Add(func(c metadata.ComponentMetadata) (json.RawMessage, error) {
+			return getComponentJSONIfPresent(wCtx, c, id)
+		})
+		return addErr == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if addErr != nil {
+		return nil, addErr
+	}
+	return aggregator.Results(), nil
+}
@@ -0,0 +1,126 @@
+package ecb
+
+import (
+	"strconv"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+)
+
+// archetypeIndex maps each set of components to its archetype ID, so archetypes can be found without comparing the set
+// to every archetype. It also caches the archetype an entity moves to when a component is added to or removed from an
+// entity of each archetype.
+type archetypeIndex struct {
+	ids         map[string]archetype.ID
+	transitions map[archetype.ID]map[archetypeTransition]archetype.ID
+}
+
+// archetypeTransition is the addition or removal of a single component.
+type archetypeTransition struct {
+	typeID metadata.TypeID
+	add    bool
+}
+
+func newArchetypeIndex(archIDToComps map[archetype.ID][]metadata.ComponentMetadata) *archetypeIndex {
+	index := &archetypeIndex{
+		ids:         make(map[string]archetype.ID, len(archIDToComps)),
+		transitions: map[archetype.ID]map[archetypeTransition]archetype.ID{},
+	}
+	for archID, comps := range archIDToComps {
+		index.add(archID, comps)
+	}
+	return index
+}
+
+// componentSetKey returns the canonical key of a sorted set of components. Equal sets have equal keys.
+func componentSetKey(comps []metadata.ComponentMetadata) string {
+	var key strings.Builder
+	for i, comp := range comps {
+		if i > 0 {
+			key.WriteByte(',')
+		}
+		key.WriteString(strconv.Itoa(int(comp.ID())))
+	}
+	return key.String()
+}
+
+// get returns the archetype ID of the given sorted set of components.
+func (a *archetypeIndex) get(comps []metadata.ComponentMetadata) (archetype.ID, bool) {
+	archID, ok := a.ids[componentSetKey(comps)]
+	return archID, ok
+}
+
+func (a *archetypeIndex) add(archID archetype.ID, comps []metadata.ComponentMetadata) {
+	a.ids[componentSetKey(comps)] = archID
+}
+
+// remove removes the given archetypes, along with every cached transition from or to them.
+func (a *archetypeIndex) remove(archIDs []archetype.ID, archIDToComps map[archetype.ID][]metadata.ComponentMetadata) {
+	if len(archIDs) == 0 {
+		return
+	}
+	removed := make(map[archetype.ID]bool, len(archIDs))
+	for _, archID := range archIDs {
+		removed[archID] = true
+		delete(a.ids, componentSetKey(archIDToComps[archID]))
+		delete(a.transitions, archID)
+	}
+	for _, targets := range a.transitions {
+		for transition, target := range targets {
+			if removed[target] {
+				delete(targets, transition)
+			}
+		}
+	}
+}
+
+// getTransition returns the cached archetype that entities of the given archetype move to after the transition.
+func (a *archetypeIndex) getTransition(from archetype.ID, transition archetypeTransition) (archetype.ID, bool) {
+	to, ok := a.transitions[from][transition]
+	return to, ok
+}
+
+func (a *archetypeIndex) setTransition(from archetype.ID, transition archetypeTransition, to archetype.ID) {
+	targets, ok := a.transitions[from]
+	if !ok {
+		targets = map[archetypeTransition]archetype.ID{}
+		a.transitions[from] = targets
+	}
+	targets[transition] = to
+}
+
+// getArchIDAfterTransition returns the archetype that an entity of the given archetype moves to when the given
+// component is added or removed, creating the archetype if needed. The caller must make sure the transition is valid.
+func (m *Manager) getArchIDAfterTransition(from archetype.ID, cType metadata.ComponentMetadata, add bool) (
+	archetype.ID, error) {
+	transition := archetypeTransition{typeID: cType.ID(), add: add}
+	if to, ok := m.archIndex.getTransition(from, transition); ok {
+		return to, nil
+	}
+	fromComps := m.getComponentTypesForArchID(from)
+	toComps := make([]metadata.ComponentMetadata, 0, len(fromComps)+1)
+	for _, comp := range fromComps {
+		if comp.ID() != cType.ID() {
+			toComps = append(toComps, comp)
+		}
+	}
+	if add {
+		toComps = append(toComps, cType)
+	}
+	if err := sortComponentSet(toComps); err != nil {
+		return 0, err
+	}
+	to, err := m.getOrMakeArchIDForComponents(toComps)
+	if err != nil {
+		return 0, err
+	}
+	m.archIndex.setTransition(from, transition, to)
+	return to, nil
+}
+
+// setArchIDToComps replaces every archetype, and rebuilds the archetype index.
+func (m *Manager) setArchIDToComps(archIDToComps map[archetype.ID][]metadata.ComponentMetadata) {
+	m.archIDToComps = archIDToComps
+	m.archIndex = newArchetypeIndex(archIDToComps)
+}
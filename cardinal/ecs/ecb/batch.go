@@ -0,0 +1,208 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/codec"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+// makeBatchOfCommands return a batch with all pending state changes ready to be committed in an atomic
+// transaction. If an error is returned, no storage changes will have been made.
+func (m *Manager) makeBatchOfCommands(ctx context.Context) (KVBatch, error) {
+	batch := m.kv.NewBatch()
+
+	if m.typeToComponent == nil {
+		// component.TypeID -> ComponentMetadata mappings are required to serialized data for the DB
+		return nil, errors.New("must call RegisterComponents before flushing to DB")
+	}
+
+	if err := m.addComponentChangesToBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to add component changes to batch: %w", err)
+	}
+	if err := m.addNextEntityIDToBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to add entity id changes to batch: %w", err)
+	}
+	if err := m.addPendingArchIDsToBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to add archID to component type map to batch: %w", err)
+	}
+	if err := m.addEntityIDToArchIDToBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to add entity ID to archID mapping to batch: %w", err)
+	}
+	if err := m.addActiveEntityIDsToBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to add changes to active entity ids to batch: %w", err)
+	}
+	if err := m.addEventsToBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to add events to batch: %w", err)
+	}
+	if err := m.addSubmissionToBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to add submission to batch: %w", err)
+	}
+
+	return batch, nil
+}
+
+// addEntityIDToArchIDToBatch adds the information related to mapping an entity ID to its assigned archetype ID.
+func (m *Manager) addEntityIDToArchIDToBatch(ctx context.Context, batch KVBatch) error {
+	for id, originArchID := range m.entityIDToOriginArchID {
+		key := redisArchetypeIDForEntityID(id)
+		archID, ok := m.entityIDToArchID[id]
+		if !ok {
+			// this entity has been removed
+			if err := batch.Del(ctx, key); err != nil {
+				return err
+			}
+			continue
+		}
+		// This entity somehow ended up back at its original archetype. There's nothing to do.
+		if archID == originArchID {
+			continue
+		}
+
+		// Otherwise, the archetype actually needs to be updated
+		if err := batch.Set(ctx, key, encodeInt(int(archID))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addNextEntityIDToBatch adds any changes to the next available entity ID to the given batch.
+func (m *Manager) addNextEntityIDToBatch(ctx context.Context, batch KVBatch) error {
+	// There are no pending entity id creations, so there's nothing to commit
+	if m.pendingEntityIDs == 0 {
+		return nil
+	}
+	key := redisNextEntityIDKey()
+	nextID := m.nextEntityIDSaved + m.pendingEntityIDs
+	return batch.Set(ctx, key, encodeUint64(nextID))
+}
+
+// addComponentChangesToBatch adds updated component values for entities to the batch.
+func (m *Manager) addComponentChangesToBatch(ctx context.Context, batch KVBatch) error {
+	for key, isMarkedForDeletion := range m.compValuesToDelete {
+		if !isMarkedForDeletion {
+			continue
+		}
+		redisKey := redisComponentKey(key.typeID, key.entityID)
+		if err := batch.Del(ctx, redisKey); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range m.compValues {
+		cType := m.typeToComponent[key.typeID]
+		bz, err := cType.Encode(value)
+		if err != nil {
+			return err
+		}
+
+		redisKey := redisComponentKey(key.typeID, key.entityID)
+		if err = batch.Set(ctx, redisKey, bz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preloadArchIDs loads the mapping of archetypes IDs to sets of IComponentTypes from storage.
+func (m *Manager) loadArchIDs() error {
+	archIDToComps, ok, err := getArchIDToCompTypesFromKV(m.kv, m.typeToComponent)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// Nothing is saved in the DB. Leave the m.archIDToComps field unchanged
+		return nil
+	}
+	if len(m.archIDToComps) > 0 {
+		return errors.New("assigned archetype ID is about to be overwritten by something from storage")
+	}
+	m.setArchIDToComps(archIDToComps)
+	return nil
+}
+
+// addPendingArchIDsToBatch adds any newly created archetype IDs (as well as the associated sets of components) to the
+// batch.
+func (m *Manager) addPendingArchIDsToBatch(ctx context.Context, batch KVBatch) error {
+	if len(m.pendingArchIDs) == 0 {
+		return nil
+	}
+
+	bz, err := encodeArchIDToCompTypes(m.archIDToComps)
+	if err != nil {
+		return err
+	}
+
+	return batch.Set(ctx, redisArchIDsToCompTypesKey(), bz)
+}
+
+// addActiveEntityIDsToBatch adds information about which entities are assigned to which archetype IDs to the batch.
+func (m *Manager) addActiveEntityIDsToBatch(ctx context.Context, batch KVBatch) error {
+	for archID, active := range m.activeEntities {
+		if !active.modified {
+			continue
+		}
+		bz, err := codec.Encode(active.ids)
+		if err != nil {
+			return err
+		}
+		key := redisActiveEntityIDKey(archID)
+		err = batch.Set(ctx, key, bz)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeArchIDToCompTypes(archIDToComps map[archetype.ID][]metadata.ComponentMetadata) ([]byte, error) {
+	forStorage := map[archetype.ID][]metadata.TypeID{}
+	for archID, comps := range archIDToComps {
+		typeIDs := []metadata.TypeID{}
+		for _, comp := range comps {
+			typeIDs = append(typeIDs, comp.ID())
+		}
+		forStorage[archID] = typeIDs
+	}
+	return codec.Encode(forStorage)
+}
+
+func getArchIDToCompTypesFromKV(kv KVStore,
+	typeToComp map[metadata.TypeID]metadata.ComponentMetadata,
+) (m map[archetype.ID][]metadata.ComponentMetadata, ok bool, err error) {
+	ctx := context.Background()
+	key := redisArchIDsToCompTypesKey()
+	bz, err := kv.Get(ctx, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	fromStorage, err := codec.Decode[map[archetype.ID][]metadata.TypeID](bz)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// result is the mapping of Arch ID -> IComponent sets
+	result := map[archetype.ID][]metadata.ComponentMetadata{}
+	for archID, compTypeIDs := range fromStorage {
+		currComps := []metadata.ComponentMetadata{}
+		for _, compTypeID := range compTypeIDs {
+			currComp, found := typeToComp[compTypeID]
+			if !found {
+				return nil, false, storage.ErrComponentMismatchWithSavedState
+			}
+			currComps = append(currComps, currComp)
+		}
+
+		result[archID] = currComps
+	}
+	return result, true, nil
+}
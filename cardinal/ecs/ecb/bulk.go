@@ -0,0 +1,210 @@
+package ecb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/codec"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/filter"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+// GetComponentsForEntities returns the saved component data for each of the given entities, in the same order. The
+// archetypes and values that are not in memory are fetched from the KVStore with one MGet each.
+func (m *Manager) GetComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID) ([]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getComponentsForEntities(cType, ids)
+}
+
+func (m *Manager) getComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID) ([]any, error) {
+	values := make([]any, len(ids))
+	var missing []int
+	for i, id := range ids {
+		if value, ok := m.compValues[compKey{cType.ID(), id}]; ok {
+			values[i] = value
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+	ctx := context.Background()
+	missingIDs := make([]entity.ID, 0, len(missing))
+	for _, i := range missing {
+		missingIDs = append(missingIDs, ids[i])
+	}
+	if err := m.loadArchetypesForEntities(ctx, missingIDs); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(missing))
+	for _, id := range missingIDs {
+		// Make sure each entity has this component
+		comps, err := m.getComponentTypesForEntity(id)
+		if err != nil {
+			return nil, err
+		}
+		if !filter.MatchComponentMetaData(comps, cType) {
+			return nil, storage.ErrComponentNotOnEntity
+		}
+		keys = append(keys, redisComponentKey(cType.ID(), id))
+	}
+	bzs, err := m.kv.MGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range missing {
+		value, err := decodeComponentOrDefault(cType, bzs[j])
+		if err != nil {
+			return nil, err
+		}
+		m.compValues[compKey{cType.ID(), ids[i]}] = value
+		values[i] = value
+	}
+	return values, nil
+}
+
+// SetComponentsForEntities sets the given component of each of the given entities to the value at the same index.
+func (m *Manager) SetComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID, values []any) error {
+	if len(ids) != len(values) {
+		return fmt.Errorf("got %d entities but %d values", len(ids), len(values))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.loadArchetypesForEntities(context.Background(), ids); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if err := m.setComponentForEntity(cType, id, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadArchetypesForEntities fetches the archetype of each of the given entities that is not in memory yet. Entities
+// that do not exist are skipped, so getArchetypeForEntity returns the error for them.
+func (m *Manager) loadArchetypesForEntities(ctx context.Context, ids []entity.ID) error {
+	var missing []entity.ID
+	var keys []string
+	for _, id := range ids {
+		if _, ok := m.entityIDToArchID[id]; !ok {
+			missing = append(missing, id)
+			keys = append(keys, redisArchetypeIDForEntityID(id))
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	bzs, err := m.kv.MGet(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for i, bz := range bzs {
+		if bz == nil {
+			continue
+		}
+		num, err := strconv.Atoi(string(bz))
+		if err != nil {
+			return err
+		}
+		m.entityIDToArchID[missing[i]] = archetype.ID(num)
+	}
+	return nil
+}
+
+// loadActiveEntities fetches the entities of every archetype whose entities are not in memory yet, including the
+// given archetype, with a single MGet.
+func (m *Manager) loadActiveEntities(ctx context.Context, archID archetype.ID) error {
+	archIDs := []archetype.ID{archID}
+	for id := range m.archIDToComps {
+		if _, ok := m.activeEntities[id]; !ok && id != archID {
+			archIDs = append(archIDs, id)
+		}
+	}
+	keys := make([]string, 0, len(archIDs))
+	for _, id := range archIDs {
+		keys = append(keys, redisActiveEntityIDKey(id))
+	}
+	bzs, err := m.kv.MGet(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for i, bz := range bzs {
+		var ids []entity.ID
+		if bz != nil {
+			if ids, err = codec.Decode[[]entity.ID](bz); err != nil {
+				return err
+			}
+		}
+		m.activeEntities[archIDs[i]] = activeEntities{
+			ids:      ids,
+			modified: false,
+		}
+	}
+	return nil
+}
+
+// GetComponentsForEntities returns the committed component data for each of the given entities, in the same order.
+func (r *readOnlyManager) GetComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID) ([]any, error) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, redisComponentKey(cType.ID(), id))
+	}
+	bzs, err := r.kv.MGet(context.Background(), keys)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, 0, len(ids))
+	for _, bz := range bzs {
+		value, err := decodeComponentOrDefault(cType, bz)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// decodeComponentOrDefault decodes a value fetched with MGet. A nil value has never been set, so it has the default
+// value.
+func decodeComponentOrDefault(cType metadata.ComponentMetadata, bz []byte) (any, error) {
+	if bz == nil {
+		var err error
+		if bz, err = cType.New(); err != nil {
+			return nil, err
+		}
+	}
+	return cType.Decode(bz)
+}
+
+// getCommittedEncodedComponents fetches the committed encoded value of the component at each index of comps on the
+// entity at the same index of ids with a single MGet. Values that have never been set have the component's default
+// value. Values are not decoded or cached.
+func getCommittedEncodedComponents(ctx context.Context, kv KVStore, comps []metadata.ComponentMetadata,
+	ids []entity.ID) ([][]byte, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(ids))
+	for i, id := range ids {
+		keys = append(keys, redisComponentKey(comps[i].ID(), id))
+	}
+	bzs, err := kv.MGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for i, bz := range bzs {
+		if bz == nil {
+			if bzs[i], err = comps[i].New(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return bzs, nil
+}
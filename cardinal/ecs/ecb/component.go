@@ -29,15 +29,3 @@ func sortComponentSet(components []metadata.ComponentMetadata) error {
 
 	return nil
 }
-
-func isComponentSetMatch(a, b []metadata.ComponentMetadata) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if a[i].ID() != b[i].ID() {
-			return false
-		}
-	}
-	return true
-}
@@ -0,0 +1,205 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+var _ store.DiffStorage = &Manager{}
+
+// diffTracker records the state changes that have been made since the last finalized tick. Only the IDs of changed
+// entities and components are tracked here; the final values are read from the Manager when the TickDiff is built.
+type diffTracker struct {
+	createdEntities map[entity.ID]bool
+	// removedEntities is the archetype each removed entity belonged to when it was removed.
+	removedEntities map[entity.ID]archetype.ID
+	setComps        map[compKey]bool
+	removedComps    map[compKey]bool
+	// originArchIDs is the archetype each moved entity belonged to at the start of the tick.
+	originArchIDs map[entity.ID]archetype.ID
+}
+
+func newDiffTracker() diffTracker {
+	return diffTracker{
+		createdEntities: map[entity.ID]bool{},
+		removedEntities: map[entity.ID]archetype.ID{},
+		setComps:        map[compKey]bool{},
+		removedComps:    map[compKey]bool{},
+		originArchIDs:   map[entity.ID]archetype.ID{},
+	}
+}
+
+func (d *diffTracker) reset() {
+	clear(d.createdEntities)
+	clear(d.removedEntities)
+	clear(d.setComps)
+	clear(d.removedComps)
+	clear(d.originArchIDs)
+}
+
+func (d *diffTracker) entityCreated(id entity.ID) {
+	d.createdEntities[id] = true
+}
+
+func (d *diffTracker) entityRemoved(id entity.ID, archID archetype.ID) {
+	if d.createdEntities[id] {
+		// This entity never made it to storage, so there's no need to report it at all.
+		delete(d.createdEntities, id)
+		return
+	}
+	d.removedEntities[id] = archID
+}
+
+func (d *diffTracker) componentSet(key compKey) {
+	delete(d.removedComps, key)
+	d.setComps[key] = true
+}
+
+func (d *diffTracker) componentRemoved(key compKey) {
+	delete(d.setComps, key)
+	d.removedComps[key] = true
+}
+
+func (d *diffTracker) entityMoved(id entity.ID, fromArchID archetype.ID) {
+	if _, ok := d.originArchIDs[id]; !ok {
+		d.originArchIDs[id] = fromArchID
+	}
+}
+
+// LastTickDiff returns the changes that were committed by the most recent successful call to FinalizeTick.
+func (m *Manager) LastTickDiff() *store.TickDiff {
+	return m.lastTickDiff
+}
+
+// makeTickDiff builds a TickDiff out of the changes that have been made since the last finalized tick.
+func (m *Manager) makeTickDiff(ctx context.Context) (*store.TickDiff, error) {
+	tick, err := getUint64(ctx, m.kv, redisEndTickKey())
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+	diff := &store.TickDiff{
+		Tick:              tick,
+		CreatedEntities:   sortedEntityIDs(m.diff.createdEntities),
+		RemovedEntities:   make([]entity.ID, 0, len(m.diff.removedEntities)),
+		ComponentSets:     []store.ComponentChange{},
+		ComponentRemovals: []store.ComponentChange{},
+		ArchetypeMoves:    []store.ArchetypeMove{},
+	}
+
+	// Every component on a created entity is reported, regardless of whether it was explicitly set.
+	for _, id := range diff.CreatedEntities {
+		comps, err := m.GetComponentTypesForEntity(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, comp := range comps {
+			bz, err := m.GetComponentForEntityInRawJSON(comp, id)
+			if err != nil {
+				return nil, err
+			}
+			diff.ComponentSets = append(diff.ComponentSets, store.ComponentChange{
+				EntityID:  id,
+				Component: comp.Name(),
+				Value:     bz,
+			})
+		}
+	}
+
+	for key := range m.diff.setComps {
+		if !m.isExistingEntity(key.entityID) {
+			continue
+		}
+		comp := m.typeToComponent[key.typeID]
+		bz, err := m.GetComponentForEntityInRawJSON(comp, key.entityID)
+		if err != nil {
+			return nil, err
+		}
+		diff.ComponentSets = append(diff.ComponentSets, store.ComponentChange{
+			EntityID:  key.entityID,
+			Component: comp.Name(),
+			Value:     bz,
+		})
+	}
+	sortComponentChanges(diff.ComponentSets)
+
+	for id, archID := range m.diff.removedEntities {
+		diff.RemovedEntities = append(diff.RemovedEntities, id)
+		// Every component that was on a removed entity has also been removed.
+		for _, comp := range m.GetComponentTypesForArchID(archID) {
+			diff.ComponentRemovals = append(diff.ComponentRemovals, store.ComponentChange{
+				EntityID:  id,
+				Component: comp.Name(),
+			})
+		}
+	}
+	sort.Slice(diff.RemovedEntities, func(i, j int) bool {
+		return diff.RemovedEntities[i] < diff.RemovedEntities[j]
+	})
+	for key := range m.diff.removedComps {
+		if !m.isExistingEntity(key.entityID) {
+			continue
+		}
+		diff.ComponentRemovals = append(diff.ComponentRemovals, store.ComponentChange{
+			EntityID:  key.entityID,
+			Component: m.typeToComponent[key.typeID].Name(),
+		})
+	}
+	sortComponentChanges(diff.ComponentRemovals)
+
+	for id, fromArchID := range m.diff.originArchIDs {
+		if !m.isExistingEntity(id) {
+			continue
+		}
+		toArchID := m.entityIDToArchID[id]
+		if toArchID == fromArchID {
+			continue
+		}
+		diff.ArchetypeMoves = append(diff.ArchetypeMoves, store.ArchetypeMove{
+			EntityID: id,
+			From:     fromArchID,
+			To:       toArchID,
+		})
+	}
+	sort.Slice(diff.ArchetypeMoves, func(i, j int) bool {
+		return diff.ArchetypeMoves[i].EntityID < diff.ArchetypeMoves[j].EntityID
+	})
+
+	return diff, nil
+}
+
+// isExistingEntity returns true if the given entity was changed during this tick, existed before this tick started,
+// and still exists. Changes to any other entity are already covered by CreatedEntities or RemovedEntities.
+func (m *Manager) isExistingEntity(id entity.ID) bool {
+	if _, ok := m.diff.removedEntities[id]; ok || m.diff.createdEntities[id] {
+		return false
+	}
+	// Any entity that was changed during the tick has had its archetype loaded, and removed entities are dropped
+	// from this map.
+	_, ok := m.entityIDToArchID[id]
+	return ok
+}
+
+func sortedEntityIDs(ids map[entity.ID]bool) []entity.ID {
+	result := make([]entity.ID, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
+
+func sortComponentChanges(changes []store.ComponentChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].EntityID != changes[j].EntityID {
+			return changes[i].EntityID < changes[j].EntityID
+		}
+		return changes[i].Component < changes[j].Component
+	})
+}
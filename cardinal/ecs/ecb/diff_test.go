@@ -0,0 +1,108 @@
+package ecb_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+func TestTickDiffIsEmptyBeforeFirstTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	assert.Check(t, manager.LastTickDiff() == nil)
+
+	assert.NilError(t, manager.StartNextTick(nil, nil))
+	assert.NilError(t, manager.FinalizeTick())
+	diff := manager.LastTickDiff()
+	assert.Check(t, diff != nil)
+	assert.Equal(t, uint64(0), diff.Tick)
+	assert.Check(t, diff.IsEmpty())
+}
+
+func TestTickDiffContainsCreatedEntities(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ids, err := manager.CreateManyEntities(2, fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[0], Foo{Value: 7}))
+	assert.NilError(t, manager.FinalizeTick())
+
+	diff := manager.LastTickDiff()
+	assert.DeepEqual(t, ids, diff.CreatedEntities)
+	assert.Equal(t, 0, len(diff.RemovedEntities))
+	assert.Equal(t, 0, len(diff.ArchetypeMoves))
+	// Every component on every created entity should be reported, even if the value was never explicitly set.
+	assert.Equal(t, 4, len(diff.ComponentSets))
+	assert.Equal(t, ids[0], diff.ComponentSets[0].EntityID)
+	assert.Equal(t, "bar", diff.ComponentSets[0].Component)
+	assert.Equal(t, "foo", diff.ComponentSets[1].Component)
+	foo := decodeFoo(t, diff.ComponentSets[1].Value)
+	assert.Equal(t, 7, foo.Value)
+}
+
+func TestTickDiffOnlyContainsChangesFromTheLastTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ids, err := manager.CreateManyEntities(3, fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick())
+
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[0], Foo{Value: 100}))
+	assert.NilError(t, manager.AddComponentToEntity(barComp, ids[1]))
+	assert.NilError(t, manager.RemoveEntity(ids[2]))
+	// Reading a component is not a change.
+	_, err = manager.GetComponentForEntity(fooComp, ids[1])
+	assert.NilError(t, err)
+	// An entity that is created and removed in the same tick is not a change.
+	tempID, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RemoveEntity(tempID))
+	assert.NilError(t, manager.FinalizeTick())
+
+	diff := manager.LastTickDiff()
+	assert.Equal(t, uint64(1), diff.Tick)
+	assert.Equal(t, 0, len(diff.CreatedEntities))
+	assert.DeepEqual(t, []entity.ID{ids[2]}, diff.RemovedEntities)
+	assert.DeepEqual(t, []store.ComponentChange{{EntityID: ids[2], Component: "foo"}}, diff.ComponentRemovals)
+	assert.Equal(t, 2, len(diff.ComponentSets))
+	assert.Equal(t, ids[0], diff.ComponentSets[0].EntityID)
+	assert.Equal(t, 100, decodeFoo(t, diff.ComponentSets[0].Value).Value)
+	assert.Equal(t, ids[1], diff.ComponentSets[1].EntityID)
+	assert.Equal(t, "bar", diff.ComponentSets[1].Component)
+	assert.Equal(t, 1, len(diff.ArchetypeMoves))
+	move := diff.ArchetypeMoves[0]
+	assert.Equal(t, ids[1], move.EntityID)
+	assert.Check(t, move.From != move.To)
+
+	// Remove the component that was just added. The entity should move back to its original archetype.
+	assert.NilError(t, manager.RemoveComponentFromEntity(barComp, ids[1]))
+	assert.NilError(t, manager.FinalizeTick())
+	diff = manager.LastTickDiff()
+	assert.Equal(t, uint64(2), diff.Tick)
+	assert.DeepEqual(t, []store.ComponentChange{{EntityID: ids[1], Component: "bar"}}, diff.ComponentRemovals)
+	assert.DeepEqual(t, []store.ArchetypeMove{{EntityID: ids[1], From: move.To, To: move.From}}, diff.ArchetypeMoves)
+	assert.Equal(t, 0, len(diff.ComponentSets))
+
+	// A tick with no changes should produce an empty diff.
+	assert.NilError(t, manager.FinalizeTick())
+	assert.Check(t, manager.LastTickDiff().IsEmpty())
+}
+
+func TestDiscardedChangesAreNotInTickDiff(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick())
+
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 1}))
+	manager.DiscardPending()
+	assert.NilError(t, manager.FinalizeTick())
+	assert.Check(t, manager.LastTickDiff().IsEmpty())
+}
+
+func decodeFoo(t *testing.T, bz json.RawMessage) Foo {
+	var foo Foo
+	assert.NilError(t, json.Unmarshal(bz, &foo))
+	return foo
+}
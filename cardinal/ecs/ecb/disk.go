@@ -0,0 +1,181 @@
+package ecb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var _ KVStore = &diskStore{}
+
+var (
+	ErrDiskStoreClosed = errors.New("disk store is closed")
+)
+
+const (
+	diskDBFileName = "ecb.db"
+	diskFilePerm   = 0o600
+	diskDirPerm    = 0o750
+
+	// diskOpenTimeout is how long NewDiskKVStore waits for another process to release the directory.
+	diskOpenTimeout = time.Second
+)
+
+// diskBucket is the bolt bucket every key is saved in.
+var diskBucket = []byte("ecb")
+
+// diskStore is a KVStore that persists data to a bolt database in a local directory. Each call to KVBatch.Exec is a
+// single bolt transaction, which is fsynced before Exec returns. A batch is either fully applied or not applied at all,
+// even if the process crashes in the middle of a write, which gives the same crash recovery guarantees for
+// GetTickNumbers and Recover as the redis backed store. Data is read from disk (via bolt's memory map), so it does not
+// need to fit in memory.
+type diskStore struct {
+	db *bbolt.DB
+}
+
+// NewDiskKVStore opens (or creates) a KVStore that persists data in the given directory. Only one process may have the
+// directory open at a time.
+func NewDiskKVStore(dir string) (KVStore, error) {
+	if err := os.MkdirAll(dir, diskDirPerm); err != nil {
+		return nil, err
+	}
+	db, err := bbolt.Open(filepath.Join(dir, diskDBFileName), diskFilePerm, &bbolt.Options{Timeout: diskOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(diskBucket)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return &diskStore{db: db}, nil
+}
+
+// view runs fn in a read only transaction, with the bucket that holds every key.
+func (s *diskStore) view(fn func(bucket *bbolt.Bucket) error) error {
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return fn(tx.Bucket(diskBucket))
+	})
+	if errors.Is(err, bbolt.ErrDatabaseNotOpen) {
+		return ErrDiskStoreClosed
+	}
+	return err
+}
+
+func (s *diskStore) Get(_ context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.view(func(bucket *bbolt.Bucket) error {
+		bz := bucket.Get([]byte(key))
+		if bz == nil {
+			return ErrKeyNotFound
+		}
+		// Values returned by bolt are only valid during the transaction.
+		value = append([]byte{}, bz...)
+		return nil
+	})
+	return value, err
+}
+
+func (s *diskStore) MGet(_ context.Context, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	err := s.view(func(bucket *bbolt.Bucket) error {
+		for i, key := range keys {
+			if bz := bucket.Get([]byte(key)); bz != nil {
+				values[i] = append([]byte{}, bz...)
+			}
+		}
+		return nil
+	})
+	return values, err
+}
+
+func (s *diskStore) Keys(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.view(func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
+		for k, _ := cursor.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = cursor.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (s *diskStore) NewBatch() KVBatch {
+	return &diskBatch{store: s}
+}
+
+func (s *diskStore) Close() error {
+	return s.db.Close()
+}
+
+// commit applies the given operations in a single bolt transaction. If any operation fails, none of them are applied.
+func (s *diskStore) commit(ops []memoryOp) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(diskBucket)
+		for _, op := range ops {
+			key := []byte(op.key)
+			var err error
+			switch op.kind {
+			case memoryOpSet:
+				err = bucket.Put(key, op.value)
+			case memoryOpDel:
+				err = bucket.Delete(key)
+			case memoryOpIncr:
+				var n int64
+				if bz := bucket.Get(key); bz != nil {
+					n, err = strconv.ParseInt(string(bz), 10, 64)
+					if err != nil {
+						return fmt.Errorf("value at key %q is not an integer: %w", op.key, err)
+					}
+				}
+				err = bucket.Put(key, []byte(strconv.FormatInt(n+1, 10)))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, bbolt.ErrDatabaseNotOpen) {
+		return ErrDiskStoreClosed
+	}
+	return err
+}
+
+type diskBatch struct {
+	store *diskStore
+	ops   []memoryOp
+}
+
+func (b *diskBatch) Set(_ context.Context, key string, value []byte) error {
+	b.ops = append(b.ops, memoryOp{kind: memoryOpSet, key: key, value: append([]byte{}, value...)})
+	return nil
+}
+
+func (b *diskBatch) Del(_ context.Context, key string) error {
+	b.ops = append(b.ops, memoryOp{kind: memoryOpDel, key: key})
+	return nil
+}
+
+func (b *diskBatch) Incr(_ context.Context, key string) error {
+	b.ops = append(b.ops, memoryOp{kind: memoryOpIncr, key: key})
+	return nil
+}
+
+func (b *diskBatch) Exec(_ context.Context) error {
+	if err := b.store.commit(b.ops); err != nil {
+		return err
+	}
+	b.ops = nil
+	return nil
+}
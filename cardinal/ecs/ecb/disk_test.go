@@ -0,0 +1,141 @@
+package ecb_test
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+	"pkg.world.dev/world-engine/cardinal/ecs/internal/testutil"
+	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
+)
+
+func openDiskKVStoreForTest(t *testing.T, dir string) ecb.KVStore {
+	kv, err := ecb.NewDiskKVStore(dir)
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		assert.NilError(t, kv.Close())
+	})
+	return kv
+}
+
+func TestDiskStoreStateSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	kv := openDiskKVStoreForTest(t, dir)
+	manager := newInMemoryManagerForTest(t, kv)
+
+	ids, err := manager.CreateManyEntities(10, fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[3], Foo{Value: 33}))
+	assert.NilError(t, manager.StartNextTick(nil, transaction.NewTxQueue()))
+	assert.NilError(t, manager.FinalizeTick())
+	assert.NilError(t, kv.Close())
+
+	manager = newInMemoryManagerForTest(t, openDiskKVStoreForTest(t, dir))
+	start, end, err := manager.GetTickNumbers()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), start)
+	assert.Equal(t, uint64(1), end)
+
+	gotFoo, err := manager.GetComponentForEntity(fooComp, ids[3])
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{Value: 33}, gotFoo)
+
+	nextID, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.Equal(t, ids[len(ids)-1]+1, nextID)
+}
+
+func TestDiskStoreRecoversUnfinishedTick(t *testing.T) {
+	type TxIn struct {
+		Value int
+	}
+	tx := ecs.NewTransactionType[TxIn, TxIn]("disk-tx")
+	assert.NilError(t, tx.SetID(1))
+	txs := []transaction.ITransaction{tx}
+
+	dir := t.TempDir()
+	kv := openDiskKVStoreForTest(t, dir)
+	manager := newInMemoryManagerForTest(t, kv)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.CommitPending())
+
+	queue := transaction.NewTxQueue()
+	queue.AddTransaction(tx.ID(), TxIn{Value: 99}, testutil.UniqueSignature(t))
+	assert.NilError(t, manager.StartNextTick(txs, queue))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 100}))
+	// Simulate a crash before FinalizeTick is called.
+	assert.NilError(t, kv.Close())
+
+	manager = newInMemoryManagerForTest(t, openDiskKVStoreForTest(t, dir))
+	start, end, err := manager.GetTickNumbers()
+	assert.NilError(t, err)
+	assert.Equal(t, start, end+1)
+
+	gotFoo, err := manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{}, gotFoo)
+
+	recovered, err := manager.Recover(txs)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, recovered.GetAmountOfTxs())
+}
+
+func TestDiskStoreDoesNotApplyFailedBatches(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	kv := openDiskKVStoreForTest(t, dir)
+
+	batch := kv.NewBatch()
+	assert.NilError(t, batch.Set(ctx, "alpha", []byte("1")))
+	assert.NilError(t, batch.Set(ctx, "word", []byte("not-a-number")))
+	assert.NilError(t, batch.Incr(ctx, "counter"))
+	assert.NilError(t, batch.Exec(ctx))
+
+	// Incrementing a value that is not an integer fails, so the earlier operations in the batch are not applied either.
+	batch = kv.NewBatch()
+	assert.NilError(t, batch.Set(ctx, "beta", []byte("2")))
+	assert.NilError(t, batch.Del(ctx, "alpha"))
+	assert.NilError(t, batch.Incr(ctx, "counter"))
+	assert.NilError(t, batch.Incr(ctx, "word"))
+	assert.Check(t, batch.Exec(ctx) != nil)
+	assert.NilError(t, kv.Close())
+
+	kv = openDiskKVStoreForTest(t, dir)
+	gotAlpha, err := kv.Get(ctx, "alpha")
+	assert.NilError(t, err)
+	assert.Equal(t, "1", string(gotAlpha))
+	_, err = kv.Get(ctx, "beta")
+	assert.ErrorIs(t, err, ecb.ErrKeyNotFound)
+	gotCounter, err := kv.Get(ctx, "counter")
+	assert.NilError(t, err)
+	assert.Equal(t, "1", string(gotCounter))
+
+	keys, err := kv.Keys(ctx, "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"alpha", "counter", "word"}, keys)
+}
+
+func TestKVNonceStorage(t *testing.T) {
+	dir := t.TempDir()
+	kv := openDiskKVStoreForTest(t, dir)
+	nonces := ecb.NewKVNonceStorage(kv)
+
+	gotNonce, err := nonces.GetNonce("some-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), gotNonce)
+	assert.NilError(t, nonces.SetNonce("some-address", 100))
+	assert.NilError(t, nonces.SetNonce("other-address", 200))
+	assert.NilError(t, kv.Close())
+
+	nonces = ecb.NewKVNonceStorage(openDiskKVStoreForTest(t, dir))
+	gotNonce, err = nonces.GetNonce("some-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(100), gotNonce)
+
+	allNonces, err := nonces.GetAllNonces()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]uint64{"some-address": 100, "other-address": 200}, allNonces)
+}
@@ -3,6 +3,23 @@ Package ecb allows for buffering of state changes to the ECS storage layer, and
 in an atomic Redis transaction, or discarding the changes. In either case, the underlying Redis DB is never in an
 intermediate state.
 
+# Storage backends
+
+The Manager does not talk to Redis directly. Instead, all reads and writes go through a KVStore, and all writes for a
+single commit are grouped into one KVBatch that is applied atomically. Three KVStore implementations are provided:
+
+NewRedisKVStore saves data to a Redis DB. Batches are applied with a multi/exec pipeline.
+
+NewMemoryKVStore keeps data in process memory. Batches are applied while holding a lock, so readers never observe a
+partially applied batch. Nothing is persisted across process restarts.
+
+NewDiskKVStore persists data to a bolt database file in a local directory, so no external DB is required. Each batch
+is applied in a single bolt transaction that is fsynced before Exec returns, so a batch is never partially applied, even
+if the process crashes. Values are read from disk rather than kept in memory.
+
+All backends use the same key layout (described below), so the tick recovery semantics of GetTickNumbers, Recover,
+StartNextTick and FinalizeTick are identical.
+
 # Atomic options
 
 There are two ways a batch of state changes can be grouped and applied/discarded.
@@ -30,6 +47,23 @@ this time will never return any pending state. For example, if a series of 100 c
 100, and then CommitPending is called, reading this value from the DB will only ever return 0 or 100 (depending on the
 exact timing of the call).
 
+# Tick diffs
+
+In addition to the pending state, the Manager tracks which entities and components were changed since the last
+finalized tick. When FinalizeTick successfully commits a tick, these changes are packaged into a store.TickDiff (created
+and removed entities, component sets and removals, and archetype moves) that can be fetched with Manager.LastTickDiff.
+Tick diffs are only kept in memory; they are not saved to the DB.
+
+# State hashes
+
+FinalizeTick also saves a state hash for every tick, which can be fetched with Manager.GetStateHash. Two stores with
+the same entities and component values always have the same state hash, so nodes can cheaply check that they agree on
+the state of a tick. The Manager keeps the Merkle tree of every committed component value in memory (see the merkle
+package), so after the first tick only the paths to the leaves of components that changed during a tick have to be
+rehashed, which costs O(log n) hashes per change. The trees are never changed once they are built, so the trees of
+recent ticks are kept (see SetProofRetention) and GetComponentProof builds proofs against them without blocking
+FinalizeTick.
+
 # Redis Storage Model
 
 The Redis keys that store data in redis are defined in keys.go. All keys are prefixed with "ECB".
@@ -65,12 +99,54 @@ value:  JSON serialized bytes that can be deserialized to a list of transactions
 processed in the last started tick. This data is only relevant when the START-TICK number does not match the END-TICK
 number.
 
+key: 	"ECB:PENDING-TIMESTAMP"
+value:  An integer that represents the timestamp, in unix milliseconds, of the last started tick. It is used to recover
+an incomplete tick with its original timestamp, and to derive the timestamp of the next tick.
+
+key: 	"ECB:SUBMISSION:TICK-{tick}"
+value:  The data the given tick has to submit to the base shard (see AddSubmission). It is saved along with the rest of
+the tick and deleted once it was submitted, so the keys that remain belong to ticks that still have to be submitted. The
+tick number is zero padded to 20 digits so the keys sort in tick order.
+
+key: 	"ECB:EVENTS:TICK-{tick}"
+value:  A JSON array of the events that were emitted during the given tick. The tick number is zero padded to 20 digits
+so the keys sort in tick order. Ticks that did not emit any events have no key. Keys older than the event retention
+(see SetEventRetention) are deleted.
+
+key: 	"ECB:EVENTS-OLDEST-TICK"
+value:  An integer that represents the oldest tick whose events have not been deleted. Every ECB:EVENTS:TICK key from
+this tick up to the end tick is deleted once it falls out of the event retention, even if the retention was lowered.
+
+key: 	"ECB:STATE-HASH:TICK-{tick}"
+value:  The state hash at the end of the given tick: the root of a Merkle tree (see the merkle package) with a leaf for
+every component value of every entity. The tick number is zero padded to 20 digits so the keys sort in tick order. Keys
+older than the state hash retention (see SetStateHashRetention) are deleted.
+
+key: 	"ECB:STATE-HASH-OLDEST-TICK"
+value:  An integer that represents the oldest tick whose state hash has not been deleted. Like EVENTS-OLDEST-TICK, it
+makes sure every state hash that falls out of the retention is deleted, even if the retention was lowered.
+
 # In-memory storage model
 
 The in-memory data model roughly matches the model that is stored in redis, but there are some differences:
 
 Components are stored as generic interfaces and not as serialized JSON.
 
+Archetypes are found by a hash of their sorted component IDs, and the archetype that an entity moves to when a component
+is added or removed is cached per archetype. Both only exist in memory, and are rebuilt from the
+ECB:ARCHETYPE-ID-TO-COMPONENT-TYPES key.
+
+The index of the entities that refer to each entity through a relation component (see GetRelationSources) only exists
+in memory. It is built from the saved component values the first time it is needed.
+
+Field indexes (see GetEntitiesByIndex) also only exist in memory and are built the same way. Unlike the relation index,
+they only contain committed values and are updated in FinalizeTick. Pending changes are checked one by one.
+
+Values are loaded from redis lazily and then cached until the pending changes are committed or discarded. To save round
+trips, GetComponentsForEntities fetches every uncached value of a component with a single MGET, and the first time the
+entities of any archetype are needed, the entities of every archetype that has not been loaded yet are fetched with a
+single MGET as well.
+
 # Potential Improvements
 
 In redis, the ECB:ACTIVE-ENTITY-IDS and ECB:ARCHETYPE-ID:ENTITY-ID keys contains the same data, but are just reversed
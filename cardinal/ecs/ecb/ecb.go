@@ -4,8 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-
-	"pkg.world.dev/world-engine/cardinal/ecs/codec"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
@@ -15,6 +14,7 @@ import (
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	"pkg.world.dev/world-engine/cardinal/ecs/filter"
 	ecslog "pkg.world.dev/world-engine/cardinal/ecs/log"
+	"pkg.world.dev/world-engine/cardinal/ecs/merkle"
 	"pkg.world.dev/world-engine/cardinal/ecs/storage"
 	"pkg.world.dev/world-engine/cardinal/ecs/store"
 )
@@ -22,7 +22,12 @@ import (
 var _ store.IManager = &Manager{}
 
 type Manager struct {
-	client *redis.Client
+	kv KVStore
+
+	// mu guards the in-memory state below so that systems running in parallel can read and write entities at the
+	// same time. Only the Reader and Writer methods that systems use take the lock; tick lifecycle methods like
+	// StartNextTick, FinalizeTick and CommitPending must not be called while systems are running.
+	mu sync.Mutex
 
 	compValues         map[compKey]any
 	compValuesToDelete map[compKey]bool
@@ -41,6 +46,44 @@ type Manager struct {
 
 	archIDToComps  map[archetype.ID][]metadata.ComponentMetadata
 	pendingArchIDs []archetype.ID
+	// archIndex finds the archetype of a set of components, and caches the archetype changes of adding and removing
+	// components. It must be updated whenever archIDToComps changes.
+	archIndex *archetypeIndex
+
+	// Changes made since the last finalized tick, and the diff that was built by the last finalized tick.
+	diff         diffTracker
+	lastTickDiff *store.TickDiff
+
+	// Events emitted since the last commit, and the number of ticks events are kept for.
+	pendingEvents  []json.RawMessage
+	eventRetention uint64
+
+	// The current tick's submission to the base shard. See AddSubmission.
+	pendingSubmission []byte
+
+	// The Merkle tree of every committed component value. The tree is built by the first finalized tick, and then
+	// updated by every following finalized tick. The trees of the last proofRetention finalized ticks are kept so
+	// proofs can be built against them. stateMu is held while the trees and the committed state are changed, so
+	// proofs can be built while a tick is running.
+	stateMu           sync.RWMutex
+	stateTree         merkle.Tree
+	isStateTreeLoaded bool
+	stateTrees        map[uint64]merkle.Tree
+	proofRetention    uint64
+	// The number of ticks that state hashes are kept for.
+	stateHashRetention uint64
+
+	// The entities that refer to each entity through each relation component (see metadata.Relation). The index is
+	// built the first time it is needed, and then kept up to date until pending changes are discarded.
+	relationTypes         map[metadata.TypeID]bool
+	relationSources       map[relationKey]map[entity.ID]bool
+	isRelationIndexLoaded bool
+
+	// The index of every indexed component field (see metadata.ComponentMetadata.AddIndex). Unlike the relation
+	// index, these only contain committed values. They are loaded the first time they are needed, then updated by
+	// every finalized tick, and guarded by stateMu.
+	indexes         map[metadata.TypeID]map[string]*fieldIndex
+	isIndexesLoaded bool
 
 	logger *ecslog.Logger
 }
@@ -53,17 +96,32 @@ var (
 // NewManager creates a new command buffer manager that is able to queue up a series of states changes and
 // atomically commit them to the underlying redis storage layer.
 func NewManager(client *redis.Client) (*Manager, error) {
+	return NewManagerWithKVStore(NewRedisKVStore(client))
+}
+
+// NewManagerWithKVStore creates a new command buffer manager that is able to queue up a series of states changes and
+// atomically commit them to the given KVStore.
+func NewManagerWithKVStore(kv KVStore) (*Manager, error) {
 	m := &Manager{
-		client:             client,
+		kv:                 kv,
 		compValues:         map[compKey]any{},
 		compValuesToDelete: map[compKey]bool{},
 
 		activeEntities: map[archetype.ID]activeEntities{},
 		archIDToComps:  map[archetype.ID][]metadata.ComponentMetadata{},
+		archIndex:      newArchetypeIndex(nil),
 
 		entityIDToArchID:       map[entity.ID]archetype.ID{},
 		entityIDToOriginArchID: map[entity.ID]archetype.ID{},
 
+		diff: newDiffTracker(),
+
+		eventRetention: DefaultEventRetention,
+
+		stateTrees:         map[uint64]merkle.Tree{},
+		proofRetention:     DefaultProofRetention,
+		stateHashRetention: DefaultStateHashRetention,
+
 		// This field cannot be set until RegisterComponents is called
 		typeToComponent: nil,
 
@@ -77,8 +135,20 @@ func NewManager(client *redis.Client) (*Manager, error) {
 
 func (m *Manager) RegisterComponents(comps []metadata.ComponentMetadata) error {
 	m.typeToComponent = map[metadata.TypeID]metadata.ComponentMetadata{}
+	m.relationTypes = map[metadata.TypeID]bool{}
+	m.indexes = map[metadata.TypeID]map[string]*fieldIndex{}
+	m.isIndexesLoaded = false
 	for _, comp := range comps {
 		m.typeToComponent[comp.ID()] = comp
+		if metadata.IsRelation(comp) {
+			m.relationTypes[comp.ID()] = true
+		}
+		for _, field := range comp.Indexes() {
+			if m.indexes[comp.ID()] == nil {
+				m.indexes[comp.ID()] = map[string]*fieldIndex{}
+			}
+			m.indexes[comp.ID()][field] = newFieldIndex()
+		}
 	}
 
 	return m.loadArchIDs()
@@ -87,20 +157,24 @@ func (m *Manager) RegisterComponents(comps []metadata.ComponentMetadata) error {
 // CommitPending commits any pending state changes to the DB. If an error is returned, there will be no changes
 // to the underlying DB.
 func (m *Manager) CommitPending() error {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
 	ctx := context.Background()
-	pipe, err := m.makePipeOfRedisCommands(ctx)
+	batch, err := m.makeBatchOfCommands(ctx)
 	if err != nil {
 		return err
 	}
-	_, err = pipe.Exec(ctx)
-	if err != nil {
+	if err = batch.Exec(ctx); err != nil {
 		return err
 	}
 
 	m.pendingArchIDs = nil
 
-	// All changes were just successfully committed to redis, so stop tracking them locally
+	// All changes were just successfully committed to storage, so stop tracking them locally. The changes are not
+	// part of any tick, so the state tree must be rebuilt.
 	m.DiscardPending()
+	m.isStateTreeLoaded = false
+	m.isIndexesLoaded = false
 	return nil
 }
 
@@ -118,18 +192,36 @@ func (m *Manager) DiscardPending() {
 	m.isEntityIDLoaded = false
 	m.pendingEntityIDs = 0
 
+	m.archIndex.remove(m.pendingArchIDs, m.archIDToComps)
 	for _, archID := range m.pendingArchIDs {
 		delete(m.archIDToComps, archID)
 	}
 	m.pendingArchIDs = m.pendingArchIDs[:0]
+
+	m.diff.reset()
+	m.pendingEvents = nil
+	m.pendingSubmission = nil
+
+	m.relationSources = nil
+	m.isRelationIndexLoaded = false
 }
 
 // RemoveEntity removes the given entity from the ECS data model.
 func (m *Manager) RemoveEntity(idToRemove entity.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.removeEntity(idToRemove)
+}
+
+func (m *Manager) removeEntity(idToRemove entity.ID) error {
 	archID, err := m.getArchetypeForEntity(idToRemove)
 	if err != nil {
 		return err
 	}
+	comps := m.getComponentTypesForArchID(archID)
+	if err = m.relationsRemoved(comps, idToRemove); err != nil {
+		return err
+	}
 	active, err := m.getActiveEntities(archID)
 	if err != nil {
 		return err
@@ -144,8 +236,8 @@ func (m *Manager) RemoveEntity(idToRemove entity.ID) error {
 		m.entityIDToOriginArchID[idToRemove] = archID
 	}
 	delete(m.entityIDToArchID, idToRemove)
+	m.diff.entityRemoved(idToRemove, archID)
 
-	comps := m.GetComponentTypesForArchID(archID)
 	for _, comp := range comps {
 		key := compKey{comp.ID(), idToRemove}
 		delete(m.compValues, key)
@@ -157,7 +249,9 @@ func (m *Manager) RemoveEntity(idToRemove entity.ID) error {
 
 // CreateEntity creates a single entity with the given set of components.
 func (m *Manager) CreateEntity(comps ...metadata.ComponentMetadata) (entity.ID, error) {
-	ids, err := m.CreateManyEntities(1, comps...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids, err := m.createManyEntities(1, comps...)
 	if err != nil {
 		return 0, err
 	}
@@ -166,6 +260,12 @@ func (m *Manager) CreateEntity(comps ...metadata.ComponentMetadata) (entity.ID,
 
 // CreateManyEntities creates many entities with the given set of components.
 func (m *Manager) CreateManyEntities(num int, comps ...metadata.ComponentMetadata) ([]entity.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createManyEntities(num, comps...)
+}
+
+func (m *Manager) createManyEntities(num int, comps ...metadata.ComponentMetadata) ([]entity.ID, error) {
 	archID, err := m.getOrMakeArchIDForComponents(comps)
 	if err != nil {
 		return nil, err
@@ -186,36 +286,62 @@ func (m *Manager) CreateManyEntities(num int, comps ...metadata.ComponentMetadat
 		m.entityIDToOriginArchID[currID] = doesNotExistArchetypeID
 		active.ids = append(active.ids, currID)
 		active.modified = true
+		m.diff.entityCreated(currID)
 		m.logger.LogEntity(zerolog.DebugLevel, currID, archID, comps)
 	}
 	m.setActiveEntities(archID, active)
+	for _, id := range ids {
+		if err = m.relationsAdded(comps, id); err != nil {
+			return nil, err
+		}
+	}
 	return ids, nil
 }
 
 // SetComponentForEntity sets the given entity's component data to the given value.
 func (m *Manager) SetComponentForEntity(cType metadata.ComponentMetadata, id entity.ID, value any) error {
-	comps, err := m.GetComponentTypesForEntity(id)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.setComponentForEntity(cType, id, value)
+}
+
+func (m *Manager) setComponentForEntity(cType metadata.ComponentMetadata, id entity.ID, value any) error {
+	comps, err := m.getComponentTypesForEntity(id)
 	if err != nil {
 		return err
 	}
 	if !filter.MatchComponentMetaData(comps, cType) {
 		return storage.ErrComponentNotOnEntity
 	}
+	if err = m.checkRelationTarget(cType, value); err != nil {
+		return err
+	}
+	relation := []metadata.ComponentMetadata{cType}
+	if err = m.relationsRemoved(relation, id); err != nil {
+		return err
+	}
 
 	key := compKey{cType.ID(), id}
 	m.compValues[key] = value
-	return nil
+	m.diff.componentSet(key)
+	return m.relationsAdded(relation, id)
 }
 
 // GetComponentForEntity returns the saved component data for the given entity.
 func (m *Manager) GetComponentForEntity(cType metadata.ComponentMetadata, id entity.ID) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getComponentForEntity(cType, id)
+}
+
+func (m *Manager) getComponentForEntity(cType metadata.ComponentMetadata, id entity.ID) (any, error) {
 	key := compKey{cType.ID(), id}
 	value, ok := m.compValues[key]
 	if ok {
 		return value, nil
 	}
 	// Make sure this entity has this component
-	comps, err := m.GetComponentTypesForEntity(id)
+	comps, err := m.getComponentTypesForEntity(id)
 	if err != nil {
 		return nil, err
 	}
@@ -223,13 +349,13 @@ func (m *Manager) GetComponentForEntity(cType metadata.ComponentMetadata, id ent
 		return nil, storage.ErrComponentNotOnEntity
 	}
 
-	// Fetch the value from redis
+	// Fetch the value from storage
 	redisKey := redisComponentKey(cType.ID(), id)
 	ctx := context.Background()
 
-	bz, err := m.client.Get(ctx, redisKey).Bytes()
+	bz, err := m.kv.Get(ctx, redisKey)
 	if err != nil {
-		if !errors.Is(err, redis.Nil) {
+		if !errors.Is(err, ErrKeyNotFound) {
 			return nil, err
 		}
 		// This value has never been set. Make a default value.
@@ -249,7 +375,14 @@ func (m *Manager) GetComponentForEntity(cType metadata.ComponentMetadata, id ent
 // GetComponentForEntityInRawJSON returns the saved component data as JSON encoded bytes for the given entity.
 func (m *Manager) GetComponentForEntityInRawJSON(cType metadata.ComponentMetadata, id entity.ID) (
 	json.RawMessage, error) {
-	value, err := m.GetComponentForEntity(cType, id)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getComponentForEntityInRawJSON(cType, id)
+}
+
+func (m *Manager) getComponentForEntityInRawJSON(cType metadata.ComponentMetadata, id entity.ID) (
+	json.RawMessage, error) {
+	value, err := m.getComponentForEntity(cType, id)
 	if err != nil {
 		return nil, err
 	}
@@ -259,59 +392,58 @@ func (m *Manager) GetComponentForEntityInRawJSON(cType metadata.ComponentMetadat
 // AddComponentToEntity adds the given component to the given entity. An error is returned if the entity
 // already has this component.
 func (m *Manager) AddComponentToEntity(cType metadata.ComponentMetadata, id entity.ID) error {
-	fromComps, err := m.GetComponentTypesForEntity(id)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addComponentToEntity(cType, id)
+}
+
+func (m *Manager) addComponentToEntity(cType metadata.ComponentMetadata, id entity.ID) error {
+	fromArchID, err := m.getArchetypeForEntity(id)
 	if err != nil {
 		return err
 	}
-	if filter.MatchComponentMetaData(fromComps, cType) {
+	if filter.MatchComponentMetaData(m.getComponentTypesForArchID(fromArchID), cType) {
 		return storage.ErrComponentAlreadyOnEntity
 	}
-	toComps := append(fromComps, cType) //nolint:gocritic // easier this way.
-	if err = sortComponentSet(toComps); err != nil {
-		return err
-	}
-
-	toArchID, err := m.getOrMakeArchIDForComponents(toComps)
+	toArchID, err := m.getArchIDAfterTransition(fromArchID, cType, true)
 	if err != nil {
 		return err
 	}
-	fromArchID, err := m.getOrMakeArchIDForComponents(fromComps)
-	if err != nil {
+	m.diff.componentSet(compKey{cType.ID(), id})
+	if err = m.moveEntityByArchetype(fromArchID, toArchID, id); err != nil {
 		return err
 	}
-	return m.moveEntityByArchetype(fromArchID, toArchID, id)
+	return m.relationsAdded([]metadata.ComponentMetadata{cType}, id)
 }
 
 // RemoveComponentFromEntity removes the given component from the given entity. An error is returned if the entity
 // does not have the component.
 func (m *Manager) RemoveComponentFromEntity(cType metadata.ComponentMetadata, id entity.ID) error {
-	comps, err := m.GetComponentTypesForEntity(id)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.removeComponentFromEntity(cType, id)
+}
+
+func (m *Manager) removeComponentFromEntity(cType metadata.ComponentMetadata, id entity.ID) error {
+	fromArchID, err := m.getArchetypeForEntity(id)
 	if err != nil {
 		return err
 	}
-	newCompSet := make([]metadata.ComponentMetadata, 0, len(comps)-1)
-	found := false
-	for _, comp := range comps {
-		if comp.ID() == cType.ID() {
-			found = true
-			continue
-		}
-		newCompSet = append(newCompSet, comp)
-	}
-	if !found {
+	comps := m.getComponentTypesForArchID(fromArchID)
+	if !filter.MatchComponentMetaData(comps, cType) {
 		return storage.ErrComponentNotOnEntity
 	}
-	if len(newCompSet) == 0 {
+	if len(comps) == 1 {
 		return storage.ErrEntityMustHaveAtLeastOneComponent
 	}
+	if err = m.relationsRemoved([]metadata.ComponentMetadata{cType}, id); err != nil {
+		return err
+	}
 	key := compKey{cType.ID(), id}
 	delete(m.compValues, key)
 	m.compValuesToDelete[key] = true
-	fromArchID, err := m.getOrMakeArchIDForComponents(comps)
-	if err != nil {
-		return err
-	}
-	toArchID, err := m.getOrMakeArchIDForComponents(newCompSet)
+	m.diff.componentRemoved(key)
+	toArchID, err := m.getArchIDAfterTransition(fromArchID, cType, false)
 	if err != nil {
 		return err
 	}
@@ -321,38 +453,60 @@ func (m *Manager) RemoveComponentFromEntity(cType metadata.ComponentMetadata, id
 // GetComponentTypesForEntity returns all the component types that are currently on the given entity. Only types
 // are returned. To get the actual component data, use GetComponentForEntity.
 func (m *Manager) GetComponentTypesForEntity(id entity.ID) ([]metadata.ComponentMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getComponentTypesForEntity(id)
+}
+
+func (m *Manager) getComponentTypesForEntity(id entity.ID) ([]metadata.ComponentMetadata, error) {
 	archID, err := m.getArchetypeForEntity(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.GetComponentTypesForArchID(archID), nil
+	return m.getComponentTypesForArchID(archID), nil
 }
 
 // GetComponentTypesForArchID returns the set of components that are associated with the given archetype id.
 func (m *Manager) GetComponentTypesForArchID(archID archetype.ID) []metadata.ComponentMetadata {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getComponentTypesForArchID(archID)
+}
+
+func (m *Manager) getComponentTypesForArchID(archID archetype.ID) []metadata.ComponentMetadata {
 	return m.archIDToComps[archID]
 }
 
 // GetArchIDForComponents returns the archetype ID that has been assigned to this set of components.
 // If this set of components does not have an archetype ID assigned to it, an error is returned.
 func (m *Manager) GetArchIDForComponents(components []metadata.ComponentMetadata) (archetype.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getArchIDForComponents(components)
+}
+
+func (m *Manager) getArchIDForComponents(components []metadata.ComponentMetadata) (archetype.ID, error) {
 	if len(components) == 0 {
 		return 0, errors.New("must provide at least 1 component")
 	}
 	if err := sortComponentSet(components); err != nil {
 		return 0, err
 	}
-	for archID, comps := range m.archIDToComps {
-		if isComponentSetMatch(comps, components) {
-			return archID, nil
-		}
+	if archID, ok := m.archIndex.get(components); ok {
+		return archID, nil
 	}
 	return 0, ErrArchetypeNotFound
 }
 
 // GetEntitiesForArchID returns all the entities that currently belong to the given archetype ID.
 func (m *Manager) GetEntitiesForArchID(archID archetype.ID) ([]entity.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.getEntitiesForArchID(archID)
+}
+
+func (m *Manager) getEntitiesForArchID(archID archetype.ID) ([]entity.ID, error) {
 	active, err := m.getActiveEntities(archID)
 	if err != nil {
 		return nil, err
@@ -363,6 +517,12 @@ func (m *Manager) GetEntitiesForArchID(archID archetype.ID) ([]entity.ID, error)
 // SearchFrom returns an ArchetypeIterator based on a component filter. The iterator will iterate over all archetypes
 // that match the given filter.
 func (m *Manager) SearchFrom(filter filter.ComponentFilter, start int) *storage.ArchetypeIterator {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.searchFrom(filter, start)
+}
+
+func (m *Manager) searchFrom(filter filter.ComponentFilter, start int) *storage.ArchetypeIterator {
 	itr := &storage.ArchetypeIterator{}
 	for i := start; i < len(m.archIDToComps); i++ {
 		archID := archetype.ID(i)
@@ -376,6 +536,12 @@ func (m *Manager) SearchFrom(filter filter.ComponentFilter, start int) *storage.
 
 // ArchetypeCount returns the number of archetypes that have been generated.
 func (m *Manager) ArchetypeCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.archetypeCount()
+}
+
+func (m *Manager) archetypeCount() int {
 	return len(m.archIDToComps)
 }
 
@@ -386,13 +552,7 @@ func (m *Manager) InjectLogger(logger *ecslog.Logger) {
 
 // Close closes the manager.
 func (m *Manager) Close() error {
-	err := m.client.Close()
-	if errors.Is(err, redis.ErrClosed) {
-		// if redis is already closed that means another shutdown pathway got to it first.
-		// There are multiple modules that will try to shutdown redis, if it is already shutdown it is not an error.
-		return nil
-	}
-	return err
+	return m.kv.Close()
 }
 
 // getArchetypeForEntity returns the archetype ID for the given entity ID.
@@ -402,7 +562,7 @@ func (m *Manager) getArchetypeForEntity(id entity.ID) (archetype.ID, error) {
 		return archID, nil
 	}
 	key := redisArchetypeIDForEntityID(id)
-	num, err := m.client.Get(context.Background(), key).Int()
+	num, err := getInt(context.Background(), m.kv, key)
 	if err != nil {
 		return 0, err
 	}
@@ -416,12 +576,12 @@ func (m *Manager) nextEntityID() (entity.ID, error) {
 	if !m.isEntityIDLoaded {
 		// The next valid entity ID needs to be loaded from storage.
 		ctx := context.Background()
-		nextID, err := m.client.Get(ctx, redisNextEntityIDKey()).Uint64()
+		nextID, err := getUint64(ctx, m.kv, redisNextEntityIDKey())
 		if err != nil {
-			if !errors.Is(err, redis.Nil) {
+			if !errors.Is(err, ErrKeyNotFound) {
 				return 0, err
 			}
-			// redis.Nil means there's no value at this key. Start with an ID of 0
+			// ErrKeyNotFound means there's no value at this key. Start with an ID of 0
 			nextID = 0
 		}
 		m.nextEntityIDSaved = nextID
@@ -438,7 +598,7 @@ func (m *Manager) nextEntityID() (entity.ID, error) {
 // has already been assigned an archetype ID, that ID is returned. If this is a new set of components, an archetype ID
 // is generated.
 func (m *Manager) getOrMakeArchIDForComponents(comps []metadata.ComponentMetadata) (archetype.ID, error) {
-	archID, err := m.GetArchIDForComponents(comps)
+	archID, err := m.getArchIDForComponents(comps)
 	if err == nil {
 		return archID, nil
 	}
@@ -449,6 +609,7 @@ func (m *Manager) getOrMakeArchIDForComponents(comps []metadata.ComponentMetadat
 	id := archetype.ID(len(m.archIDToComps))
 	m.pendingArchIDs = append(m.pendingArchIDs, id)
 	m.archIDToComps[id] = comps
+	m.archIndex.add(id, comps)
 	m.logger.Debug().Int("archetype_id", int(id)).Msg("created")
 	return id, nil
 }
@@ -456,28 +617,13 @@ func (m *Manager) getOrMakeArchIDForComponents(comps []metadata.ComponentMetadat
 // getActiveEntities returns the entities that are currently assigned to the given archetype ID.
 func (m *Manager) getActiveEntities(archID archetype.ID) (activeEntities, error) {
 	active, ok := m.activeEntities[archID]
-	// The active entities for this archetype ID has not yet been loaded from storage
 	if ok {
-		return m.activeEntities[archID], nil
+		return active, nil
 	}
-	ctx := context.Background()
-	key := redisActiveEntityIDKey(archID)
-	bz, err := m.client.Get(ctx, key).Bytes()
-	var ids []entity.ID
-	if err != nil {
-		if !errors.Is(err, redis.Nil) {
-			return active, err
-		}
-	} else {
-		ids, err = codec.Decode[[]entity.ID](bz)
-		if err != nil {
-			return active, err
-		}
-	}
-
-	m.activeEntities[archID] = activeEntities{
-		ids:      ids,
-		modified: false,
+	// The active entities for this archetype ID has not yet been loaded from storage. The other archetypes that have
+	// not been loaded are fetched in the same round trip.
+	if err := m.loadActiveEntities(context.Background(), archID); err != nil {
+		return active, err
 	}
 	return m.activeEntities[archID], nil
 }
@@ -495,6 +641,7 @@ func (m *Manager) moveEntityByArchetype(fromArchID, toArchID archetype.ID, id en
 		m.entityIDToOriginArchID[id] = fromArchID
 	}
 	m.entityIDToArchID[id] = toArchID
+	m.diff.entityMoved(id, fromArchID)
 
 	active, err := m.getActiveEntities(fromArchID)
 	if err != nil {
@@ -1,6 +1,7 @@
 package ecb_test
 
 import (
+	"sync"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -524,3 +525,105 @@ func TestCannotSaveStateBeforeRegisteringComponents(t *testing.T) {
 	assert.NilError(t, manager.RegisterComponents(allComponents))
 	assert.NilError(t, manager.CommitPending())
 }
+
+func TestManagerCanBeUsedConcurrentlyForDisjointComponents(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ids, err := manager.CreateManyEntities(50, fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.CommitPending())
+
+	// One goroutine updates every foo while another updates every bar. The values are loaded from storage at the same
+	// time, so this test is only meaningful when run with the race detector.
+	var wg sync.WaitGroup
+	for _, comp := range allComponents {
+		wg.Add(1)
+		go func(comp metadata.ComponentMetadata) {
+			defer wg.Done()
+			for i, id := range ids {
+				var value any = Foo{Value: i}
+				if comp.Name() == barComp.Name() {
+					value = Bar{Value: i}
+				}
+				_, err := manager.GetComponentForEntity(comp, id)
+				assert.Check(t, err)
+				assert.Check(t, manager.SetComponentForEntity(comp, id, value))
+			}
+		}(comp)
+	}
+	wg.Wait()
+	assert.NilError(t, manager.CommitPending())
+
+	for i, id := range ids {
+		foo, err := manager.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, i, foo.(Foo).Value)
+		bar, err := manager.GetComponentForEntity(barComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, i, bar.(Bar).Value)
+	}
+}
+
+func TestCanGetAndSetComponentsForManyEntities(t *testing.T) {
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ids, err := manager.CreateManyEntities(3, fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[1], Foo{1}))
+	assert.NilError(t, manager.CommitPending())
+
+	// A new manager has nothing in memory, so every value is fetched from redis.
+	manager, _ = newCmdBufferAndRedisClientForTest(t, client)
+	values, err := manager.GetComponentsForEntities(fooComp, ids)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []any{Foo{0}, Foo{1}, Foo{0}}, values)
+	values, err = manager.ToReadOnly().GetComponentsForEntities(fooComp, ids)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []any{Foo{0}, Foo{1}, Foo{0}}, values)
+
+	assert.NilError(t, manager.SetComponentsForEntities(fooComp, ids[1:], []any{Foo{2}, Foo{3}}))
+	values, err = manager.GetComponentsForEntities(fooComp, ids)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []any{Foo{0}, Foo{2}, Foo{3}}, values)
+	assert.ErrorContains(t, manager.SetComponentsForEntities(fooComp, ids, []any{Foo{4}}), "3 entities but 1 values")
+
+	_, err = manager.GetComponentsForEntities(barComp, ids)
+	assert.ErrorIs(t, err, storage.ErrComponentNotOnEntity)
+	assert.ErrorIs(t, manager.SetComponentsForEntities(barComp, ids[:1], []any{Bar{1}}), storage.ErrComponentNotOnEntity)
+}
+
+func TestDiscardedArchetypesAreRemovedFromArchetypeLookups(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.CommitPending())
+	fooArchID, err := manager.GetArchIDForComponents([]metadata.ComponentMetadata{fooComp})
+	assert.NilError(t, err)
+
+	// Adding bar creates a pending archetype, which is thrown away with the other pending changes.
+	assert.NilError(t, manager.AddComponentToEntity(barComp, id))
+	manager.DiscardPending()
+	assert.Equal(t, 1, manager.ArchetypeCount())
+	_, err = manager.GetArchIDForComponents([]metadata.ComponentMetadata{barComp, fooComp})
+	assert.ErrorIs(t, err, ecb.ErrArchetypeNotFound)
+
+	// The discarded archetype ID is reused for a different set of components, so adding bar again must not move the
+	// entity to it.
+	_, err = manager.CreateEntity(barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.AddComponentToEntity(barComp, id))
+	comps, err := manager.GetComponentTypesForEntity(id)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(comps))
+	assert.Equal(t, comps[0].ID(), fooComp.ID())
+	assert.Equal(t, comps[1].ID(), barComp.ID())
+	assert.Equal(t, 3, manager.ArchetypeCount())
+
+	// Removing bar moves the entity back to its original archetype.
+	assert.NilError(t, manager.RemoveComponentFromEntity(barComp, id))
+	comps, err = manager.GetComponentTypesForEntity(id)
+	assert.NilError(t, err)
+	archID, err := manager.GetArchIDForComponents(comps)
+	assert.NilError(t, err)
+	assert.Equal(t, fooArchID, archID)
+	assert.Equal(t, 1, len(comps))
+	assert.Equal(t, comps[0].ID(), fooComp.ID())
+}
@@ -0,0 +1,123 @@
+package ecb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+// DefaultEventRetention is the number of ticks that emitted events are kept for, unless SetEventRetention is called.
+const DefaultEventRetention = 1000
+
+var _ store.EventStorage = &Manager{}
+
+// AddEvent saves the given JSON encoded event as part of the current tick. The event is written to the DB along with
+// the rest of the tick's state changes.
+func (m *Manager) AddEvent(event json.RawMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingEvents = append(m.pendingEvents, event)
+}
+
+// SetEventRetention sets the number of ticks that events are kept for. A retention of 0 keeps events forever.
+func (m *Manager) SetEventRetention(ticks uint64) {
+	m.eventRetention = ticks
+}
+
+// GetEvents returns the saved events that were emitted during or after the given tick, in the order they were emitted.
+// A single call reads the events of at most MaxReplayTicks ticks, and stops at the end of the tick in which
+// MaxReplayEvents events were reached. If there are more saved events, nextTick is the tick to call GetEvents with
+// next; otherwise it is 0. Only the KVStore is read, so this is safe to call while a tick is running.
+func (m *Manager) GetEvents(fromTick uint64) (evts []json.RawMessage, nextTick uint64, err error) {
+	ctx := context.Background()
+	endTick, err := getUint64(ctx, m.kv, redisEndTickKey())
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return nil, 0, err
+	}
+	oldestTick, _, err := m.getOldestTick(ctx, redisEventsPrefix, redisEventsOldestTickKey(), endTick+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	fromTick = max(fromTick, oldestTick)
+	if fromTick > endTick {
+		return nil, 0, nil
+	}
+	// Events are saved under the tick that was running when they were committed, which is at most the end tick. Every
+	// key in the range is fetched, since ticks that did not emit any events simply have no key.
+	lastTick := min(endTick, fromTick+MaxReplayTicks-1)
+	keys := make([]string, 0, lastTick-fromTick+1)
+	for tick := fromTick; tick <= lastTick; tick++ {
+		keys = append(keys, redisEventsKey(tick))
+	}
+	bzs, err := m.kv.MGet(ctx, keys)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, bz := range bzs {
+		if bz == nil {
+			continue
+		}
+		var tickEvents []json.RawMessage
+		if err = json.Unmarshal(bz, &tickEvents); err != nil {
+			return nil, 0, err
+		}
+		evts = append(evts, tickEvents...)
+		if len(evts) >= MaxReplayEvents {
+			lastTick = fromTick + uint64(i)
+			break
+		}
+	}
+	if lastTick < endTick {
+		nextTick = lastTick + 1
+	}
+	return evts, nextTick, nil
+}
+
+const (
+	// MaxReplayTicks is the maximum number of ticks whose events are read by a single call to GetEvents.
+	MaxReplayTicks = 1000
+	// MaxReplayEvents is the number of events after which GetEvents stops reading more ticks. The events of a tick
+	// are never split across calls, so more events than this are returned if the last tick has many events.
+	MaxReplayEvents = 1000
+)
+
+func (m *Manager) getSavedEvents(ctx context.Context, key string) ([]json.RawMessage, error) {
+	bz, err := m.kv.Get(ctx, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var evts []json.RawMessage
+	if err = json.Unmarshal(bz, &evts); err != nil {
+		return nil, err
+	}
+	return evts, nil
+}
+
+// addEventsToBatch saves the pending events under the current tick, and deletes the events of every tick that has
+// fallen out of the retention window.
+func (m *Manager) addEventsToBatch(ctx context.Context, batch KVBatch) error {
+	tick, err := getUint64(ctx, m.kv, redisEndTickKey())
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+	if len(m.pendingEvents) > 0 {
+		key := redisEventsKey(tick)
+		// Events may have already been committed for this tick.
+		evts, err := m.getSavedEvents(ctx, key)
+		if err != nil {
+			return err
+		}
+		bz, err := json.Marshal(append(evts, m.pendingEvents...))
+		if err != nil {
+			return err
+		}
+		if err = batch.Set(ctx, key, bz); err != nil {
+			return err
+		}
+	}
+	return m.addPrunedTicksToBatch(ctx, batch, redisEventsPrefix, redisEventsOldestTickKey(), tick, m.eventRetention)
+}
@@ -0,0 +1,110 @@
+package ecb_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+)
+
+func TestEventsAreSavedWhenTheTickIsFinalized(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	manager.AddEvent(json.RawMessage(`"tick-0-a"`))
+	manager.AddEvent(json.RawMessage(`"tick-0-b"`))
+	evts, _, err := manager.GetEvents(0)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(evts))
+
+	assert.NilError(t, manager.FinalizeTick())
+	// A tick without events.
+	assert.NilError(t, manager.FinalizeTick())
+	manager.AddEvent(json.RawMessage(`"tick-2"`))
+	assert.NilError(t, manager.FinalizeTick())
+
+	evts, _, err = manager.GetEvents(0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []json.RawMessage{[]byte(`"tick-0-a"`), []byte(`"tick-0-b"`), []byte(`"tick-2"`)}, evts)
+	evts, _, err = manager.GetEvents(1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []json.RawMessage{[]byte(`"tick-2"`)}, evts)
+	evts, _, err = manager.GetEvents(3)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(evts))
+}
+
+func TestDiscardedEventsAreNotSaved(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	manager.AddEvent(json.RawMessage(`"discarded"`))
+	manager.DiscardPending()
+	assert.NilError(t, manager.FinalizeTick())
+	evts, _, err := manager.GetEvents(0)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(evts))
+}
+
+func TestEventsOutsideTheRetentionAreDeleted(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	manager.SetEventRetention(2)
+	for _, event := range []string{`0`, `1`, `2`, `3`} {
+		manager.AddEvent(json.RawMessage(event))
+		assert.NilError(t, manager.FinalizeTick())
+	}
+	evts, _, err := manager.GetEvents(0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []json.RawMessage{[]byte(`2`), []byte(`3`)}, evts)
+}
+
+func TestLoweringTheRetentionDeletesEveryOldTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	for _, event := range []string{`0`, `1`, `2`, `3`} {
+		manager.AddEvent(json.RawMessage(event))
+		assert.NilError(t, manager.FinalizeTick())
+	}
+	manager.SetEventRetention(1)
+	manager.AddEvent(json.RawMessage(`4`))
+	assert.NilError(t, manager.FinalizeTick())
+	evts, _, err := manager.GetEvents(0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []json.RawMessage{[]byte(`4`)}, evts)
+
+	// Raising the retention again does not bring back the deleted events.
+	manager.SetEventRetention(10)
+	assert.NilError(t, manager.FinalizeTick())
+	evts, _, err = manager.GetEvents(0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []json.RawMessage{[]byte(`4`)}, evts)
+}
+
+func TestEventReplayIsSplitAcrossCalls(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	manager.SetEventRetention(0)
+	for i := 0; i < ecb.MaxReplayEvents; i++ {
+		manager.AddEvent(json.RawMessage(`"many"`))
+	}
+	assert.NilError(t, manager.FinalizeTick())
+	for i := 0; i < ecb.MaxReplayTicks; i++ {
+		assert.NilError(t, manager.FinalizeTick())
+	}
+	manager.AddEvent(json.RawMessage(`"last"`))
+	assert.NilError(t, manager.FinalizeTick())
+	lastTick := uint64(ecb.MaxReplayTicks + 1)
+
+	// The first call stops after the tick that reached the event limit.
+	evts, nextTick, err := manager.GetEvents(0)
+	assert.NilError(t, err)
+	assert.Equal(t, ecb.MaxReplayEvents, len(evts))
+	assert.Equal(t, uint64(1), nextTick)
+
+	// The next call stops at the tick limit, even though it found no events.
+	evts, nextTick, err = manager.GetEvents(nextTick)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(evts))
+	assert.Equal(t, lastTick, nextTick)
+
+	evts, nextTick, err = manager.GetEvents(nextTick)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []json.RawMessage{[]byte(`"last"`)}, evts)
+	assert.Equal(t, uint64(0), nextTick)
+}
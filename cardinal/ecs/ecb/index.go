@@ -0,0 +1,299 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/codec"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+// fieldIndex maps the index keys of a single field of a single component type to the entities that have those keys.
+type fieldIndex struct {
+	entities map[string]map[entity.ID]bool
+	keys     map[entity.ID][]string
+}
+
+func newFieldIndex() *fieldIndex {
+	return &fieldIndex{
+		entities: map[string]map[entity.ID]bool{},
+		keys:     map[entity.ID][]string{},
+	}
+}
+
+func (f *fieldIndex) set(id entity.ID, keys []string) {
+	f.remove(id)
+	for _, key := range keys {
+		ids, ok := f.entities[key]
+		if !ok {
+			ids = map[entity.ID]bool{}
+			f.entities[key] = ids
+		}
+		ids[id] = true
+	}
+	f.keys[id] = keys
+}
+
+func (f *fieldIndex) remove(id entity.ID) {
+	for _, key := range f.keys[id] {
+		delete(f.entities[key], id)
+		if len(f.entities[key]) == 0 {
+			delete(f.entities, key)
+		}
+	}
+	delete(f.keys, id)
+}
+
+// GetEntitiesByIndex returns the entities whose given indexed field of the given component matches the given value,
+// including any pending changes. The entities are sorted by ID.
+func (m *Manager) GetEntitiesByIndex(cType metadata.ComponentMetadata, field string, value any) ([]entity.ID, error) {
+	key, err := metadata.IndexKey(value)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := m.getCommittedEntitiesByIndex(cType, field, key)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The index only contains committed values, so every entity whose component changed since the last finalized tick
+	// has to be checked as well.
+	candidates := committed
+	for changed := range m.diff.setComps {
+		if changed.typeID == cType.ID() {
+			candidates[changed.entityID] = true
+		}
+	}
+	for id := range m.diff.createdEntities {
+		candidates[id] = true
+	}
+	matches := map[entity.ID]bool{}
+	for id := range candidates {
+		ok, err := m.componentMatchesIndexKey(cType, field, id, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches[id] = true
+		}
+	}
+	return sortedEntityIDs(matches), nil
+}
+
+// componentMatchesIndexKey returns true if the given entity exists, has the given component, and the current value of
+// the given field has the given index key.
+func (m *Manager) componentMatchesIndexKey(cType metadata.ComponentMetadata, field string, id entity.ID,
+	key string) (bool, error) {
+	exists, err := m.entityExists(id)
+	if err != nil || !exists {
+		return false, err
+	}
+	value, err := m.getComponentForEntity(cType, id)
+	if errors.Is(err, storage.ErrComponentNotOnEntity) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	keys, err := cType.IndexKeys(value, field)
+	if err != nil {
+		return false, err
+	}
+	for _, k := range keys {
+		if k == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getCommittedEntitiesByIndex returns the committed entities that have the given key in the given field index. The
+// indexes are loaded from storage the first time they are needed.
+func (m *Manager) getCommittedEntitiesByIndex(cType metadata.ComponentMetadata, field, key string) (
+	map[entity.ID]bool, error) {
+	m.stateMu.RLock()
+	for !m.isIndexesLoaded {
+		m.stateMu.RUnlock()
+		m.stateMu.Lock()
+		err := m.loadIndexes(context.Background())
+		m.stateMu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		m.stateMu.RLock()
+	}
+	defer m.stateMu.RUnlock()
+
+	index, ok := m.indexes[cType.ID()][field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s.%s", storage.ErrFieldNotIndexed, cType.Name(), field)
+	}
+	ids := make(map[entity.ID]bool, len(index.entities[key]))
+	for id := range index.entities[key] {
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+// loadIndexes builds every field index from the committed state in the KVStore. The indexed values of each archetype
+// are fetched with a single MGet.
+func (m *Manager) loadIndexes(ctx context.Context) error {
+	if m.isIndexesLoaded {
+		return nil
+	}
+	for _, fields := range m.indexes {
+		for field := range fields {
+			fields[field] = newFieldIndex()
+		}
+	}
+	// archIDToComps is empty if nothing has been saved yet.
+	archIDToComps, _, err := getArchIDToCompTypesFromKV(m.kv, m.typeToComponent)
+	if err != nil {
+		return err
+	}
+	for archID, comps := range archIDToComps {
+		var indexed []metadata.ComponentMetadata
+		for _, comp := range comps {
+			if len(m.indexes[comp.ID()]) > 0 {
+				indexed = append(indexed, comp)
+			}
+		}
+		if len(indexed) == 0 {
+			continue
+		}
+		ids, err := getCommittedEntitiesForArchID(ctx, m.kv, archID)
+		if err != nil {
+			return err
+		}
+		comps := make([]metadata.ComponentMetadata, 0, len(ids)*len(indexed))
+		entityIDs := make([]entity.ID, 0, len(ids)*len(indexed))
+		for _, id := range ids {
+			for _, comp := range indexed {
+				comps = append(comps, comp)
+				entityIDs = append(entityIDs, id)
+			}
+		}
+		// The indexed values of the whole archetype are fetched at once.
+		bzs, err := getCommittedEncodedComponents(ctx, m.kv, comps, entityIDs)
+		if err != nil {
+			return err
+		}
+		for i, bz := range bzs {
+			value, err := comps[i].Decode(bz)
+			if err != nil {
+				return err
+			}
+			if err = m.setIndexKeys(comps[i], entityIDs[i], value); err != nil {
+				return err
+			}
+		}
+	}
+	m.isIndexesLoaded = true
+	return nil
+}
+
+// updateIndexes applies the changes that were made since the last finalized tick to the indexes, if they are loaded.
+func (m *Manager) updateIndexes(ctx context.Context) error {
+	if !m.isIndexesLoaded {
+		return nil
+	}
+	for id := range m.diff.removedEntities {
+		for _, fields := range m.indexes {
+			for _, index := range fields {
+				index.remove(id)
+			}
+		}
+	}
+	for key := range m.diff.removedComps {
+		for _, index := range m.indexes[key.typeID] {
+			index.remove(key.entityID)
+		}
+	}
+	for id := range m.diff.createdEntities {
+		comps, err := m.getComponentTypesForEntity(id)
+		if err != nil {
+			return err
+		}
+		for _, comp := range comps {
+			if err = m.updateIndexKeys(ctx, comp, id); err != nil {
+				return err
+			}
+		}
+	}
+	for key := range m.diff.setComps {
+		if _, ok := m.entityIDToArchID[key.entityID]; !ok || m.diff.createdEntities[key.entityID] {
+			continue
+		}
+		if err := m.updateIndexKeys(ctx, m.typeToComponent[key.typeID], key.entityID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) updateIndexKeys(ctx context.Context, cType metadata.ComponentMetadata, id entity.ID) error {
+	if len(m.indexes[cType.ID()]) == 0 {
+		return nil
+	}
+	value, err := m.getUncachedComponent(ctx, cType, id)
+	if err != nil {
+		return err
+	}
+	return m.setIndexKeys(cType, id, value)
+}
+
+func (m *Manager) setIndexKeys(cType metadata.ComponentMetadata, id entity.ID, value any) error {
+	for field, index := range m.indexes[cType.ID()] {
+		keys, err := cType.IndexKeys(value, field)
+		if err != nil {
+			return err
+		}
+		index.set(id, keys)
+	}
+	return nil
+}
+
+// getUncachedComponent returns the current value of the given component. Unlike getComponentForEntity, values that
+// are only in the KVStore are not cached.
+func (m *Manager) getUncachedComponent(ctx context.Context, cType metadata.ComponentMetadata, id entity.ID) (
+	any, error) {
+	if value, ok := m.compValues[compKey{cType.ID(), id}]; ok {
+		return value, nil
+	}
+	bz, err := m.getEncodedComponent(ctx, cType, id)
+	if err != nil {
+		return nil, err
+	}
+	return cType.Decode(bz)
+}
+
+// getCommittedEntitiesForArchID returns the entities that are saved in the KVStore for the given archetype.
+func getCommittedEntitiesForArchID(ctx context.Context, kv KVStore, archID archetype.ID) ([]entity.ID, error) {
+	bz, err := kv.Get(ctx, redisActiveEntityIDKey(archID))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return codec.Decode[[]entity.ID](bz)
+}
+
+// GetEntitiesByIndex returns the committed entities whose given indexed field of the given component matches the
+// given value, sorted by ID.
+func (r *readOnlyManager) GetEntitiesByIndex(cType metadata.ComponentMetadata, field string, value any) (
+	[]entity.ID, error) {
+	key, err := metadata.IndexKey(value)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := r.manager.getCommittedEntitiesByIndex(cType, field, key)
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntityIDs(ids), nil
+}
@@ -0,0 +1,88 @@
+package ecb_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+type Player struct {
+	Team string
+	Tags []string
+}
+
+func (Player) Name() string {
+	return "player"
+}
+
+var playerComp = metadata.NewComponentMetadata[Player]()
+
+//nolint:gochecknoinits // its for testing.
+func init() {
+	_ = playerComp.SetID(4)         //notlint:errcheck
+	_ = playerComp.AddIndex("Team") //notlint:errcheck
+	_ = playerComp.AddIndex("Tags") //notlint:errcheck
+}
+
+func newIndexManagerForTest(t *testing.T, kv ecb.KVStore) *ecb.Manager {
+	manager, err := ecb.NewManagerWithKVStore(kv)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterComponents(append(allComponents, playerComp)))
+	return manager
+}
+
+func TestEntitiesCanBeFoundByIndexedFields(t *testing.T) {
+	kv := ecb.NewMemoryKVStore()
+	manager := newIndexManagerForTest(t, kv)
+	ids, err := manager.CreateManyEntities(4, playerComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(playerComp, ids[0], Player{Team: "red", Tags: []string{"a"}}))
+	assert.NilError(t, manager.SetComponentForEntity(playerComp, ids[1], Player{Team: "red", Tags: []string{"a", "b"}}))
+	assert.NilError(t, manager.SetComponentForEntity(playerComp, ids[2], Player{Team: "blue"}))
+	assert.NilError(t, manager.FinalizeTick())
+
+	// Pending changes are seen by the manager, but not by a read only manager.
+	assert.NilError(t, manager.SetComponentForEntity(playerComp, ids[0], Player{Team: "blue"}))
+	assert.NilError(t, manager.RemoveEntity(ids[1]))
+	created, err := manager.CreateEntity(playerComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(playerComp, created, Player{Team: "red"}))
+
+	pending := map[string][]entity.ID{
+		"red":  {created},
+		"blue": {ids[0], ids[2]},
+	}
+	committed := map[string][]entity.ID{
+		"red":  {ids[0], ids[1]},
+		"blue": {ids[2]},
+	}
+	check := func(reader store.Reader, want map[string][]entity.ID) {
+		for team, wantIDs := range want {
+			got, err := reader.GetEntitiesByIndex(playerComp, "Team", team)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, wantIDs, got)
+		}
+	}
+	check(manager, pending)
+	check(manager.ToReadOnly(), committed)
+	got, err := manager.ToReadOnly().GetEntitiesByIndex(playerComp, "Tags", "a")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []entity.ID{ids[0], ids[1]}, got)
+
+	_, err = manager.GetEntitiesByIndex(fooComp, "Value", 1)
+	assert.ErrorIs(t, err, storage.ErrFieldNotIndexed)
+
+	// Finalizing the tick updates the loaded indexes, and a new manager builds them from storage.
+	assert.NilError(t, manager.FinalizeTick())
+	check(manager.ToReadOnly(), pending)
+	check(newIndexManagerForTest(t, kv), pending)
+	got, err = manager.GetEntitiesByIndex(playerComp, "Tags", "a")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(got))
+}
@@ -52,3 +52,61 @@ func redisEndTickKey() string {
 func redisPendingTransactionKey() string {
 	return "ECB:PENDING-TRANSACTIONS"
 }
+
+// redisPendingTimestampKey is the key that stores the timestamp of the last started tick. It is saved alongside the
+// pending transactions so a recovered tick sees the same timestamp it originally had.
+func redisPendingTimestampKey() string {
+	return "ECB:PENDING-TIMESTAMP"
+}
+
+// redisNonceKey is the key that stores the last used nonce for the given signer address. It is only used by the
+// NonceStorage returned from NewKVNonceStorage.
+func redisNonceKey(signerAddress string) string {
+	return redisNoncePrefix + signerAddress
+}
+
+// redisNoncePrefix is the prefix shared by all keys returned from redisNonceKey.
+const redisNoncePrefix = "ECB:NONCE:ADDRESS-"
+
+// redisEventsKey is the key that stores the JSON encoded events that were emitted during the given tick. The tick is
+// zero padded so the keys sort in tick order.
+func redisEventsKey(tick uint64) string {
+	return redisTickKey(redisEventsPrefix, tick)
+}
+
+// redisEventsPrefix is the prefix shared by all keys returned from redisEventsKey.
+const redisEventsPrefix = "ECB:EVENTS:TICK-"
+
+// redisEventsOldestTickKey is the key that stores the oldest tick whose events have not been deleted.
+func redisEventsOldestTickKey() string {
+	return "ECB:EVENTS-OLDEST-TICK"
+}
+
+// redisStateHashKey is the key that stores the hash of the complete state at the end of the given tick. The tick is
+// zero padded so the keys sort in tick order.
+func redisStateHashKey(tick uint64) string {
+	return redisTickKey(redisStateHashPrefix, tick)
+}
+
+// redisStateHashPrefix is the prefix shared by all keys returned from redisStateHashKey.
+const redisStateHashPrefix = "ECB:STATE-HASH:TICK-"
+
+// redisStateHashOldestTickKey is the key that stores the oldest tick whose state hash has not been deleted.
+func redisStateHashOldestTickKey() string {
+	return "ECB:STATE-HASH-OLDEST-TICK"
+}
+
+// redisSubmissionKey is the key that stores the data the given tick has to submit to the base shard. The key is
+// deleted once the data was submitted. The tick is zero padded so the keys sort in tick order.
+func redisSubmissionKey(tick uint64) string {
+	return redisTickKey(redisSubmissionPrefix, tick)
+}
+
+// redisSubmissionPrefix is the prefix shared by all keys returned from redisSubmissionKey.
+const redisSubmissionPrefix = "ECB:SUBMISSION:TICK-"
+
+// redisTickKey is the key with the given prefix for the given tick. The tick is zero padded to 20 digits, so keys with
+// the same prefix sort in tick order.
+func redisTickKey(prefix string, tick uint64) string {
+	return fmt.Sprintf("%s%020d", prefix, tick)
+}
@@ -0,0 +1,94 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrKeyNotFound is returned by a KVStore when the requested key has never been set, or has been deleted.
+	ErrKeyNotFound = errors.New("key not found")
+)
+
+// KVStore is the key/value storage layer a Manager persists its state to. The keys used by the Manager are defined in
+// keys.go. Values are opaque byte slices, with the exception of keys that are modified via KVBatch.Incr; those values
+// must be base 10 encoded integers.
+type KVStore interface {
+	// Get returns the value saved at the given key. ErrKeyNotFound is returned if the key does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// MGet returns the values saved at the given keys in a single round trip. Values are in the same order as the keys,
+	// and the value of a key that does not exist is nil.
+	MGet(ctx context.Context, keys []string) ([][]byte, error)
+	// Keys returns all the saved keys that start with the given prefix in sorted order.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	// NewBatch returns an empty batch of write operations.
+	NewBatch() KVBatch
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// KVBatch is a group of write operations that are applied to a KVStore atomically. Either every operation in the
+// batch is applied, or none of them are. Operations are applied in the order they were added to the batch.
+type KVBatch interface {
+	Set(ctx context.Context, key string, value []byte) error
+	Del(ctx context.Context, key string) error
+	// Incr increments the integer saved at the given key. A missing key is treated as 0.
+	Incr(ctx context.Context, key string) error
+	// Exec atomically applies all operations in the batch.
+	Exec(ctx context.Context) error
+}
+
+// getUint64 fetches the base 10 encoded integer saved at the given key.
+func getUint64(ctx context.Context, kv KVStore, key string) (uint64, error) {
+	bz, err := kv.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(bz), 10, 64)
+}
+
+// getInt fetches the base 10 encoded integer saved at the given key.
+func getInt(ctx context.Context, kv KVStore, key string) (int, error) {
+	bz, err := kv.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(bz))
+}
+
+// encodeUint64 converts the given number to the format expected by getUint64 and KVBatch.Incr.
+func encodeUint64(n uint64) []byte {
+	return []byte(strconv.FormatUint(n, 10))
+}
+
+// encodeInt converts the given number to the format expected by getInt.
+func encodeInt(n int) []byte {
+	return []byte(strconv.Itoa(n))
+}
+
+// keysWithPrefix returns the sorted keys in the given map that start with the given prefix.
+func keysWithPrefix(data map[string][]byte, prefix string) []string {
+	var keys []string
+	for key := range data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// getMany returns copies of the values saved at the given keys in the given map, with nil for missing keys.
+func getMany(data map[string][]byte, keys []string) [][]byte {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		if bz, ok := data[key]; ok {
+			// An empty value must not be mistaken for a missing key.
+			values[i] = append([]byte{}, bz...)
+		}
+	}
+	return values
+}
@@ -0,0 +1,138 @@
+package ecb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+var _ KVStore = &memoryStore{}
+
+// memoryStore is a KVStore that keeps all data in process memory. It provides the same atomic batch semantics as the
+// redis backed store, but nothing survives a restart of the process. It is primarily useful for tests and local
+// development.
+type memoryStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryKVStore returns an empty KVStore that keeps all data in process memory.
+func NewMemoryKVStore() KVStore {
+	return &memoryStore{
+		data: map[string][]byte{},
+	}
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	bz, ok := s.data[key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	// Return a copy so callers can't modify the saved value.
+	return append([]byte(nil), bz...), nil
+}
+
+func (s *memoryStore) MGet(_ context.Context, keys []string) ([][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return getMany(s.data, keys), nil
+}
+
+func (s *memoryStore) Keys(_ context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return keysWithPrefix(s.data, prefix), nil
+}
+
+func (s *memoryStore) NewBatch() KVBatch {
+	return &memoryBatch{store: s}
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+type memoryOpKind int
+
+const (
+	memoryOpSet memoryOpKind = iota
+	memoryOpDel
+	memoryOpIncr
+)
+
+type memoryOp struct {
+	kind  memoryOpKind
+	key   string
+	value []byte
+}
+
+type memoryBatch struct {
+	store *memoryStore
+	ops   []memoryOp
+}
+
+func (b *memoryBatch) Set(_ context.Context, key string, value []byte) error {
+	b.ops = append(b.ops, memoryOp{kind: memoryOpSet, key: key, value: append([]byte(nil), value...)})
+	return nil
+}
+
+func (b *memoryBatch) Del(_ context.Context, key string) error {
+	b.ops = append(b.ops, memoryOp{kind: memoryOpDel, key: key})
+	return nil
+}
+
+func (b *memoryBatch) Incr(_ context.Context, key string) error {
+	b.ops = append(b.ops, memoryOp{kind: memoryOpIncr, key: key})
+	return nil
+}
+
+// Exec applies all operations to the underlying store. The operations are first applied to a scratch copy of the
+// modified keys so that an invalid operation (e.g. incrementing a non integer) leaves the store untouched.
+func (b *memoryBatch) Exec(_ context.Context) error {
+	b.store.mu.Lock()
+	defer b.store.mu.Unlock()
+
+	type pendingValue struct {
+		value   []byte
+		deleted bool
+	}
+	pending := map[string]pendingValue{}
+	lookup := func(key string) ([]byte, bool) {
+		if p, ok := pending[key]; ok {
+			return p.value, !p.deleted
+		}
+		bz, ok := b.store.data[key]
+		return bz, ok
+	}
+	for _, op := range b.ops {
+		switch op.kind {
+		case memoryOpSet:
+			pending[op.key] = pendingValue{value: op.value}
+		case memoryOpDel:
+			pending[op.key] = pendingValue{deleted: true}
+		case memoryOpIncr:
+			var n int64
+			if bz, ok := lookup(op.key); ok {
+				var err error
+				n, err = strconv.ParseInt(string(bz), 10, 64)
+				if err != nil {
+					return fmt.Errorf("value at key %q is not an integer: %w", op.key, err)
+				}
+			}
+			pending[op.key] = pendingValue{value: []byte(strconv.FormatInt(n+1, 10))}
+		}
+	}
+
+	for key, p := range pending {
+		if p.deleted {
+			delete(b.store.data, key)
+		} else {
+			b.store.data[key] = p.value
+		}
+	}
+	b.ops = nil
+	return nil
+}
@@ -0,0 +1,96 @@
+package ecb_test
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+	"pkg.world.dev/world-engine/cardinal/ecs/internal/testutil"
+	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
+)
+
+func newInMemoryManagerForTest(t *testing.T, kv ecb.KVStore) *ecb.Manager {
+	manager, err := ecb.NewManagerWithKVStore(kv)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterComponents(allComponents))
+	return manager
+}
+
+func TestInMemoryStoreStateSurvivesNewManager(t *testing.T) {
+	kv := ecb.NewMemoryKVStore()
+	manager := newInMemoryManagerForTest(t, kv)
+
+	ids, err := manager.CreateManyEntities(10, fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[3], Foo{Value: 33}))
+	assert.NilError(t, manager.StartNextTick(nil, transaction.NewTxQueue()))
+	assert.NilError(t, manager.FinalizeTick())
+
+	manager = newInMemoryManagerForTest(t, kv)
+	start, end, err := manager.GetTickNumbers()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), start)
+	assert.Equal(t, uint64(1), end)
+
+	gotFoo, err := manager.GetComponentForEntity(fooComp, ids[3])
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{Value: 33}, gotFoo)
+
+	nextID, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.Equal(t, ids[len(ids)-1]+1, nextID)
+}
+
+func TestInMemoryStoreDiscardsUnfinishedTick(t *testing.T) {
+	type TxIn struct {
+		Value int
+	}
+	tx := ecs.NewTransactionType[TxIn, TxIn]("in-memory-tx")
+	assert.NilError(t, tx.SetID(1))
+	txs := []transaction.ITransaction{tx}
+
+	kv := ecb.NewMemoryKVStore()
+	manager := newInMemoryManagerForTest(t, kv)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.CommitPending())
+
+	queue := transaction.NewTxQueue()
+	queue.AddTransaction(tx.ID(), TxIn{Value: 99}, testutil.UniqueSignature(t))
+	assert.NilError(t, manager.StartNextTick(txs, queue))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 100}))
+	// Simulate a crash before FinalizeTick is called.
+
+	manager = newInMemoryManagerForTest(t, kv)
+	start, end, err := manager.GetTickNumbers()
+	assert.NilError(t, err)
+	assert.Equal(t, start, end+1)
+
+	gotFoo, err := manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{}, gotFoo)
+
+	recovered, err := manager.Recover(txs)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, recovered.GetAmountOfTxs())
+}
+
+func TestInMemoryBatchIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	kv := ecb.NewMemoryKVStore()
+
+	batch := kv.NewBatch()
+	assert.NilError(t, batch.Set(ctx, "alpha", []byte("not-a-number")))
+	assert.NilError(t, batch.Exec(ctx))
+
+	batch = kv.NewBatch()
+	assert.NilError(t, batch.Set(ctx, "beta", []byte("some-value")))
+	assert.NilError(t, batch.Incr(ctx, "alpha"))
+	assert.Check(t, batch.Exec(ctx) != nil)
+
+	// The failed increment means the entire batch must be discarded.
+	_, err := kv.Get(ctx, "beta")
+	assert.ErrorIs(t, err, ecb.ErrKeyNotFound)
+}
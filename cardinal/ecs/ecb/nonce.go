@@ -0,0 +1,67 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+var _ storage.NonceStorage = &kvNonceStorage{}
+
+// kvNonceStorage saves nonces to a KVStore. This allows nonces to be persisted in the same store as the rest of the
+// world's state.
+type kvNonceStorage struct {
+	kv KVStore
+}
+
+// NewKVNonceStorage returns a NonceStorage that saves nonces to the given KVStore.
+func NewKVNonceStorage(kv KVStore) storage.NonceStorage {
+	return &kvNonceStorage{kv: kv}
+}
+
+// SavesNonces returns true if the given NonceStorage saves nonces to the same KVStore as the Manager. Only then can
+// RestoreState save the nonces of a store.State in the same batch as the rest of the state.
+func (m *Manager) SavesNonces(nonceStore storage.NonceStorage) bool {
+	k, ok := nonceStore.(*kvNonceStorage)
+	return ok && k.kv == m.kv
+}
+
+// GetNonce returns the saved nonce for the given signer address. Addresses that have never been saved have a nonce
+// of 0.
+func (k *kvNonceStorage) GetNonce(signerAddress string) (uint64, error) {
+	n, err := getUint64(context.Background(), k.kv, redisNonceKey(signerAddress))
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	}
+	return n, err
+}
+
+// SetNonce saves the given nonce value with the given signer address.
+func (k *kvNonceStorage) SetNonce(signerAddress string, nonce uint64) error {
+	ctx := context.Background()
+	batch := k.kv.NewBatch()
+	if err := batch.Set(ctx, redisNonceKey(signerAddress), encodeUint64(nonce)); err != nil {
+		return err
+	}
+	return batch.Exec(ctx)
+}
+
+// GetAllNonces returns every saved nonce keyed by signer address.
+func (k *kvNonceStorage) GetAllNonces() (map[string]uint64, error) {
+	ctx := context.Background()
+	keys, err := k.kv.Keys(ctx, redisNoncePrefix)
+	if err != nil {
+		return nil, err
+	}
+	nonces := make(map[string]uint64, len(keys))
+	for _, key := range keys {
+		n, err := getUint64(ctx, k.kv, key)
+		if err != nil {
+			return nil, err
+		}
+		nonces[strings.TrimPrefix(key, redisNoncePrefix)] = n
+	}
+	return nonces, nil
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/redis/go-redis/v9"
 	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
 	"pkg.world.dev/world-engine/cardinal/ecs/codec"
 	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
@@ -23,15 +22,20 @@ var (
 )
 
 type readOnlyManager struct {
-	client          *redis.Client
+	// manager is only used to look up committed values in the indexes.
+	manager         *Manager
+	kv              KVStore
 	typeToComponent map[metadata.TypeID]metadata.ComponentMetadata
 	archIDToComps   map[archetype.ID][]metadata.ComponentMetadata
+	archIndex       *archetypeIndex
 }
 
 func (m *Manager) ToReadOnly() store.Reader {
 	return &readOnlyManager{
-		client:          m.client,
+		manager:         m,
+		kv:              m.kv,
 		typeToComponent: m.typeToComponent,
+		archIndex:       newArchetypeIndex(nil),
 	}
 }
 
@@ -39,13 +43,14 @@ func (m *Manager) ToReadOnly() store.Reader {
 // only, i.e. if an archetype ID is in this map, it will ALWAYS refer to the same set of components. It's ok to save
 // this to memory instead of reading from redit each time. If an archetype ID is not found in this map.
 func (r *readOnlyManager) refreshArchIDToCompTypes() error {
-	archIDToComps, ok, err := getArchIDToCompTypesFromRedis(r.client, r.typeToComponent)
+	archIDToComps, ok, err := getArchIDToCompTypesFromKV(r.kv, r.typeToComponent)
 	if err != nil {
 		return err
 	} else if !ok {
 		return ErrNoArchIDMappingFound
 	}
 	r.archIDToComps = archIDToComps
+	r.archIndex = newArchetypeIndex(archIDToComps)
 	return nil
 }
 
@@ -62,7 +67,7 @@ func (r *readOnlyManager) GetComponentForEntityInRawJSON(cType metadata.Componen
 ) (json.RawMessage, error) {
 	ctx := context.Background()
 	key := redisComponentKey(cType.ID(), id)
-	return r.client.Get(ctx, key).Bytes()
+	return r.kv.Get(ctx, key)
 }
 
 func (r *readOnlyManager) getComponentsForArchID(archID archetype.ID) ([]metadata.ComponentMetadata, error) {
@@ -83,7 +88,7 @@ func (r *readOnlyManager) GetComponentTypesForEntity(id entity.ID) ([]metadata.C
 	ctx := context.Background()
 
 	archIDKey := redisArchetypeIDForEntityID(id)
-	num, err := r.client.Get(ctx, archIDKey).Int()
+	num, err := getInt(ctx, r.kv, archIDKey)
 	if err != nil {
 		return nil, err
 	}
@@ -116,10 +121,8 @@ func (r *readOnlyManager) GetArchIDForComponents(components []metadata.Component
 			}
 		}
 
-		for archID, currComps := range r.archIDToComps {
-			if isComponentSetMatch(currComps, components) {
-				return archID, nil
-			}
+		if archID, ok := r.archIndex.get(components); ok {
+			return archID, nil
 		}
 	}
 	return 0, errors.New("arch ID for components not found")
@@ -128,7 +131,7 @@ func (r *readOnlyManager) GetArchIDForComponents(components []metadata.Component
 func (r *readOnlyManager) GetEntitiesForArchID(archID archetype.ID) ([]entity.ID, error) {
 	ctx := context.Background()
 	key := redisActiveEntityIDKey(archID)
-	bz, err := r.client.Get(ctx, key).Bytes()
+	bz, err := r.kv.Get(ctx, key)
 	if err != nil {
 		// No entities were found for this archetype ID
 		return nil, err
@@ -3,202 +3,101 @@ package ecb
 import (
 	"context"
 	"errors"
-	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
-	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
-	"pkg.world.dev/world-engine/cardinal/ecs/codec"
-	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
-	"pkg.world.dev/world-engine/cardinal/ecs/storage"
 )
 
-// pipeFlushToRedis return a pipeliner with all pending state changes to redis ready to be committed in an atomic
-// transaction. If an error is returned, no redis changes will have been made.
-func (m *Manager) makePipeOfRedisCommands(ctx context.Context) (redis.Pipeliner, error) {
-	pipe := m.client.TxPipeline()
+var _ KVStore = &redisStore{}
 
-	if m.typeToComponent == nil {
-		// component.TypeID -> ComponentMetadata mappings are required to serialized data for the DB
-		return nil, errors.New("must call RegisterComponents before flushing to DB")
-	}
-
-	if err := m.addComponentChangesToPipe(ctx, pipe); err != nil {
-		return nil, fmt.Errorf("failed to add component changes to pipe: %w", err)
-	}
-	if err := m.addNextEntityIDToPipe(ctx, pipe); err != nil {
-		return nil, fmt.Errorf("failed to add entity id changes to pipe: %w", err)
-	}
-	if err := m.addPendingArchIDsToPipe(ctx, pipe); err != nil {
-		return nil, fmt.Errorf("failed to add archID to component type map to pipe: %w", err)
-	}
-	if err := m.addEntityIDToArchIDToPipe(ctx, pipe); err != nil {
-		return nil, fmt.Errorf("failed to add entity ID to archID mapping to pipe: %w", err)
-	}
-	if err := m.addActiveEntityIDsToPipe(ctx, pipe); err != nil {
-		return nil, fmt.Errorf("failed to add changes to active entity ids to pipe: %w", err)
-	}
+// redisGlobEscaper escapes the characters that have a special meaning in redis glob-style patterns.
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
 
-	return pipe, nil
+// redisStore is a KVStore backed by a redis DB. Batches are applied using a redis
+// [multi/exec pipeline](https://redis.io/docs/interact/transactions/).
+type redisStore struct {
+	client *redis.Client
 }
 
-// addEntityIDToArchIDToPipe adds the information related to mapping an entity ID to its assigned archetype ID.
-func (m *Manager) addEntityIDToArchIDToPipe(ctx context.Context, pipe redis.Pipeliner) error {
-	for id, originArchID := range m.entityIDToOriginArchID {
-		key := redisArchetypeIDForEntityID(id)
-		archID, ok := m.entityIDToArchID[id]
-		if !ok {
-			// this entity has been removed
-			if err := pipe.Del(ctx, key).Err(); err != nil {
-				return err
-			}
-			continue
-		}
-		// This entity somehow ended up back at its original archetype. There's nothing to do.
-		if archID == originArchID {
-			continue
-		}
-
-		// Otherwise, the archetype actually needs to be updated
-		archIDAsNum := int(archID)
-		if err := pipe.Set(ctx, key, archIDAsNum, 0).Err(); err != nil {
-			return err
-		}
-	}
-
-	return nil
+// NewRedisKVStore returns a KVStore that saves data to the given redis client.
+func NewRedisKVStore(client *redis.Client) KVStore {
+	return &redisStore{client: client}
 }
 
-// addNextEntityIDToPipe adds any changes to the next available entity ID to the given redis pipe.
-func (m *Manager) addNextEntityIDToPipe(ctx context.Context, pipe redis.Pipeliner) error {
-	// There are no pending entity id creations, so there's nothing to commit
-	if m.pendingEntityIDs == 0 {
-		return nil
+func (r *redisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	bz, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrKeyNotFound
 	}
-	key := redisNextEntityIDKey()
-	nextID := m.nextEntityIDSaved + m.pendingEntityIDs
-	return pipe.Set(ctx, key, nextID, 0).Err()
+	return bz, err
 }
 
-// addComponentChangesToPipe adds updated component values for entities to the redis pipe.
-func (m *Manager) addComponentChangesToPipe(ctx context.Context, pipe redis.Pipeliner) error {
-	for key, isMarkedForDeletion := range m.compValuesToDelete {
-		if !isMarkedForDeletion {
-			continue
-		}
-		redisKey := redisComponentKey(key.typeID, key.entityID)
-		if err := pipe.Del(ctx, redisKey).Err(); err != nil {
-			return err
-		}
+func (r *redisStore) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
 	}
-
-	for key, value := range m.compValues {
-		cType := m.typeToComponent[key.typeID]
-		bz, err := cType.Encode(value)
-		if err != nil {
-			return err
-		}
-
-		redisKey := redisComponentKey(key.typeID, key.entityID)
-		if err = pipe.Set(ctx, redisKey, bz, 0).Err(); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// preloadArchIDs loads the mapping of archetypes IDs to sets of IComponentTypes from storage.
-func (m *Manager) loadArchIDs() error {
-	archIDToComps, ok, err := getArchIDToCompTypesFromRedis(m.client, m.typeToComponent)
+	results, err := r.client.MGet(ctx, keys...).Result()
 	if err != nil {
-		return err
-	}
-	if !ok {
-		// Nothing is saved in the DB. Leave the m.archIDToComps field unchanged
-		return nil
+		return nil, err
 	}
-	if len(m.archIDToComps) > 0 {
-		return errors.New("assigned archetype ID is about to be overwritten by something from storage")
+	for i, result := range results {
+		// Missing keys are nil, and every other value is a string.
+		if str, ok := result.(string); ok {
+			values[i] = []byte(str)
+		}
 	}
-	m.archIDToComps = archIDToComps
-	return nil
+	return values, nil
 }
 
-// addPendingArchIDsToPipe adds any newly created archetype IDs (as well as the associated sets of components) to the
-// redis pipe.
-func (m *Manager) addPendingArchIDsToPipe(ctx context.Context, pipe redis.Pipeliner) error {
-	if len(m.pendingArchIDs) == 0 {
-		return nil
+// Keys uses SCAN to find all matching keys, so it is safe to use on a large DB, however keys that are added or removed
+// while the scan is in progress may or may not be returned.
+func (r *redisStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, redisGlobEscaper.Replace(prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
 	}
-
-	bz, err := m.encodeArchIDToCompTypes()
-	if err != nil {
-		return err
+	if err := iter.Err(); err != nil {
+		return nil, err
 	}
+	// SCAN may return the same key more than once.
+	slices.Sort(keys)
+	return slices.Compact(keys), nil
+}
 
-	return pipe.Set(ctx, redisArchIDsToCompTypesKey(), bz, 0).Err()
+func (r *redisStore) NewBatch() KVBatch {
+	return &redisBatch{pipe: r.client.TxPipeline()}
 }
 
-// addActiveEntityIDsToPipe adds information about which entities are assigned to which archetype IDs to the reids pipe.
-func (m *Manager) addActiveEntityIDsToPipe(ctx context.Context, pipe redis.Pipeliner) error {
-	for archID, active := range m.activeEntities {
-		if !active.modified {
-			continue
-		}
-		bz, err := codec.Encode(active.ids)
-		if err != nil {
-			return err
-		}
-		key := redisActiveEntityIDKey(archID)
-		err = pipe.Set(ctx, key, bz, 0).Err()
-		if err != nil {
-			return err
-		}
+func (r *redisStore) Close() error {
+	err := r.client.Close()
+	if errors.Is(err, redis.ErrClosed) {
+		// if redis is already closed that means another shutdown pathway got to it first.
+		// There are multiple modules that will try to shutdown redis, if it is already shutdown it is not an error.
+		return nil
 	}
-	return nil
+	return err
 }
 
-func (m *Manager) encodeArchIDToCompTypes() ([]byte, error) {
-	forStorage := map[archetype.ID][]metadata.TypeID{}
-	for archID, comps := range m.archIDToComps {
-		typeIDs := []metadata.TypeID{}
-		for _, comp := range comps {
-			typeIDs = append(typeIDs, comp.ID())
-		}
-		forStorage[archID] = typeIDs
-	}
-	return codec.Encode(forStorage)
+type redisBatch struct {
+	pipe redis.Pipeliner
 }
 
-func getArchIDToCompTypesFromRedis(client *redis.Client,
-	typeToComp map[metadata.TypeID]metadata.ComponentMetadata,
-) (m map[archetype.ID][]metadata.ComponentMetadata, ok bool, err error) {
-	ctx := context.Background()
-	key := redisArchIDsToCompTypesKey()
-	bz, err := client.Get(ctx, key).Bytes()
-	if errors.Is(err, redis.Nil) {
-		return nil, false, nil
-	} else if err != nil {
-		return nil, false, err
-	}
+func (b *redisBatch) Set(ctx context.Context, key string, value []byte) error {
+	return b.pipe.Set(ctx, key, value, 0).Err()
+}
 
-	fromStorage, err := codec.Decode[map[archetype.ID][]metadata.TypeID](bz)
-	if err != nil {
-		return nil, false, err
-	}
+func (b *redisBatch) Del(ctx context.Context, key string) error {
+	return b.pipe.Del(ctx, key).Err()
+}
 
-	// result is the mapping of Arch ID -> IComponent sets
-	result := map[archetype.ID][]metadata.ComponentMetadata{}
-	for archID, compTypeIDs := range fromStorage {
-		currComps := []metadata.ComponentMetadata{}
-		for _, compTypeID := range compTypeIDs {
-			currComp, found := typeToComp[compTypeID]
-			if !found {
-				return nil, false, storage.ErrComponentMismatchWithSavedState
-			}
-			currComps = append(currComps, currComp)
-		}
+func (b *redisBatch) Incr(ctx context.Context, key string) error {
+	return b.pipe.Incr(ctx, key).Err()
+}
 
-		result[archID] = currComps
-	}
-	return result, true, nil
+func (b *redisBatch) Exec(ctx context.Context) error {
+	_, err := b.pipe.Exec(ctx)
+	return err
 }
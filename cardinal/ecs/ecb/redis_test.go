@@ -62,3 +62,49 @@ func TestComponentValuesAreDeletedFromRedis(t *testing.T) {
 	err = client.Get(ctx, key).Err()
 	assert.ErrorIs(t, err, redis.Nil)
 }
+
+func TestRedisKeysMatchesLiteralPrefix(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	kv := NewRedisKVStore(redis.NewClient(&redis.Options{Addr: s.Addr()}))
+
+	batch := kv.NewBatch()
+	for _, key := range []string{"PREFIX*-b", "PREFIX*-a", "PREFIX-c", "OTHER"} {
+		assert.NilError(t, batch.Set(ctx, key, []byte("1")))
+	}
+	assert.NilError(t, batch.Exec(ctx))
+
+	keys, err := kv.Keys(ctx, "PREFIX*")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"PREFIX*-a", "PREFIX*-b"}, keys)
+}
+
+func TestMGetReturnsNilForMissingKeys(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	disk, err := NewDiskKVStore(t.TempDir())
+	assert.NilError(t, err)
+	stores := map[string]KVStore{
+		"redis":  NewRedisKVStore(redis.NewClient(&redis.Options{Addr: s.Addr()})),
+		"memory": NewMemoryKVStore(),
+		"disk":   disk,
+	}
+	for name, kv := range stores {
+		batch := kv.NewBatch()
+		assert.NilError(t, batch.Set(ctx, "a", []byte("1")))
+		assert.NilError(t, batch.Set(ctx, "empty", []byte{}))
+		assert.NilError(t, batch.Exec(ctx))
+
+		values, err := kv.MGet(ctx, []string{"missing", "a", "empty"})
+		assert.NilError(t, err, name)
+		assert.Equal(t, 3, len(values), name)
+		assert.Check(t, values[0] == nil, name)
+		assert.Equal(t, "1", string(values[1]), name)
+		assert.Check(t, values[2] != nil && len(values[2]) == 0, name)
+
+		values, err = kv.MGet(ctx, nil)
+		assert.NilError(t, err, name)
+		assert.Equal(t, 0, len(values), name)
+		assert.NilError(t, kv.Close())
+	}
+}
@@ -0,0 +1,226 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/filter"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+// relationKey is the target of a relation component. The relation index maps each relationKey to the set of entities
+// that refer to the target through the relation.
+type relationKey struct {
+	typeID metadata.TypeID
+	target entity.ID
+}
+
+// GetRelationSources returns the entities that refer to the target entity through the given relation component,
+// including any pending changes. The entities are sorted by ID.
+func (m *Manager) GetRelationSources(cType metadata.ComponentMetadata, target entity.ID) ([]entity.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.relationTypes[cType.ID()] {
+		return nil, fmt.Errorf("%w: %s", storage.ErrComponentIsNotARelation, cType.Name())
+	}
+	if err := m.loadRelationIndex(); err != nil {
+		return nil, err
+	}
+	return sortedEntityIDs(m.relationSources[relationKey{cType.ID(), target}]), nil
+}
+
+// loadRelationIndex builds the relation index from the current state, including any pending changes. Once the index
+// is loaded, it is kept up to date by every change to a relation component until pending changes are discarded.
+func (m *Manager) loadRelationIndex() error {
+	if m.isRelationIndexLoaded {
+		return nil
+	}
+	m.relationSources = map[relationKey]map[entity.ID]bool{}
+	for i := 0; i < m.archetypeCount(); i++ {
+		archID := archetype.ID(i)
+		var relations []metadata.ComponentMetadata
+		for _, comp := range m.getComponentTypesForArchID(archID) {
+			if m.relationTypes[comp.ID()] {
+				relations = append(relations, comp)
+			}
+		}
+		if len(relations) == 0 {
+			continue
+		}
+		ids, err := m.getEntitiesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			for _, comp := range relations {
+				target, ok, err := m.getRelationTarget(comp, id)
+				if err != nil {
+					return err
+				}
+				if ok {
+					m.addRelationSource(relationKey{comp.ID(), target}, id)
+				}
+			}
+		}
+	}
+	m.isRelationIndexLoaded = true
+	return nil
+}
+
+// getRelationTarget returns the entity that the given entity currently refers to through the given relation. false is
+// returned if the relation does not refer to any entity, e.g. because it still has its default value. Values that are
+// only in the KVStore are not decoded and cached.
+func (m *Manager) getRelationTarget(cType metadata.ComponentMetadata, id entity.ID) (entity.ID, bool, error) {
+	value, err := m.getUncachedComponent(context.Background(), cType, id)
+	if err != nil {
+		return 0, false, err
+	}
+	target, hasTarget, isRelation := metadata.RelationTarget(value)
+	if !isRelation {
+		return 0, false, fmt.Errorf("%w: %s", storage.ErrComponentIsNotARelation, cType.Name())
+	}
+	return target, hasTarget, nil
+}
+
+// checkRelationTarget makes sure the entity a relation is about to be set to exists.
+func (m *Manager) checkRelationTarget(cType metadata.ComponentMetadata, value any) error {
+	if !m.relationTypes[cType.ID()] {
+		return nil
+	}
+	target, hasTarget, isRelation := metadata.RelationTarget(value)
+	if !isRelation {
+		return fmt.Errorf("%w: %s", storage.ErrComponentIsNotARelation, cType.Name())
+	}
+	if !hasTarget {
+		return nil
+	}
+	exists, err := m.entityExists(target)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s cannot refer to entity %d", storage.ErrRelationTargetNotFound, cType.Name(), target)
+	}
+	return nil
+}
+
+// entityExists returns true if the given entity exists, including any pending changes.
+func (m *Manager) entityExists(id entity.ID) (bool, error) {
+	if _, ok := m.entityIDToArchID[id]; ok {
+		return true, nil
+	}
+	if _, ok := m.entityIDToOriginArchID[id]; ok {
+		// The entity has been removed.
+		return false, nil
+	}
+	_, err := m.getArchetypeForEntity(id)
+	if errors.Is(err, ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// relationsAdded adds the current relations of the given entity to the relation index, if the index is loaded.
+func (m *Manager) relationsAdded(comps []metadata.ComponentMetadata, id entity.ID) error {
+	if !m.isRelationIndexLoaded {
+		return nil
+	}
+	for _, comp := range comps {
+		if !m.relationTypes[comp.ID()] {
+			continue
+		}
+		target, ok, err := m.getRelationTarget(comp, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			m.addRelationSource(relationKey{comp.ID(), target}, id)
+		}
+	}
+	return nil
+}
+
+// relationsRemoved removes the current relations of the given entity from the relation index, if the index is loaded.
+// This must be called before the component values are removed.
+func (m *Manager) relationsRemoved(comps []metadata.ComponentMetadata, id entity.ID) error {
+	if !m.isRelationIndexLoaded {
+		return nil
+	}
+	for _, comp := range comps {
+		if !m.relationTypes[comp.ID()] {
+			continue
+		}
+		target, ok, err := m.getRelationTarget(comp, id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		key := relationKey{comp.ID(), target}
+		delete(m.relationSources[key], id)
+		if len(m.relationSources[key]) == 0 {
+			delete(m.relationSources, key)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) addRelationSource(key relationKey, id entity.ID) {
+	sources, ok := m.relationSources[key]
+	if !ok {
+		sources = map[entity.ID]bool{}
+		m.relationSources[key] = sources
+	}
+	sources[id] = true
+}
+
+// GetRelationSources returns the entities that refer to the target entity through the given relation component. Only
+// committed state is read, so every entity that has the relation is checked.
+func (r *readOnlyManager) GetRelationSources(cType metadata.ComponentMetadata, target entity.ID) (
+	[]entity.ID, error) {
+	if !metadata.IsRelation(cType) {
+		return nil, fmt.Errorf("%w: %s", storage.ErrComponentIsNotARelation, cType.Name())
+	}
+	if err := r.refreshArchIDToCompTypes(); err != nil {
+		return nil, err
+	}
+	sources := []entity.ID{}
+	for archID, comps := range r.archIDToComps {
+		if !filter.MatchComponentMetaData(comps, cType) {
+			continue
+		}
+		ids, err := r.GetEntitiesForArchID(archID)
+		if errors.Is(err, ErrKeyNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			bz, err := r.GetComponentForEntityInRawJSON(cType, id)
+			if errors.Is(err, ErrKeyNotFound) {
+				// This value has never been set, so it has the default value.
+				bz, err = cType.New()
+			}
+			if err != nil {
+				return nil, err
+			}
+			value, err := cType.Decode(bz)
+			if err != nil {
+				return nil, err
+			}
+			if valueTarget, ok, _ := metadata.RelationTarget(value); ok && valueTarget == target {
+				sources = append(sources, id)
+			}
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i] < sources[j]
+	})
+	return sources, nil
+}
@@ -0,0 +1,124 @@
+package ecb_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+type Owner struct {
+	ID *entity.ID
+}
+
+func (Owner) Name() string {
+	return "owner"
+}
+
+func (o Owner) Target() (entity.ID, bool) {
+	if o.ID == nil {
+		return 0, false
+	}
+	return *o.ID, true
+}
+
+func ownedBy(id entity.ID) Owner {
+	return Owner{ID: &id}
+}
+
+var ownerComp = metadata.NewComponentMetadata[Owner]()
+
+//nolint:gochecknoinits // its for testing.
+func init() {
+	_ = ownerComp.SetID(3) //notlint:errcheck
+}
+
+func newRelationManagerForTest(t *testing.T, kv ecb.KVStore) *ecb.Manager {
+	manager, err := ecb.NewManagerWithKVStore(kv)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterComponents(append(allComponents, ownerComp)))
+	return manager
+}
+
+func TestRelationSourcesAreKeptUpToDate(t *testing.T) {
+	kv := ecb.NewMemoryKVStore()
+	manager := newRelationManagerForTest(t, kv)
+	owners, err := manager.CreateManyEntities(3, fooComp)
+	assert.NilError(t, err)
+	items, err := manager.CreateManyEntities(3, fooComp, ownerComp)
+	assert.NilError(t, err)
+	for _, item := range items {
+		assert.NilError(t, manager.SetComponentForEntity(ownerComp, item, ownedBy(owners[0])))
+	}
+	sources, err := manager.GetRelationSources(ownerComp, owners[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, sources)
+
+	// Change the relations after the index is loaded.
+	owner := ownedBy(owners[1])
+	assert.NilError(t, manager.SetComponentForEntity(ownerComp, items[0], &owner))
+	assert.NilError(t, manager.RemoveComponentFromEntity(ownerComp, items[1]))
+	assert.NilError(t, manager.AddComponentToEntity(ownerComp, owners[2]))
+	assert.NilError(t, manager.SetComponentForEntity(ownerComp, owners[2], ownedBy(owners[1])))
+	assert.NilError(t, manager.RemoveEntity(items[2]))
+
+	// Relations can only refer to entities that exist.
+	err = manager.SetComponentForEntity(ownerComp, items[0], ownedBy(100))
+	assert.ErrorIs(t, err, storage.ErrRelationTargetNotFound)
+	assert.NilError(t, manager.RemoveEntity(owners[0]))
+	err = manager.SetComponentForEntity(ownerComp, items[0], ownedBy(owners[0]))
+	assert.ErrorIs(t, err, storage.ErrRelationTargetNotFound)
+
+	want := map[entity.ID][]entity.ID{
+		owners[0]: {},
+		owners[1]: {owners[2], items[0]},
+	}
+	for target, wantSources := range want {
+		sources, err = manager.GetRelationSources(ownerComp, target)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, wantSources, sources)
+	}
+	assert.NilError(t, manager.FinalizeTick())
+
+	// A new manager builds the index from storage, and a read only manager searches storage.
+	loaded := newRelationManagerForTest(t, kv)
+	for _, reader := range []store.Reader{loaded, manager.ToReadOnly()} {
+		for target, wantSources := range want {
+			sources, err = reader.GetRelationSources(ownerComp, target)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, wantSources, sources)
+		}
+		_, err = reader.GetRelationSources(fooComp, owners[1])
+		assert.ErrorIs(t, err, storage.ErrComponentIsNotARelation)
+	}
+}
+
+func TestDefaultRelationsDoNotReferToAnyEntity(t *testing.T) {
+	kv := ecb.NewMemoryKVStore()
+	manager := newRelationManagerForTest(t, kv)
+	first, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.Equal(t, entity.ID(0), first)
+	_, err = manager.GetRelationSources(ownerComp, first)
+	assert.NilError(t, err)
+
+	// Neither new entities nor added components refer to entity 0 until their relation is set.
+	_, err = manager.CreateEntity(fooComp, ownerComp)
+	assert.NilError(t, err)
+	other, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.AddComponentToEntity(ownerComp, other))
+	assert.NilError(t, manager.FinalizeTick())
+
+	loaded := newRelationManagerForTest(t, kv)
+	for _, reader := range []store.Reader{manager, loaded, manager.ToReadOnly()} {
+		sources, err := reader.GetRelationSources(ownerComp, first)
+		assert.NilError(t, err)
+		assert.Equal(t, 0, len(sources))
+	}
+}
@@ -0,0 +1,55 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// getOldestTick returns the oldest tick whose key with the given prefix (see redisTickKey) has not been deleted, and
+// whether it was read from oldestKey. If oldestKey has not been saved yet, the oldest tick is found by listing the keys
+// with the prefix, and ifNone is returned if there are no such keys.
+func (m *Manager) getOldestTick(ctx context.Context, prefix, oldestKey string, ifNone uint64) (
+	oldest uint64, saved bool, err error) {
+	oldest, err = getUint64(ctx, m.kv, oldestKey)
+	if err == nil {
+		return oldest, true, nil
+	} else if !errors.Is(err, ErrKeyNotFound) {
+		return 0, false, err
+	}
+	keys, err := m.kv.Keys(ctx, prefix)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(keys) == 0 {
+		return ifNone, false, nil
+	}
+	// Tick keys zero pad the tick number, so they are sorted by tick.
+	oldest, err = strconv.ParseUint(strings.TrimPrefix(keys[0], prefix), 10, 64)
+	return oldest, false, err
+}
+
+// addPrunedTicksToBatch deletes the key with the given prefix of every tick that has fallen out of the retention window
+// that ends at the given tick. A retention of 0 keeps every tick. The oldest remaining tick is saved under oldestKey,
+// so the keys of ticks that fell out of the window before the retention was lowered are deleted as well.
+func (m *Manager) addPrunedTicksToBatch(ctx context.Context, batch KVBatch, prefix, oldestKey string,
+	tick, retention uint64) error {
+	oldest, saved, err := m.getOldestTick(ctx, prefix, oldestKey, tick)
+	if err != nil {
+		return err
+	}
+	keep := oldest
+	if retention > 0 && tick >= retention {
+		keep = max(oldest, tick-retention+1)
+	}
+	for pruned := oldest; pruned < keep; pruned++ {
+		if err = batch.Del(ctx, redisTickKey(prefix, pruned)); err != nil {
+			return err
+		}
+	}
+	if saved && keep == oldest {
+		return nil
+	}
+	return batch.Set(ctx, oldestKey, encodeUint64(keep))
+}
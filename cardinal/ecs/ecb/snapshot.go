@@ -0,0 +1,136 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/codec"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+var _ store.SnapshotStorage = &Manager{}
+
+var (
+	ErrRestoreRequiresEmptyStore = errors.New("state can only be restored to an empty store")
+)
+
+// NextEntityID returns the ID that will be assigned to the next created entity. Pending entity creations are taken
+// into account.
+func (m *Manager) NextEntityID() (entity.ID, error) {
+	id, err := m.nextEntityID()
+	if err != nil {
+		return 0, err
+	}
+	// nextEntityID reserves the returned ID. Give it back.
+	m.pendingEntityIDs--
+	return id, nil
+}
+
+// RestoreState saves the given state to the DB in a single atomic batch. The tick numbers will be set to the state's
+// tick, so GetTickNumbers will report that the state's tick has been successfully completed. RegisterComponents must
+// be called before RestoreState, and the store must not contain any entities or archetypes.
+func (m *Manager) RestoreState(state store.State) error {
+	if m.typeToComponent == nil {
+		return errors.New("must call RegisterComponents before restoring state")
+	}
+	if len(m.archIDToComps) > 0 || len(m.entityIDToOriginArchID) > 0 {
+		return ErrRestoreRequiresEmptyStore
+	}
+	if id, err := m.NextEntityID(); err != nil {
+		return err
+	} else if id != 0 {
+		return ErrRestoreRequiresEmptyStore
+	}
+
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	ctx := context.Background()
+	batch := m.kv.NewBatch()
+	archIDToComps := map[archetype.ID][]metadata.ComponentMetadata{}
+	seen := map[entity.ID]bool{}
+	for i, arch := range state.Archetypes {
+		archID := archetype.ID(i)
+		comps := append([]metadata.ComponentMetadata(nil), arch.Components...)
+		for _, comp := range comps {
+			if _, ok := m.typeToComponent[comp.ID()]; !ok {
+				return fmt.Errorf("component %q of archetype %d has not been registered", comp.Name(), archID)
+			}
+		}
+		if err := sortComponentSet(comps); err != nil {
+			return err
+		}
+		archIDToComps[archID] = comps
+
+		ids := make([]entity.ID, 0, len(arch.Entities))
+		for _, ent := range arch.Entities {
+			if seen[ent.ID] {
+				return fmt.Errorf("entity %d belongs to more than one archetype", ent.ID)
+			}
+			if ent.ID >= state.NextEntityID {
+				return fmt.Errorf("entity %d is not less than the next entity ID %d", ent.ID, state.NextEntityID)
+			}
+			if len(ent.Values) != len(arch.Components) {
+				return fmt.Errorf("entity %d has %d component values, but archetype %d has %d components",
+					ent.ID, len(ent.Values), archID, len(arch.Components))
+			}
+			seen[ent.ID] = true
+			ids = append(ids, ent.ID)
+			if err := batch.Set(ctx, redisArchetypeIDForEntityID(ent.ID), encodeInt(int(archID))); err != nil {
+				return err
+			}
+			for j, comp := range arch.Components {
+				if err := batch.Set(ctx, redisComponentKey(comp.ID(), ent.ID), ent.Values[j]); err != nil {
+					return err
+				}
+			}
+		}
+		bz, err := codec.Encode(ids)
+		if err != nil {
+			return err
+		}
+		if err = batch.Set(ctx, redisActiveEntityIDKey(archID), bz); err != nil {
+			return err
+		}
+	}
+
+	if len(archIDToComps) > 0 {
+		bz, err := encodeArchIDToCompTypes(archIDToComps)
+		if err != nil {
+			return err
+		}
+		if err = batch.Set(ctx, redisArchIDsToCompTypesKey(), bz); err != nil {
+			return err
+		}
+	}
+	if err := batch.Set(ctx, redisNextEntityIDKey(), encodeUint64(uint64(state.NextEntityID))); err != nil {
+		return err
+	}
+	if err := batch.Set(ctx, redisStartTickKey(), encodeUint64(state.Tick)); err != nil {
+		return err
+	}
+	if err := batch.Set(ctx, redisEndTickKey(), encodeUint64(state.Tick)); err != nil {
+		return err
+	}
+	for signerAddress, nonce := range state.Nonces {
+		if err := batch.Set(ctx, redisNonceKey(signerAddress), encodeUint64(nonce)); err != nil {
+			return err
+		}
+	}
+	if err := batch.Exec(ctx); err != nil {
+		return err
+	}
+
+	// Drop anything that was cached before the restore so all future reads come from the restored state.
+	m.DiscardPending()
+	clear(m.compValuesToDelete)
+	clear(m.entityIDToArchID)
+	m.setArchIDToComps(archIDToComps)
+	m.isStateTreeLoaded = false
+	clear(m.stateTrees)
+	m.isIndexesLoaded = false
+	return nil
+}
@@ -0,0 +1,241 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/merkle"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+var _ store.StateHashStorage = &Manager{}
+
+// DefaultStateHashRetention is the number of ticks that state hashes are kept for, unless SetStateHashRetention is
+// called.
+const DefaultStateHashRetention = 1000
+
+// DefaultProofRetention is the number of finalized ticks that component proofs can be built for, unless
+// SetProofRetention is called.
+const DefaultProofRetention = 100
+
+var ErrNoStateTree = errors.New("the state tree of the tick is not kept in memory")
+
+// SetProofRetention sets the number of finalized ticks that component proofs can be built for. The state trees of
+// consecutive ticks share every node that did not change, so each kept tick only costs memory for the paths to the
+// values that changed during the tick. A retention of 0 keeps the trees of every tick since the state was loaded.
+func (m *Manager) SetProofRetention(ticks uint64) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	m.proofRetention = ticks
+}
+
+// SetStateHashRetention sets the number of ticks that state hashes are kept for. A retention of 0 keeps the state hash
+// of every tick.
+func (m *Manager) SetStateHashRetention(ticks uint64) {
+	m.stateHashRetention = ticks
+}
+
+// GetStateHash returns the state hash that was saved at the end of the given tick. nil is returned if no state hash
+// was saved for the tick, or if it is older than the state hash retention (see SetStateHashRetention). Only the
+// KVStore is read, so this is safe to call while a tick is running.
+func (m *Manager) GetStateHash(tick uint64) ([]byte, error) {
+	hash, err := m.kv.Get(context.Background(), redisStateHashKey(tick))
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	}
+	return hash, err
+}
+
+// GetPendingStateHash returns the state hash of the current state, including every pending change. At the end of a
+// tick, this is the same hash that FinalizeTick saves. Once a tick has been finalized, only the changes that were made
+// since then are hashed, and nothing is saved.
+func (m *Manager) GetPendingStateHash() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tree, err := m.nextStateTree(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return tree.Root(), nil
+}
+
+// GetComponentProof returns the value the given component had on the given entity at the end of the given tick, along
+// with a proof that the value is part of the tick's state hash. ErrNoStateTree is returned if the tick was not
+// finalized since the state was loaded, or if it is older than the proof retention (see SetProofRetention), and
+// storage.ErrComponentNotOnEntity is returned if the entity did not have the component. The state trees are never
+// changed, so the proof is built without holding any lock.
+func (m *Manager) GetComponentProof(cType metadata.ComponentMetadata, id entity.ID, tick uint64) (
+	*store.ComponentProof, error) {
+	m.stateMu.RLock()
+	tree, ok := m.stateTrees[tick]
+	m.stateMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: tick %d", ErrNoStateTree, tick)
+	}
+	key := compKey{cType.ID(), id}
+	value, ok := tree.Get(uint64(key.typeID), uint64(key.entityID))
+	if !ok {
+		return nil, storage.ErrComponentNotOnEntity
+	}
+	proof, _ := tree.Proof(uint64(key.typeID), uint64(key.entityID))
+	return &store.ComponentProof{
+		Tick:            tick,
+		StateHash:       tree.Root(),
+		ComponentTypeID: key.typeID,
+		EntityID:        key.entityID,
+		Value:           value,
+		Proof:           proof,
+	}, nil
+}
+
+// saveStateTree makes the given tree the state tree of the given finalized tick, and drops the trees that are older
+// than the proof retention.
+func (m *Manager) saveStateTree(tick uint64, tree merkle.Tree) {
+	m.stateTree = tree
+	m.isStateTreeLoaded = true
+	m.stateTrees[tick] = tree
+	if m.proofRetention == 0 || tick < m.proofRetention {
+		return
+	}
+	for kept := range m.stateTrees {
+		if kept <= tick-m.proofRetention {
+			delete(m.stateTrees, kept)
+		}
+	}
+}
+
+// addStateHashToBatch brings the state tree up to date with the changes that were made during the tick, and saves
+// its root under the current tick. The tree is built from scratch the first time this is called; after that only the
+// leaves of changed components are updated, which costs O(log n) hashes per change. The state hashes of ticks that have
+// fallen out of the retention window are deleted. The tick and the updated tree are returned, and must only be saved
+// with saveStateTree once the batch has been applied.
+func (m *Manager) addStateHashToBatch(ctx context.Context, batch KVBatch) (uint64, merkle.Tree, error) {
+	tick, err := getUint64(ctx, m.kv, redisEndTickKey())
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return 0, merkle.Tree{}, err
+	}
+	tree, err := m.nextStateTree(ctx)
+	if err != nil {
+		return 0, merkle.Tree{}, err
+	}
+	if err = batch.Set(ctx, redisStateHashKey(tick), tree.Root()); err != nil {
+		return 0, merkle.Tree{}, err
+	}
+	err = m.addPrunedTicksToBatch(ctx, batch, redisStateHashPrefix, redisStateHashOldestTickKey(), tick,
+		m.stateHashRetention)
+	return tick, tree, err
+}
+
+// nextStateTree returns the state tree with every pending change.
+func (m *Manager) nextStateTree(ctx context.Context) (merkle.Tree, error) {
+	if m.isStateTreeLoaded {
+		return m.updateStateTree(ctx, m.stateTree)
+	}
+	return m.loadStateTree(ctx)
+}
+
+// loadStateTree builds the tree of every component value of every entity, including any pending changes. The values
+// of each archetype that are not in memory are fetched with a single MGet.
+func (m *Manager) loadStateTree(ctx context.Context) (merkle.Tree, error) {
+	var leaves []merkle.Leaf
+	for i := 0; i < m.archetypeCount(); i++ {
+		archID := archetype.ID(i)
+		comps := m.getComponentTypesForArchID(archID)
+		ids, err := m.getEntitiesForArchID(archID)
+		if err != nil {
+			return merkle.Tree{}, err
+		}
+		var missing []int
+		var missingComps []metadata.ComponentMetadata
+		var missingIDs []entity.ID
+		for _, id := range ids {
+			for _, comp := range comps {
+				leaf := merkle.Leaf{ComponentTypeID: uint64(comp.ID()), EntityID: uint64(id)}
+				if value, ok := m.compValues[compKey{comp.ID(), id}]; ok {
+					if leaf.Value, err = comp.Encode(value); err != nil {
+						return merkle.Tree{}, err
+					}
+				} else {
+					missing = append(missing, len(leaves))
+					missingComps = append(missingComps, comp)
+					missingIDs = append(missingIDs, id)
+				}
+				leaves = append(leaves, leaf)
+			}
+		}
+		bzs, err := getCommittedEncodedComponents(ctx, m.kv, missingComps, missingIDs)
+		if err != nil {
+			return merkle.Tree{}, err
+		}
+		for j, leaf := range missing {
+			leaves[leaf].Value = bzs[j]
+		}
+	}
+	return merkle.NewTree(leaves), nil
+}
+
+// updateStateTree applies the changes that were made since the last finalized tick to the given tree.
+func (m *Manager) updateStateTree(ctx context.Context, tree merkle.Tree) (merkle.Tree, error) {
+	for id := range m.diff.removedEntities {
+		// The entity may have had components added or removed before it was removed, so every component type is
+		// checked.
+		for typeID := range m.typeToComponent {
+			tree = tree.Delete(uint64(typeID), uint64(id))
+		}
+	}
+	for key := range m.diff.removedComps {
+		tree = tree.Delete(uint64(key.typeID), uint64(key.entityID))
+	}
+	var err error
+	for id := range m.diff.createdEntities {
+		comps, err := m.getComponentTypesForEntity(id)
+		if err != nil {
+			return merkle.Tree{}, err
+		}
+		for _, comp := range comps {
+			if tree, err = m.setStateLeaf(ctx, tree, comp, id); err != nil {
+				return merkle.Tree{}, err
+			}
+		}
+	}
+	for key := range m.diff.setComps {
+		// Removed entities, including entities that were created and removed during the tick, are dropped from
+		// entityIDToArchID.
+		if _, ok := m.entityIDToArchID[key.entityID]; !ok || m.diff.createdEntities[key.entityID] {
+			continue
+		}
+		if tree, err = m.setStateLeaf(ctx, tree, m.typeToComponent[key.typeID], key.entityID); err != nil {
+			return merkle.Tree{}, err
+		}
+	}
+	return tree, nil
+}
+
+func (m *Manager) setStateLeaf(ctx context.Context, tree merkle.Tree, comp metadata.ComponentMetadata, id entity.ID) (
+	merkle.Tree, error) {
+	bz, err := m.getEncodedComponent(ctx, comp, id)
+	if err != nil {
+		return merkle.Tree{}, err
+	}
+	return tree.Set(uint64(comp.ID()), uint64(id), bz), nil
+}
+
+// getEncodedComponent returns the encoded value of the given component. Unlike getComponentForEntityInRawJSON, values
+// that are only in the KVStore are not decoded and cached.
+func (m *Manager) getEncodedComponent(ctx context.Context, comp metadata.ComponentMetadata, id entity.ID) (
+	[]byte, error) {
+	if value, ok := m.compValues[compKey{comp.ID(), id}]; ok {
+		return comp.Encode(value)
+	}
+	bz, err := m.kv.Get(ctx, redisComponentKey(comp.ID(), id))
+	if errors.Is(err, ErrKeyNotFound) {
+		// This value has never been set, so it has the default value.
+		return comp.New()
+	}
+	return bz, err
+}
@@ -0,0 +1,184 @@
+package ecb_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+	"pkg.world.dev/world-engine/cardinal/ecs/merkle"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+func TestStateHashIsSavedForEveryTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 5}))
+	assert.NilError(t, manager.FinalizeTick())
+	// A tick without changes.
+	assert.NilError(t, manager.FinalizeTick())
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 6}))
+	assert.NilError(t, manager.FinalizeTick())
+
+	hash0, err := manager.GetStateHash(0)
+	assert.NilError(t, err)
+	// Component values are hashed exactly as they are encoded in storage.
+	want := merkle.Tree{}.Set(uint64(fooComp.ID()), uint64(id), []byte("{\"Value\":5}\n"))
+	assert.DeepEqual(t, want.Root(), hash0)
+
+	hash1, err := manager.GetStateHash(1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hash0, hash1)
+
+	hash2, err := manager.GetStateHash(2)
+	assert.NilError(t, err)
+	assert.Check(t, len(hash2) == merkle.HashSize)
+	assert.Check(t, string(hash1) != string(hash2))
+
+	hash3, err := manager.GetStateHash(3)
+	assert.NilError(t, err)
+	assert.Check(t, hash3 == nil)
+}
+
+func TestUpdatedStateHashMatchesALoadedStateHash(t *testing.T) {
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+
+	ids, err := manager.CreateManyEntities(4, fooComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: i}))
+	}
+	assert.NilError(t, manager.FinalizeTick())
+
+	// Add a component to one entity, and remove and re-add a component on another.
+	assert.NilError(t, manager.AddComponentToEntity(barComp, ids[0]))
+	assert.NilError(t, manager.SetComponentForEntity(barComp, ids[0], Bar{Value: 10}))
+	assert.NilError(t, manager.AddComponentToEntity(barComp, ids[1]))
+	assert.NilError(t, manager.FinalizeTick())
+	assert.NilError(t, manager.RemoveComponentFromEntity(barComp, ids[1]))
+	assert.NilError(t, manager.AddComponentToEntity(barComp, ids[1]))
+	assert.NilError(t, manager.SetComponentForEntity(barComp, ids[1], Bar{Value: 11}))
+	assert.NilError(t, manager.FinalizeTick())
+
+	// Remove an entity after changing its components, and create an entity that is removed in the same tick.
+	assert.NilError(t, manager.AddComponentToEntity(barComp, ids[2]))
+	assert.NilError(t, manager.RemoveComponentFromEntity(fooComp, ids[0]))
+	assert.NilError(t, manager.RemoveEntity(ids[2]))
+	id, err := manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 20}))
+	assert.NilError(t, manager.RemoveEntity(id))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[3], Foo{Value: 13}))
+	assert.NilError(t, manager.FinalizeTick())
+	want, err := manager.GetStateHash(3)
+	assert.NilError(t, err)
+
+	// A new manager has to hash the complete state when it finalizes its first tick.
+	loaded, _ := newCmdBufferAndRedisClientForTest(t, client)
+	assert.NilError(t, loaded.FinalizeTick())
+	got, err := loaded.GetStateHash(4)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, want, got)
+}
+
+func TestComponentProofsVerifyAgainstTheStateHashOfTheirTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ids, err := manager.CreateManyEntities(3, fooComp)
+	assert.NilError(t, err)
+	_, err = manager.GetComponentProof(fooComp, ids[0], 0)
+	assert.ErrorIs(t, err, ecb.ErrNoStateTree)
+
+	assert.NilError(t, manager.AddComponentToEntity(barComp, ids[1]))
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: i}))
+	}
+	assert.NilError(t, manager.FinalizeTick())
+	// Changes that have not been finalized are not part of the proof.
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[2], Foo{Value: 100}))
+
+	stateHash, err := manager.GetStateHash(0)
+	assert.NilError(t, err)
+	for _, id := range ids {
+		proof, err := manager.GetComponentProof(fooComp, id, 0)
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(0), proof.Tick)
+		assert.DeepEqual(t, stateHash, proof.StateHash)
+		assert.NilError(t, merkle.Verify(stateHash, uint64(fooComp.ID()), uint64(id), proof.Value, proof.Proof))
+	}
+	// The default value of a component that was never set can be proven too.
+	proof, err := manager.GetComponentProof(barComp, ids[1], 0)
+	assert.NilError(t, err)
+	assert.NilError(t, merkle.Verify(stateHash, uint64(barComp.ID()), uint64(ids[1]), proof.Value, proof.Proof))
+
+	_, err = manager.GetComponentProof(barComp, ids[0], 0)
+	assert.ErrorIs(t, err, storage.ErrComponentNotOnEntity)
+
+	// Older ticks can still be proven after the value changed.
+	assert.NilError(t, manager.FinalizeTick())
+	oldProof, err := manager.GetComponentProof(fooComp, ids[2], 0)
+	assert.NilError(t, err)
+	assert.Equal(t, "{\"Value\":2}\n", string(oldProof.Value))
+	newProof, err := manager.GetComponentProof(fooComp, ids[2], 1)
+	assert.NilError(t, err)
+	assert.Equal(t, "{\"Value\":100}\n", string(newProof.Value))
+	stateHash, err = manager.GetStateHash(1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, stateHash, newProof.StateHash)
+	_, err = manager.GetComponentProof(fooComp, ids[2], 2)
+	assert.ErrorIs(t, err, ecb.ErrNoStateTree)
+
+	// Ticks that are older than the proof retention can no longer be proven.
+	manager.SetProofRetention(1)
+	assert.NilError(t, manager.FinalizeTick())
+	_, err = manager.GetComponentProof(fooComp, ids[2], 1)
+	assert.ErrorIs(t, err, ecb.ErrNoStateTree)
+	_, err = manager.GetComponentProof(fooComp, ids[2], 2)
+	assert.NilError(t, err)
+}
+
+func TestPendingStateHashMatchesTheFinalizedStateHash(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ids, err := manager.CreateManyEntities(2, fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[0], Foo{Value: 1}))
+	for tick := uint64(0); tick < 2; tick++ {
+		pending, err := manager.GetPendingStateHash()
+		assert.NilError(t, err)
+		assert.NilError(t, manager.FinalizeTick())
+		saved, err := manager.GetStateHash(tick)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, saved, pending)
+
+		// Later changes are included in the pending state hash, but do not change the saved one.
+		assert.NilError(t, manager.RemoveEntity(ids[tick]))
+		pending, err = manager.GetPendingStateHash()
+		assert.NilError(t, err)
+		assert.Check(t, string(saved) != string(pending))
+		again, err := manager.GetStateHash(tick)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, saved, again)
+	}
+}
+
+func TestStateHashesOutsideTheRetentionAreDeleted(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	manager.SetStateHashRetention(2)
+	for i := 0; i < 4; i++ {
+		assert.NilError(t, manager.FinalizeTick())
+	}
+	for tick := uint64(0); tick < 4; tick++ {
+		hash, err := manager.GetStateHash(tick)
+		assert.NilError(t, err)
+		assert.Equal(t, tick >= 2, hash != nil, "tick %d", tick)
+	}
+
+	// Lowering the retention deletes every tick that falls out of it.
+	manager.SetStateHashRetention(1)
+	assert.NilError(t, manager.FinalizeTick())
+	for tick := uint64(0); tick < 5; tick++ {
+		hash, err := manager.GetStateHash(tick)
+		assert.NilError(t, err)
+		assert.Equal(t, tick == 4, hash != nil, "tick %d", tick)
+	}
+}
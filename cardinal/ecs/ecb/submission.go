@@ -0,0 +1,81 @@
+package ecb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+// AddSubmission saves the given data as the current tick's submission to the base shard. It is written to the DB along
+// with the rest of the tick's state changes, so a tick that is committed always has its submission saved, and a tick
+// that fails never does.
+func (m *Manager) AddSubmission(data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingSubmission = data
+}
+
+// GetSubmissions returns every saved submission that has not been removed with RemoveSubmission, in tick order.
+func (m *Manager) GetSubmissions() ([]store.Submission, error) {
+	ctx := context.Background()
+	keys, err := m.kv.Keys(ctx, redisSubmissionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	values, err := m.kv.MGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	submissions := make([]store.Submission, 0, len(keys))
+	// Submission keys zero pad the tick number, so they are sorted by tick.
+	for i, key := range keys {
+		if values[i] == nil {
+			// The submission was removed after the keys were listed.
+			continue
+		}
+		tick, err := strconv.ParseUint(strings.TrimPrefix(key, redisSubmissionPrefix), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, store.Submission{Tick: tick, Data: values[i]})
+	}
+	return submissions, nil
+}
+
+// RemoveSubmission deletes the submission of the given tick. It is written right away, instead of along with a tick's
+// state changes, because the submission can not be undone if the tick fails.
+func (m *Manager) RemoveSubmission(tick uint64) error {
+	ctx := context.Background()
+	batch := m.kv.NewBatch()
+	if err := batch.Del(ctx, redisSubmissionKey(tick)); err != nil {
+		return err
+	}
+	return batch.Exec(ctx)
+}
+
+// GetTimestamp returns the timestamp of the last started tick, or 0 if no tick was started.
+func (m *Manager) GetTimestamp() (uint64, error) {
+	timestamp, err := getUint64(context.Background(), m.kv, redisPendingTimestampKey())
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	}
+	return timestamp, err
+}
+
+// addSubmissionToBatch saves the pending submission under the current tick.
+func (m *Manager) addSubmissionToBatch(ctx context.Context, batch KVBatch) error {
+	if m.pendingSubmission == nil {
+		return nil
+	}
+	tick, err := getUint64(ctx, m.kv, redisEndTickKey())
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+	return batch.Set(ctx, redisSubmissionKey(tick), m.pendingSubmission)
+}
@@ -3,8 +3,8 @@ package ecb
 import (
 	"context"
 	"errors"
+	"strconv"
 
-	"github.com/redis/go-redis/v9"
 	"pkg.world.dev/world-engine/cardinal/ecs/codec"
 	"pkg.world.dev/world-engine/cardinal/ecs/store"
 	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
@@ -21,14 +21,14 @@ var _ store.TickStorage = &Manager{}
 // be completed.
 func (m *Manager) GetTickNumbers() (start, end uint64, err error) {
 	ctx := context.Background()
-	start, err = m.client.Get(ctx, redisStartTickKey()).Uint64()
-	if errors.Is(err, redis.Nil) {
+	start, err = getUint64(ctx, m.kv, redisStartTickKey())
+	if errors.Is(err, ErrKeyNotFound) {
 		start = 0
 	} else if err != nil {
 		return 0, 0, err
 	}
-	end, err = m.client.Get(ctx, redisEndTickKey()).Uint64()
-	if errors.Is(err, redis.Nil) {
+	end, err = getUint64(ctx, m.kv, redisEndTickKey())
+	if errors.Is(err, ErrKeyNotFound) {
 		end = 0
 	} else if err != nil {
 		return 0, 0, err
@@ -36,44 +36,75 @@ func (m *Manager) GetTickNumbers() (start, end uint64, err error) {
 	return start, end, nil
 }
 
-// StartNextTick saves the given transactions to the DB and sets the tick trackers to indicate we are in the middle
-// of a tick. While transactions are saved to the DB, no state changes take place at this time.
+// StartNextTick saves the given transactions and the queue's timestamp to the DB and sets the tick trackers to indicate
+// we are in the middle of a tick. While transactions are saved to the DB, no state changes take place at this time.
 func (m *Manager) StartNextTick(txs []transaction.ITransaction, queue *transaction.TxQueue) error {
 	ctx := context.Background()
-	pipe := m.client.TxPipeline()
-	if err := addPendingTransactionToPipe(ctx, pipe, txs, queue); err != nil {
+	batch := m.kv.NewBatch()
+	if err := addPendingTransactionToBatch(ctx, batch, txs, queue); err != nil {
+		return err
+	}
+	var timestamp uint64
+	if queue != nil {
+		timestamp = queue.Timestamp()
+	}
+	if err := batch.Set(ctx, redisPendingTimestampKey(), []byte(strconv.FormatUint(timestamp, 10))); err != nil {
 		return err
 	}
 
-	if err := pipe.Incr(ctx, redisStartTickKey()).Err(); err != nil {
+	if err := batch.Incr(ctx, redisStartTickKey()); err != nil {
 		return err
 	}
 
-	_, err := pipe.Exec(ctx)
-	return err
+	return batch.Exec(ctx)
 }
 
-// FinalizeTick combines all pending state changes into a single multi/exec redis transactions and commits them
-// to the DB.
+// FinalizeTick combines all pending state changes into a single atomic batch and commits them
+// to the DB. On success, the changes that were made during the tick are available via LastTickDiff, the events that
+// were emitted during the tick are available via GetEvents, and the hash of the resulting state is available via
+// GetStateHash.
 func (m *Manager) FinalizeTick() error {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
 	ctx := context.Background()
-	pipe, err := m.makePipeOfRedisCommands(ctx)
+	diff, err := m.makeTickDiff(ctx)
+	if err != nil {
+		return err
+	}
+	batch, err := m.makeBatchOfCommands(ctx)
 	if err != nil {
 		return err
 	}
-	if err = pipe.Incr(context.Background(), redisEndTickKey()).Err(); err != nil {
+	tick, stateTree, err := m.addStateHashToBatch(ctx, batch)
+	if err != nil {
 		return err
 	}
-	_, err = pipe.Exec(ctx)
-	return err
+	if err = m.updateIndexes(ctx); err != nil {
+		m.isIndexesLoaded = false
+		return err
+	}
+	if err = batch.Incr(ctx, redisEndTickKey()); err != nil {
+		return err
+	}
+	if err = batch.Exec(ctx); err != nil {
+		// The indexes already include this tick's changes.
+		m.isIndexesLoaded = false
+		return err
+	}
+	m.saveStateTree(tick, stateTree)
+	m.lastTickDiff = diff
+	m.diff.reset()
+	m.pendingEvents = nil
+	m.pendingSubmission = nil
+	return nil
 }
 
-// Recover fetches the pending transactions for an incomplete tick. This should only be called if GetTickNumbers
-// indicates that the previous tick was started, but never completed.
+// Recover fetches the pending transactions and timestamp for an incomplete tick. This should only be called if
+// GetTickNumbers indicates that the previous tick was started, but never completed.
 func (m *Manager) Recover(txs []transaction.ITransaction) (*transaction.TxQueue, error) {
 	ctx := context.Background()
 	key := redisPendingTransactionKey()
-	bz, err := m.client.Get(ctx, key).Bytes()
+	bz, err := m.kv.Get(ctx, key)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +127,11 @@ func (m *Manager) Recover(txs []transaction.ITransaction) (*transaction.TxQueue,
 		}
 		txQueue.AddTransaction(tx.ID(), txData, p.Sig)
 	}
+	timestamp, err := getUint64(ctx, m.kv, redisPendingTimestampKey())
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+	txQueue.SetTimestamp(timestamp)
 	return txQueue, nil
 }
 
@@ -106,7 +142,7 @@ type pendingTransaction struct {
 	Sig    *sign.Transaction
 }
 
-func addPendingTransactionToPipe(ctx context.Context, pipe redis.Pipeliner, txs []transaction.ITransaction,
+func addPendingTransactionToBatch(ctx context.Context, batch KVBatch, txs []transaction.ITransaction,
 	queue *transaction.TxQueue) error {
 	var pending []pendingTransaction
 	for _, tx := range txs {
@@ -130,5 +166,5 @@ func addPendingTransactionToPipe(ctx context.Context, pipe redis.Pipeliner, txs
 		return err
 	}
 	key := redisPendingTransactionKey()
-	return pipe.Set(ctx, key, buf, 0).Err()
+	return batch.Set(ctx, key, buf)
 }
@@ -6,6 +6,7 @@ import (
 	"gotest.tools/v3/assert"
 	"pkg.world.dev/world-engine/cardinal/ecs"
 	"pkg.world.dev/world-engine/cardinal/ecs/internal/testutil"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
 	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
 )
 
@@ -27,6 +28,7 @@ func TestCanSaveAndRecoverTransactions(t *testing.T) {
 	originalQueue := transaction.NewTxQueue()
 	sig := testutil.UniqueSignature(t)
 	_ = originalQueue.AddTransaction(txAlpha.ID(), TxIn{100}, sig)
+	originalQueue.SetTimestamp(1700000000000)
 
 	assert.NilError(t, manager.StartNextTick(txs, originalQueue))
 
@@ -36,6 +38,8 @@ func TestCanSaveAndRecoverTransactions(t *testing.T) {
 	assert.NilError(t, err)
 
 	assert.Equal(t, gotQueue.GetAmountOfTxs(), originalQueue.GetAmountOfTxs())
+	// The recovered tick must see the same timestamp as the original tick.
+	assert.Equal(t, gotQueue.Timestamp(), originalQueue.Timestamp())
 
 	// Make sure we can finalize the tick
 	assert.NilError(t, manager.StartNextTick(txs, gotQueue))
@@ -48,3 +52,32 @@ func TestErrorWhenRecoveringNoTransactions(t *testing.T) {
 	// Recover should fail when no transactions have previously been saved to the DB.
 	assert.Check(t, err != nil)
 }
+
+func TestSubmissionsAreSavedWithTheirTick(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	queue := transaction.NewTxQueue()
+
+	assert.NilError(t, manager.StartNextTick(nil, queue))
+	manager.AddSubmission([]byte("tick-0"))
+	assert.NilError(t, manager.FinalizeTick())
+
+	// A submission of a tick that is discarded is never saved.
+	assert.NilError(t, manager.StartNextTick(nil, queue))
+	manager.AddSubmission([]byte("discarded"))
+	manager.DiscardPending()
+	assert.NilError(t, manager.FinalizeTick())
+
+	assert.NilError(t, manager.StartNextTick(nil, queue))
+	manager.AddSubmission([]byte("tick-2"))
+	assert.NilError(t, manager.FinalizeTick())
+
+	submissions, err := manager.GetSubmissions()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []store.Submission{{Tick: 0, Data: []byte("tick-0")}, {Tick: 2, Data: []byte("tick-2")}},
+		submissions)
+
+	assert.NilError(t, manager.RemoveSubmission(0))
+	submissions, err = manager.GetSubmissions()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []store.Submission{{Tick: 2, Data: []byte("tick-2")}}, submissions)
+}
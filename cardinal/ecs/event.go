@@ -0,0 +1,219 @@
+package ecs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/events"
+	"pkg.world.dev/world-engine/sign"
+)
+
+var (
+	ErrDuplicateEventName    = errors.New("event names must be unique")
+	ErrEventNotRegistered    = errors.New("event type has not been registered")
+	ErrEventHubNotAttached   = errors.New("world does not have an event hub")
+	ErrInvalidEventHandshake = errors.New("invalid event handshake")
+)
+
+// EventHandshake is the body of a system transaction that authenticates an /events connection as a signer rather than
+// as a single persona. The connection then receives the events of every persona the signer has signed for.
+type EventHandshake struct {
+	SignerAddress string `json:"signerAddress"`
+}
+
+// IEvent is implemented by the return value of NewEventType and is used in RegisterEvents.
+type IEvent interface {
+	// Name returns the name of the event.
+	Name() string
+	// Schema returns the json schema of the event payload.
+	Schema() *jsonschema.Schema
+}
+
+// EventType is a kind of event that can be emitted by systems. The Payload struct is JSON encoded and sent to clients
+// along with the name of the event type and the tick it was emitted in.
+type EventType[Payload any] struct {
+	name string
+}
+
+// EventOption sets optional fields on an event before it is emitted.
+type EventOption func(event *events.Event)
+
+// WithEventEntityID marks the emitted event as being about the given entity.
+func WithEventEntityID(id entity.ID) EventOption {
+	return func(event *events.Event) {
+		event.EntityID = &id
+	}
+}
+
+// WithEventPersonaTag marks the emitted event as being meant for the given persona.
+func WithEventPersonaTag(personaTag string) EventOption {
+	return func(event *events.Event) {
+		event.PersonaTag = personaTag
+	}
+}
+
+var _ IEvent = &EventType[struct{}]{}
+
+func NewEventType[Payload any](name string) *EventType[Payload] {
+	if name == "" {
+		panic("cannot create event without name")
+	}
+	var payload Payload
+	payloadType := reflect.TypeOf(payload)
+	if payloadType == nil || payloadType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("Invalid EventType: %s: The Payload must be a struct", name))
+	}
+	return &EventType[Payload]{
+		name: name,
+	}
+}
+
+func (e *EventType[Payload]) Name() string {
+	return e.name
+}
+
+func (e *EventType[Payload]) Schema() *jsonschema.Schema {
+	return jsonschema.Reflect(new(Payload))
+}
+
+// Emit queues up an event with the given payload. The event is sent to clients when the current tick ends.
+func (e *EventType[Payload]) Emit(wCtx WorldContext, payload Payload, opts ...EventOption) error {
+	world := wCtx.GetWorld()
+	if !world.isEventRegistered(e.name) {
+		return fmt.Errorf("cannot emit event %q: %w", e.name, ErrEventNotRegistered)
+	}
+	if world.eventHub == nil {
+		return ErrEventHubNotAttached
+	}
+	bz, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to marshal event payload %T: %w", payload, err)
+	}
+	event := &events.Event{
+		Name:    e.name,
+		Payload: bz,
+		Tick:    wCtx.CurrentTick(),
+	}
+	for _, opt := range opts {
+		opt(event)
+	}
+	wCtx.EmitEvent(event)
+	return nil
+}
+
+// EmitTo queues up an event with the given payload that is only sent to clients that have authenticated as the given
+// persona (or as its signer).
+func (e *EventType[Payload]) EmitTo(wCtx WorldContext, personaTag string, payload Payload, opts ...EventOption) error {
+	return e.Emit(wCtx, payload, append(opts, WithEventPersonaTag(personaTag))...)
+}
+
+// RegisterEvents adds the given event types to the world. Only registered event types can be emitted, and the
+// registered event types are advertised to clients along with their schemas.
+func (w *World) RegisterEvents(evts ...IEvent) error {
+	if w.stateIsLoaded {
+		panic("cannot register events after loading game state")
+	}
+	for _, evt := range evts {
+		name := evt.Name()
+		if w.isEventRegistered(name) {
+			return fmt.Errorf("duplicate event %q: %w", name, ErrDuplicateEventName)
+		}
+		w.registeredEvents = append(w.registeredEvents, evt)
+	}
+	return nil
+}
+
+func (w *World) ListEvents() []IEvent {
+	return w.registeredEvents
+}
+
+func (w *World) isEventRegistered(name string) bool {
+	for _, evt := range w.registeredEvents {
+		if evt.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// handshakeNoncePrefix is prepended to the signer address to get the key of the signer's handshake nonce in the nonce
+// store. Handshakes have their own nonces so that authenticating a connection never uses up a transaction nonce.
+const handshakeNoncePrefix = "handshake:"
+
+// AuthenticateEventConnection verifies the signed handshake of an /events connection. A regular transaction must be
+// signed by the signer of its persona tag, and authenticates the connection as that persona. A system transaction must
+// have an EventHandshake body, must be signed by the address in the body, and authenticates the connection as that
+// signer. The nonce must be greater than the nonce of the signer's previous handshake. Transaction nonces are not
+// checked or used.
+//
+// This is called from the connection's goroutine while ticks run, so personas are looked up in the committed state.
+// A persona that was created in the current tick can only be authenticated once the tick has ended.
+func (w *World) AuthenticateEventConnection(sp *sign.Transaction) (events.Identity, error) {
+	var identity events.Identity
+	if sp.Namespace != w.Namespace().String() {
+		return identity, fmt.Errorf("%w: got namespace %q but it must be %q",
+			ErrInvalidEventHandshake, sp.Namespace, w.Namespace().String())
+	}
+	if sp.IsSystemTransaction() {
+		var handshake EventHandshake
+		if err := json.Unmarshal(sp.Body, &handshake); err != nil {
+			return identity, fmt.Errorf("%w: %w", ErrInvalidEventHandshake, err)
+		}
+		if handshake.SignerAddress == "" {
+			return identity, fmt.Errorf("%w: signer address must not be empty", ErrInvalidEventHandshake)
+		}
+		identity.SignerAddress = handshake.SignerAddress
+	} else {
+		signerAddress, err := signerForPersonaTag(NewReadOnlyWorldContext(w), sp.PersonaTag)
+		if err != nil {
+			return identity, err
+		}
+		identity.PersonaTag = sp.PersonaTag
+		identity.SignerAddress = signerAddress
+	}
+	if err := sp.Verify(identity.SignerAddress); err != nil {
+		return events.Identity{}, fmt.Errorf("%w: %w", ErrInvalidEventHandshake, err)
+	}
+	if err := w.useHandshakeNonce(identity.SignerAddress, sp.Nonce); err != nil {
+		return events.Identity{}, err
+	}
+	return identity, nil
+}
+
+// useHandshakeNonce saves the given handshake nonce of the signer, or returns an error if it is not greater than the
+// saved one. Concurrent handshakes are serialized so the same nonce can never be used twice.
+func (w *World) useHandshakeNonce(signerAddress string, nonce uint64) error {
+	w.handshakeMu.Lock()
+	defer w.handshakeMu.Unlock()
+	key := handshakeNoncePrefix + signerAddress
+	saved, err := w.nonceStore.GetNonce(key)
+	if err != nil {
+		return err
+	}
+	if nonce <= saved {
+		return fmt.Errorf("%w: got nonce %d, but must be greater than %d", ErrInvalidEventHandshake, nonce, saved)
+	}
+	return w.nonceStore.SetNonce(key, nonce)
+}
+
+// ReadEventLog returns the saved events that were emitted during or after the given tick, in the order they were
+// emitted. If only some of the events were read, nextTick is the tick to read the rest from; otherwise it is 0.
+func (w *World) ReadEventLog(fromTick uint64) (evts []*events.Event, nextTick uint64, err error) {
+	saved, nextTick, err := w.entityStore.GetEvents(fromTick)
+	if err != nil {
+		return nil, 0, err
+	}
+	evts = make([]*events.Event, 0, len(saved))
+	for _, bz := range saved {
+		event := &events.Event{}
+		if err = json.Unmarshal(bz, event); err != nil {
+			return nil, 0, err
+		}
+		evts = append(evts, event)
+	}
+	return evts, nextTick, nil
+}
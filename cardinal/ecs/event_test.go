@@ -0,0 +1,44 @@
+package ecs_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+)
+
+type LevelUpEvent struct {
+	Level int `json:"level"`
+}
+
+func TestEventTypesMustHaveUniqueNames(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, w.RegisterEvents(ecs.NewEventType[LevelUpEvent]("level-up")))
+	err := w.RegisterEvents(ecs.NewEventType[LevelUpEvent]("level-up"))
+	assert.ErrorIs(t, err, ecs.ErrDuplicateEventName)
+	assert.Equal(t, 1, len(w.ListEvents()))
+}
+
+func TestEventTypePayloadMustBeAStruct(t *testing.T) {
+	defer func() {
+		assert.Check(t, recover() != nil)
+	}()
+	ecs.NewEventType[int]("not-a-struct")
+}
+
+func TestEventTypeSchemaDescribesPayload(t *testing.T) {
+	schema := ecs.NewEventType[LevelUpEvent]("level-up").Schema()
+	def, ok := schema.Definitions["LevelUpEvent"]
+	assert.Assert(t, ok)
+	_, ok = def.Properties.Get("level")
+	assert.Check(t, ok)
+}
+
+func TestUnregisteredEventTypesCannotBeEmitted(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	levelUp := ecs.NewEventType[LevelUpEvent]("level-up")
+	assert.NilError(t, w.LoadGameState())
+	err := levelUp.Emit(ecs.NewWorldContext(w), LevelUpEvent{Level: 2})
+	assert.ErrorIs(t, err, ecs.ErrEventNotRegistered)
+}
@@ -0,0 +1,47 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+// ComponentOption configures a component when it is registered with RegisterComponent.
+type ComponentOption func(c metadata.ComponentMetadata) error
+
+// WithIndex indexes the given field of the component, so entities can be found by the value of the field with
+// Search.Where instead of checking every entity. Indexes only contain committed values; changes made during the current
+// tick are checked one by one. If the field is a slice or an array, entities can be found by any of its elements.
+func WithIndex(field string) ComponentOption {
+	return func(c metadata.ComponentMetadata) error {
+		return c.AddIndex(field)
+	}
+}
+
+// getIndexedField returns the component and field that the given name refers to. The name is either
+// "Component.Field", or the name of a field that is indexed by exactly one registered component.
+func (w *World) getIndexedField(name string) (metadata.ComponentMetadata, string, error) {
+	if compName, field, ok := strings.Cut(name, "."); ok {
+		c, err := w.GetComponentByName(compName)
+		return c, field, err
+	}
+	var found metadata.ComponentMetadata
+	for _, c := range w.registeredComponents {
+		for _, field := range c.Indexes() {
+			if field != name {
+				continue
+			}
+			if found != nil {
+				return nil, "", fmt.Errorf("field %q is indexed by both %s and %s, use %s.%s or %s.%s instead", name,
+					found.Name(), c.Name(), found.Name(), name, c.Name(), name)
+			}
+			found = c
+		}
+	}
+	if found == nil {
+		return nil, "", fmt.Errorf("%w: no component indexes %q", storage.ErrFieldNotIndexed, name)
+	}
+	return found, name, nil
+}
@@ -0,0 +1,379 @@
+/*
+Package merkle builds the Merkle tree whose root is a world's state hash.
+
+Every component value of every entity is a leaf of the tree. The tree has two levels: the values of each component type
+are the leaves of a tree of that type, and the roots of those trees are the leaves of the state tree. Each leaf has a
+fixed position, which is its entity ID in the tree of a component type and its component type ID in the state tree, so
+the root only depends on the state of the world and not on the order it was built in. A value leaf hash is
+
+	SHA256(0x00 || componentTypeID || entityID || value)
+
+where both IDs are 8 byte big endian integers and value is the encoded component value. A component type leaf hash is
+
+	SHA256(0x02 || componentTypeID || root of the component type's tree)
+
+and an interior node hash is
+
+	SHA256(0x01 || left || right)
+
+Both trees are complete binary trees with height h, where h is the smallest height that fits the largest position in
+use (a tree whose only leaf is at position 0 has height 0). Positions without a leaf are empty. The hash of an empty
+subtree of height 0 is the SHA256 hash of an empty input, and the hash of an empty subtree of height h+1 is the node
+hash of two empty subtrees of height h, so the root of a state without any values is the SHA256 hash of an empty input.
+
+Only subtrees that hold at least one leaf are kept, and a Tree is never changed once it is built. Set and Delete return
+a new Tree that shares every node except the ones on the path to the changed leaf, so a change costs O(h) hashes and
+older trees stay valid, e.g. to build proofs against the state of an older tick.
+
+A Proof shows that a single component value is part of the tree with a given root, without needing any of the other
+values. Verify only depends on the standard library, so clients can check values they got from a game shard against a
+state hash they got from somewhere they trust, like the base shard.
+*/
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// HashSize is the size of every leaf, node and root hash.
+const HashSize = sha256.Size
+
+// maxHeight is the height of a tree that fits every 64 bit position.
+const maxHeight = 64
+
+var ErrInvalidProof = errors.New("invalid merkle proof")
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+	typePrefix = 0x02
+)
+
+// emptyHashes holds the hash of an empty subtree of every height.
+var emptyHashes = func() [maxHeight + 1][]byte {
+	var hashes [maxHeight + 1][]byte
+	empty := sha256.Sum256(nil)
+	hashes[0] = empty[:]
+	for i := 1; i <= maxHeight; i++ {
+		hashes[i] = nodeHash(hashes[i-1], hashes[i-1])
+	}
+	return hashes
+}()
+
+// LeafHash returns the hash of the leaf that holds the encoded value of the given component on the given entity.
+func LeafHash(componentTypeID, entityID uint64, value []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{leafPrefix})
+	hash.Write(binary.BigEndian.AppendUint64(nil, componentTypeID))
+	hash.Write(binary.BigEndian.AppendUint64(nil, entityID))
+	hash.Write(value)
+	return hash.Sum(nil)
+}
+
+// typeHash returns the hash of the leaf that holds the root of the tree of the given component type.
+func typeHash(componentTypeID uint64, root []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{typePrefix})
+	hash.Write(binary.BigEndian.AppendUint64(nil, componentTypeID))
+	hash.Write(root)
+	return hash.Sum(nil)
+}
+
+// nodeHash returns the hash of the interior node with the given children.
+func nodeHash(left, right []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{nodePrefix})
+	hash.Write(left)
+	hash.Write(right)
+	return hash.Sum(nil)
+}
+
+// Leaf is a single encoded component value of a single entity.
+type Leaf struct {
+	ComponentTypeID uint64
+	EntityID        uint64
+	Value           []byte
+}
+
+// Tree is the Merkle tree of every component value of every entity. The zero Tree has no values. Trees are never
+// changed, so they can be shared between goroutines without any locking.
+type Tree struct {
+	types subtree
+}
+
+// NewTree returns the tree with the given leaves, which may be in any order. Every leaf must have a different
+// component type ID and entity ID pair.
+func NewTree(leaves []Leaf) Tree {
+	leaves = append([]Leaf(nil), leaves...)
+	sort.Slice(leaves, func(i, j int) bool {
+		if leaves[i].ComponentTypeID != leaves[j].ComponentTypeID {
+			return leaves[i].ComponentTypeID < leaves[j].ComponentTypeID
+		}
+		return leaves[i].EntityID < leaves[j].EntityID
+	})
+	var types []position
+	for len(leaves) > 0 {
+		typeID := leaves[0].ComponentTypeID
+		end := sort.Search(len(leaves), func(i int) bool {
+			return leaves[i].ComponentTypeID != typeID
+		})
+		values := make([]position, end)
+		for i, leaf := range leaves[:end] {
+			values[i] = position{index: leaf.EntityID, node: newValueLeaf(leaf)}
+		}
+		types = append(types, position{index: typeID, node: newTypeLeaf(typeID, newSubtree(values))})
+		leaves = leaves[end:]
+	}
+	return Tree{types: newSubtree(types)}
+}
+
+// Root returns the root hash of the tree.
+func (t Tree) Root() []byte {
+	return t.types.hash()
+}
+
+// Get returns the encoded value of the given component on the given entity.
+func (t Tree) Get(componentTypeID, entityID uint64) ([]byte, bool) {
+	typeLeaf := t.types.get(componentTypeID)
+	if typeLeaf == nil {
+		return nil, false
+	}
+	leaf := typeLeaf.values.get(entityID)
+	if leaf == nil {
+		return nil, false
+	}
+	return leaf.value, true
+}
+
+// Set returns a tree where the given component on the given entity has the given encoded value. The value must not
+// be changed afterwards.
+func (t Tree) Set(componentTypeID, entityID uint64, value []byte) Tree {
+	var values subtree
+	if typeLeaf := t.types.get(componentTypeID); typeLeaf != nil {
+		values = *typeLeaf.values
+	}
+	leaf := newValueLeaf(Leaf{ComponentTypeID: componentTypeID, EntityID: entityID, Value: value})
+	values = values.with(entityID, leaf)
+	return Tree{types: t.types.with(componentTypeID, newTypeLeaf(componentTypeID, values))}
+}
+
+// Delete returns a tree without the value of the given component on the given entity.
+func (t Tree) Delete(componentTypeID, entityID uint64) Tree {
+	typeLeaf := t.types.get(componentTypeID)
+	if typeLeaf == nil || typeLeaf.values.get(entityID) == nil {
+		return t
+	}
+	values := typeLeaf.values.with(entityID, nil)
+	if values.root == nil {
+		return Tree{types: t.types.with(componentTypeID, nil)}
+	}
+	return Tree{types: t.types.with(componentTypeID, newTypeLeaf(componentTypeID, values))}
+}
+
+// Proof returns the proof that the value of the given component on the given entity is part of the tree. false is
+// returned if the tree has no such value.
+func (t Tree) Proof(componentTypeID, entityID uint64) (*Proof, bool) {
+	typeLeaf := t.types.get(componentTypeID)
+	if typeLeaf == nil || typeLeaf.values.get(entityID) == nil {
+		return nil, false
+	}
+	return &Proof{
+		ValuePath: typeLeaf.values.path(entityID),
+		TypePath:  t.types.path(componentTypeID),
+	}, true
+}
+
+// node is a leaf or an interior node of a subtree. Nodes are never changed once they are built.
+type node struct {
+	left, right *node
+	hash        []byte
+	// value is the encoded value of a value leaf, and values is the tree of the values of a component type leaf.
+	value  []byte
+	values *subtree
+}
+
+func newValueLeaf(leaf Leaf) *node {
+	return &node{hash: LeafHash(leaf.ComponentTypeID, leaf.EntityID, leaf.Value), value: leaf.Value}
+}
+
+func newTypeLeaf(componentTypeID uint64, values subtree) *node {
+	return &node{hash: typeHash(componentTypeID, values.hash()), values: &values}
+}
+
+// newNode returns the interior node at the given height with the given children, either of which may be empty.
+func newNode(left, right *node, height int) *node {
+	return &node{left: left, right: right, hash: nodeHash(hashAt(left, height-1), hashAt(right, height-1))}
+}
+
+// hashAt returns the hash of the given subtree of the given height, which is nil if the subtree is empty.
+func hashAt(n *node, height int) []byte {
+	if n == nil {
+		return emptyHashes[height]
+	}
+	return n.hash
+}
+
+// subtree is a complete binary tree of the given height, whose root is nil if it has no leaves.
+type subtree struct {
+	root   *node
+	height int
+}
+
+// position is a leaf at the given position of a subtree.
+type position struct {
+	index uint64
+	node  *node
+}
+
+// newSubtree returns the subtree with the given leaves, which must be sorted by position.
+func newSubtree(leaves []position) subtree {
+	if len(leaves) == 0 {
+		return subtree{}
+	}
+	height := bits.Len64(leaves[len(leaves)-1].index)
+	return subtree{root: build(leaves, height), height: height}
+}
+
+func build(leaves []position, height int) *node {
+	if len(leaves) == 0 {
+		return nil
+	}
+	if height == 0 {
+		return leaves[0].node
+	}
+	split := sort.Search(len(leaves), func(i int) bool {
+		return isRight(leaves[i].index, height)
+	})
+	return newNode(build(leaves[:split], height-1), build(leaves[split:], height-1), height)
+}
+
+// isRight reports whether the given position is in the right subtree of a node at the given height.
+func isRight(index uint64, height int) bool {
+	return (index>>(height-1))&1 == 1
+}
+
+func (s subtree) hash() []byte {
+	return hashAt(s.root, s.height)
+}
+
+// get returns the leaf at the given position, or nil if there is none.
+func (s subtree) get(index uint64) *node {
+	if bits.Len64(index) > s.height {
+		return nil
+	}
+	n := s.root
+	for height := s.height; height > 0 && n != nil; height-- {
+		if isRight(index, height) {
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return n
+}
+
+// with returns a subtree where the given leaf is at the given position. The leaf at the position is removed if the
+// given leaf is nil. The height grows and shrinks with the largest position in use.
+func (s subtree) with(index uint64, leaf *node) subtree {
+	if leaf == nil && s.get(index) == nil {
+		return s
+	}
+	for bits.Len64(index) > s.height {
+		if s.root != nil {
+			s.root = newNode(s.root, nil, s.height+1)
+		}
+		s.height++
+	}
+	s.root = with(s.root, s.height, index, leaf)
+	for s.height > 0 && (s.root == nil || s.root.right == nil) {
+		if s.root != nil {
+			s.root = s.root.left
+		}
+		s.height--
+	}
+	return s
+}
+
+func with(n *node, height int, index uint64, leaf *node) *node {
+	if height == 0 {
+		return leaf
+	}
+	var left, right *node
+	if n != nil {
+		left, right = n.left, n.right
+	}
+	if isRight(index, height) {
+		right = with(right, height-1, index, leaf)
+	} else {
+		left = with(left, height-1, index, leaf)
+	}
+	if left == nil && right == nil {
+		return nil
+	}
+	return newNode(left, right, height)
+}
+
+// path returns the hash of every sibling subtree on the path from the leaf at the given position to the root,
+// starting with the leaf's sibling.
+func (s subtree) path(index uint64) [][]byte {
+	path := make([][]byte, s.height)
+	n := s.root
+	for height := s.height; height > 0; height-- {
+		if isRight(index, height) {
+			path[height-1] = hashAt(n.left, height-1)
+			n = n.right
+		} else {
+			path[height-1] = hashAt(n.right, height-1)
+			n = n.left
+		}
+	}
+	return path
+}
+
+// Proof is the inclusion proof of a single component value, also known as an audit path.
+type Proof struct {
+	// ValuePath is the root hash of every sibling subtree on the path from the value's leaf to the root of the tree
+	// of its component type, starting with the leaf's sibling.
+	ValuePath [][]byte `json:"valuePath"`
+	// TypePath is the root hash of every sibling subtree on the path from the component type's leaf to the root of the
+	// state tree, starting with the leaf's sibling.
+	TypePath [][]byte `json:"typePath"`
+}
+
+// Verify checks that the given encoded value of the given component on the given entity is part of the tree with the
+// given root. ErrInvalidProof is returned if it is not.
+func Verify(root []byte, componentTypeID, entityID uint64, value []byte, proof *Proof) error {
+	if proof == nil {
+		return fmt.Errorf("%w: proof is missing", ErrInvalidProof)
+	}
+	if len(proof.ValuePath) > maxHeight || len(proof.TypePath) > maxHeight {
+		return fmt.Errorf("%w: path is too long", ErrInvalidProof)
+	}
+	if bits.Len64(entityID) > len(proof.ValuePath) || bits.Len64(componentTypeID) > len(proof.TypePath) {
+		return fmt.Errorf("%w: path is too short", ErrInvalidProof)
+	}
+	hash := climb(LeafHash(componentTypeID, entityID, value), entityID, proof.ValuePath)
+	hash = climb(typeHash(componentTypeID, hash), componentTypeID, proof.TypePath)
+	if !bytes.Equal(hash, root) {
+		return fmt.Errorf("%w: value does not match the root", ErrInvalidProof)
+	}
+	return nil
+}
+
+// climb returns the root of the subtree with the given leaf hash at the given position and the given path.
+func climb(hash []byte, index uint64, path [][]byte) []byte {
+	for i, sibling := range path {
+		if (index>>i)&1 == 1 {
+			hash = nodeHash(sibling, hash)
+		} else {
+			hash = nodeHash(hash, sibling)
+		}
+	}
+	return hash
+}
@@ -0,0 +1,139 @@
+package merkle_test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/merkle"
+)
+
+func node(left, right []byte) []byte {
+	hash := sha256.Sum256(append(append([]byte{0x01}, left...), right...))
+	return hash[:]
+}
+
+func typeLeaf(typeID uint64, root []byte) []byte {
+	hash := sha256.Sum256(append(binary.BigEndian.AppendUint64([]byte{0x02}, typeID), root...))
+	return hash[:]
+}
+
+func TestRootOfSmallTrees(t *testing.T) {
+	empty := sha256.Sum256(nil)
+	assert.DeepEqual(t, empty[:], merkle.Tree{}.Root())
+	assert.DeepEqual(t, empty[:], merkle.NewTree(nil).Root())
+
+	a := merkle.LeafHash(0, 0, []byte(`{"Value":1}`))
+	assert.DeepEqual(t, typeLeaf(0, a), merkle.Tree{}.Set(0, 0, []byte(`{"Value":1}`)).Root())
+
+	// Entity 2 needs a tree of height 2, and component type 1 a tree of height 1.
+	b := merkle.LeafHash(1, 2, []byte(`{"Value":2}`))
+	emptyNode := node(empty[:], empty[:])
+	want := node(empty[:], typeLeaf(1, node(emptyNode, node(b, empty[:]))))
+	assert.DeepEqual(t, want, merkle.Tree{}.Set(1, 2, []byte(`{"Value":2}`)).Root())
+}
+
+func TestLeafHashIncludesEveryField(t *testing.T) {
+	leaf := merkle.LeafHash(1, 2, []byte("value"))
+	assert.Equal(t, merkle.HashSize, len(leaf))
+	assert.Check(t, string(leaf) != string(merkle.LeafHash(2, 1, []byte("value"))))
+	assert.Check(t, string(leaf) != string(merkle.LeafHash(1, 2, []byte("other"))))
+	// A leaf can never be mistaken for an interior node.
+	assert.Check(t, string(node(leaf, leaf)) != string(merkle.LeafHash(0, 0, append(leaf, leaf...))))
+}
+
+func randomLeaves(r *rand.Rand, count int) []merkle.Leaf {
+	seen := map[[2]uint64]bool{}
+	var leaves []merkle.Leaf
+	for len(leaves) < count {
+		key := [2]uint64{uint64(r.Intn(5)), uint64(r.Intn(100))}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		leaves = append(leaves, merkle.Leaf{ComponentTypeID: key[0], EntityID: key[1], Value: []byte{byte(r.Int())}})
+	}
+	return leaves
+}
+
+func TestRootOnlyDependsOnTheValues(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		leaves := randomLeaves(r, 50)
+		want := merkle.NewTree(leaves)
+
+		// Add the values in a different order, along with values that are changed or deleted later.
+		var tree merkle.Tree
+		for _, j := range r.Perm(len(leaves)) {
+			leaf := leaves[j]
+			tree = tree.Set(leaf.ComponentTypeID, leaf.EntityID, []byte("old"))
+			tree = tree.Set(leaf.ComponentTypeID, 1000+leaf.EntityID, nil)
+		}
+		before := tree
+		beforeRoot := before.Root()
+		for _, leaf := range leaves {
+			tree = tree.Set(leaf.ComponentTypeID, leaf.EntityID, leaf.Value)
+			tree = tree.Delete(leaf.ComponentTypeID, 1000+leaf.EntityID)
+		}
+		assert.DeepEqual(t, want.Root(), tree.Root())
+		// Older trees are never changed.
+		assert.DeepEqual(t, beforeRoot, before.Root())
+
+		for _, leaf := range leaves {
+			value, ok := tree.Get(leaf.ComponentTypeID, leaf.EntityID)
+			assert.Check(t, ok)
+			assert.DeepEqual(t, leaf.Value, value)
+			tree = tree.Delete(leaf.ComponentTypeID, leaf.EntityID)
+		}
+		assert.DeepEqual(t, merkle.Tree{}.Root(), tree.Root())
+	}
+}
+
+func TestProofsOfEveryLeafVerify(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for count := 1; count <= 20; count++ {
+		leaves := randomLeaves(r, count)
+		tree := merkle.NewTree(leaves)
+		root := tree.Root()
+		for _, leaf := range leaves {
+			typeID, id := leaf.ComponentTypeID, leaf.EntityID
+			proof, ok := tree.Proof(typeID, id)
+			assert.Check(t, ok)
+			assert.NilError(t, merkle.Verify(root, typeID, id, leaf.Value, proof))
+
+			// The proof does not hold for any other value, entity or component type.
+			assert.ErrorIs(t, merkle.Verify(root, typeID, id, []byte("other"), proof), merkle.ErrInvalidProof)
+			assert.ErrorIs(t, merkle.Verify(root, typeID, id^1, leaf.Value, proof), merkle.ErrInvalidProof)
+			assert.ErrorIs(t, merkle.Verify(root, typeID^1, id, leaf.Value, proof), merkle.ErrInvalidProof)
+		}
+	}
+	_, ok := merkle.Tree{}.Set(1, 1, nil).Proof(1, 2)
+	assert.Check(t, !ok)
+}
+
+func TestTamperedProofsDoNotVerify(t *testing.T) {
+	var tree merkle.Tree
+	for i := uint64(0); i < 5; i++ {
+		tree = tree.Set(1, i, nil)
+	}
+	root := tree.Root()
+	proof, ok := tree.Proof(1, 2)
+	assert.Check(t, ok)
+	assert.NilError(t, merkle.Verify(root, 1, 2, nil, proof))
+
+	shortPath := *proof
+	shortPath.ValuePath = proof.ValuePath[1:]
+	assert.ErrorIs(t, merkle.Verify(root, 1, 2, nil, &shortPath), merkle.ErrInvalidProof)
+
+	longPath := *proof
+	longPath.ValuePath = append(append([][]byte{}, proof.ValuePath...), proof.ValuePath[0])
+	assert.ErrorIs(t, merkle.Verify(root, 1, 2, nil, &longPath), merkle.ErrInvalidProof)
+
+	swapped := merkle.Proof{ValuePath: proof.TypePath, TypePath: proof.ValuePath}
+	assert.ErrorIs(t, merkle.Verify(root, 1, 2, nil, &swapped), merkle.ErrInvalidProof)
+
+	assert.ErrorIs(t, merkle.Verify(root, 1, 2, nil, nil), merkle.ErrInvalidProof)
+}
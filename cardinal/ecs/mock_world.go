@@ -6,41 +6,31 @@ import (
 
 	"gotest.tools/v3/assert"
 
-	"github.com/alicebob/miniredis/v2"
 	"github.com/rs/zerolog/log"
 	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
 	"pkg.world.dev/world-engine/cardinal/ecs/storage"
 )
 
-// NewMockWorld creates an ecs.World that uses a mock redis DB as the storage
-// layer. This is only suitable for local development. If you are creating an ecs.World for
-// unit tests, use NewTestWorld.
+// NewMockWorld creates an ecs.World that keeps all state in process memory. This is only suitable for local
+// development. If you are creating an ecs.World for unit tests, use NewTestWorld.
 func NewMockWorld(opts ...Option) (world *World, cleanup func()) {
-	// We manually set the start address to make the port deterministic
-	s := miniredis.NewMiniRedis()
-	err := s.StartAddr(":12345")
-	if err != nil {
-		panic("Unable to initialize in-memory redis")
-	}
-	log.Logger.Debug().Msgf("miniredis started at %s", s.Addr())
-
-	w, err := newMockWorld(s, opts...)
+	w, err := NewInMemoryWorld(opts...)
 	if err != nil {
 		panic(fmt.Errorf("unable to initialize world: %w", err))
 	}
 
 	return w, func() {
-		log.Logger.Debug().Msg("miniredis shutting down")
-		s.Close()
-		log.Logger.Debug().Msg("successfully shut down miniredis")
+		log.Logger.Debug().Msg("in-memory storage shutting down")
+		if err := w.StoreManager().Close(); err != nil {
+			log.Logger.Error().Err(err).Msg("failed to close in-memory storage")
+		}
 	}
 }
 
 // NewTestWorld creates an ecs.World suitable for running in tests. Relevant resources
 // are automatically cleaned up at the completion of each test.
 func NewTestWorld(t testing.TB, opts ...Option) *World {
-	s := miniredis.RunT(t)
-	w, err := newMockWorld(s, opts...)
+	w, err := NewInMemoryWorld(opts...)
 	if err != nil {
 		t.Fatalf("Unable to initialize world: %v", err)
 	}
@@ -48,16 +38,14 @@ func NewTestWorld(t testing.TB, opts ...Option) *World {
 	return w
 }
 
-func newMockWorld(s *miniredis.Miniredis, opts ...Option) (*World, error) {
-	redisStore := storage.NewRedisStorage(storage.Options{
-		Addr:     s.Addr(),
-		Password: "", // no password set
-		DB:       0,  // use default DB
-	}, "in-memory-world")
-	entityStore, err := ecb.NewManager(redisStore.Client)
+// NewInMemoryWorld creates an ecs.World that does not depend on any external storage. Entities, components, nonces
+// and tick information are all kept in process memory, so many of these worlds can safely run side by side in the
+// same process.
+func NewInMemoryWorld(opts ...Option) (*World, error) {
+	entityStore, err := ecb.NewManagerWithKVStore(ecb.NewMemoryKVStore())
 	if err != nil {
 		return nil, err
 	}
 
-	return NewWorld(&redisStore, entityStore, opts...)
+	return NewWorld(storage.NewMemoryStorage(), entityStore, opts...)
 }
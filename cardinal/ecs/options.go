@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"os"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -26,6 +27,17 @@ func WithReceiptHistorySize(size int) Option {
 	}
 }
 
+// WithTickInterval sets the expected time between ticks. It is used to convert the Interval of systems into ticks, and
+// does not change how often ticks actually happen.
+func WithTickInterval(d time.Duration) Option {
+	if d <= 0 {
+		panic("WithTickInterval must be given a positive duration")
+	}
+	return func(w *World) {
+		w.tickInterval = d
+	}
+}
+
 func WithNamespace(ns string) Option {
 	return func(w *World) {
 		w.namespace = Namespace(ns)
@@ -45,6 +57,30 @@ func WithStoreManager(s store.IManager) Option {
 	}
 }
 
+// WithEventRetention sets how many ticks worth of emitted events are saved in the store. A retention of 0 keeps events
+// forever.
+func WithEventRetention(ticks uint64) Option {
+	return func(w *World) {
+		w.entityStore.SetEventRetention(ticks)
+	}
+}
+
+// WithProofRetention sets how many finalized ticks component proofs can be built for (see World.GetComponentProof). A
+// retention of 0 keeps every tick since the state was loaded.
+func WithProofRetention(ticks uint64) Option {
+	return func(w *World) {
+		w.entityStore.SetProofRetention(ticks)
+	}
+}
+
+// WithStateHashRetention sets how many ticks worth of state hashes are saved in the store (see World.StateHash). A
+// retention of 0 keeps the state hash of every tick.
+func WithStateHashRetention(ticks uint64) Option {
+	return func(w *World) {
+		w.entityStore.SetStateHashRetention(ticks)
+	}
+}
+
 func WithEventHub(eventHub events.EventHub) Option {
 	return func(w *World) {
 		w.eventHub = eventHub
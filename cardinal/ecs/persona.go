@@ -7,6 +7,7 @@ import (
 
 	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
 )
 
 // CreatePersonaTransaction allows for the associating of a persona tag with a signer address.
@@ -41,7 +42,15 @@ var AuthorizePersonaAddressTx = NewTransactionType[AuthorizePersonaAddress, Auth
 // users who want to interact with the game via smart contract can link their EVM address to their persona tag, enabling
 // them to mutate their owned state from the context of the EVM.
 func AuthorizePersonaAddressSystem(wCtx WorldContext) error {
-	personaTagToAddress, err := buildPersonaTagMapping(wCtx)
+	txs := AuthorizePersonaAddressTx.In(wCtx)
+	if len(txs) == 0 {
+		return nil
+	}
+	personaTags := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		personaTags = append(personaTags, tx.Sig.PersonaTag)
+	}
+	personaTagToAddress, err := buildPersonaTagMapping(wCtx, personaTags)
 	if err != nil {
 		return err
 	}
@@ -82,35 +91,44 @@ func (SignerComponent) Name() string {
 	return "SignerComponent"
 }
 
+// signerComponentIndexes lets signers be found by persona tag or authorized address without checking every signer.
+var signerComponentIndexes = []ComponentOption{
+	WithIndex("PersonaTag"),
+	WithIndex("AuthorizedAddresses"),
+}
+
 type personaTagComponentData struct {
 	SignerAddress string
 	EntityID      entity.ID
 }
 
-func buildPersonaTagMapping(wCtx WorldContext) (map[string]personaTagComponentData, error) {
+// buildPersonaTagMapping finds the signers of the given persona tags. Persona tags without a signer are left out of
+// the mapping.
+func buildPersonaTagMapping(wCtx WorldContext, personaTags []string) (map[string]personaTagComponentData, error) {
 	personaTagToAddress := map[string]personaTagComponentData{}
-	var errs []error
 	q, err := wCtx.NewSearch(Exact(SignerComponent{}))
 	if err != nil {
 		return nil, err
 	}
-	err = q.Each(wCtx, func(id entity.ID) bool {
+	for _, personaTag := range personaTags {
+		if _, ok := personaTagToAddress[personaTag]; ok {
+			continue
+		}
+		id, err := q.Where("PersonaTag", personaTag).First(wCtx)
+		if err != nil {
+			return nil, err
+		}
+		if id == storage.BadID {
+			continue
+		}
 		sc, err := getComponent[SignerComponent](wCtx, id)
 		if err != nil {
-			errs = append(errs, err)
-			return true
+			return nil, err
 		}
-		personaTagToAddress[sc.PersonaTag] = personaTagComponentData{
+		personaTagToAddress[personaTag] = personaTagComponentData{
 			SignerAddress: sc.SignerAddress,
 			EntityID:      id,
 		}
-		return true
-	})
-	if err != nil {
-		return nil, err
-	}
-	if len(errs) != 0 {
-		return nil, errors.Join(errs...)
 	}
 	return personaTagToAddress, nil
 }
@@ -122,7 +140,11 @@ func RegisterPersonaSystem(wCtx WorldContext) error {
 	if len(createTxs) == 0 {
 		return nil
 	}
-	personaTagToAddress, err := buildPersonaTagMapping(wCtx)
+	personaTags := make([]string, 0, len(createTxs))
+	for _, txData := range createTxs {
+		personaTags = append(personaTags, txData.Value.PersonaTag)
+	}
+	personaTagToAddress, err := buildPersonaTagMapping(wCtx, personaTags)
 	if err != nil {
 		return err
 	}
@@ -168,28 +190,30 @@ func (w *World) GetSignerForPersonaTag(personaTag string, tick uint64) (addr str
 	if tick >= w.tick {
 		return "", ErrCreatePersonaTxsNotProcessed
 	}
-	var errs []error
-	q, err := w.NewSearch(Exact(SignerComponent{}))
+	return signerForPersonaTag(NewReadOnlyWorldContext(w), personaTag)
+}
+
+// signerForPersonaTag returns the signer address of the given persona tag as seen by the given world context.
+func signerForPersonaTag(wCtx WorldContext, personaTag string) (string, error) {
+	q, err := wCtx.GetWorld().NewSearch(Exact(SignerComponent{}))
 	if err != nil {
 		return "", err
 	}
-	wCtx := NewReadOnlyWorldContext(w)
-	err = q.Each(wCtx, func(id entity.ID) bool {
-		sc, err := getComponent[SignerComponent](wCtx, id)
-		if err != nil {
-			errs = append(errs, err)
-		}
-		if sc.PersonaTag == personaTag {
-			addr = sc.SignerAddress
-			return false
-		}
-		return true
-	})
-	errs = append(errs, err)
-	if addr == "" {
+	id, err := q.Where("PersonaTag", personaTag).First(wCtx)
+	if err != nil {
+		return "", err
+	}
+	if id == storage.BadID {
+		return "", ErrPersonaTagHasNoSigner
+	}
+	sc, err := getComponent[SignerComponent](wCtx, id)
+	if err != nil {
+		return "", err
+	}
+	if sc.SignerAddress == "" {
 		return "", ErrPersonaTagHasNoSigner
 	}
-	return addr, errors.Join(errs...)
+	return sc.SignerAddress, nil
 }
 
 // TODO private component function used to temporarily remove circular dependency until we replace components.
@@ -281,7 +305,7 @@ func createMany(wCtx WorldContext, num int, components ...metadata.Component) ([
 		}
 		acc = append(acc, c)
 	}
-	entityIds, err := world.StoreManager().CreateManyEntities(num, acc...)
+	entityIds, err := wCtx.StoreManager().CreateManyEntities(num, acc...)
 	if err != nil {
 		return nil, err
 	}
@@ -291,7 +315,7 @@ func createMany(wCtx WorldContext, num int, components ...metadata.Component) ([
 			if err != nil {
 				return nil, errors.New("must register component before creating an entity")
 			}
-			err = world.StoreManager().SetComponentForEntity(c, id, comp)
+			err = wCtx.StoreManager().SetComponentForEntity(c, id, comp)
 			if err != nil {
 				return nil, err
 			}
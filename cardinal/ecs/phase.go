@@ -0,0 +1,197 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Phase is a named stage of a tick. Every system belongs to exactly one phase, and every system in a phase finishes
+// before any system in the next phase starts.
+type Phase string
+
+const (
+	PreUpdate  Phase = "PreUpdate"
+	Update     Phase = "Update"
+	PostUpdate Phase = "PostUpdate"
+)
+
+var (
+	ErrDuplicatePhase   = errors.New("phase names must be unique")
+	ErrUnknownPhase     = errors.New("phase has not been added")
+	ErrUnknownSystem    = errors.New("system has not been added")
+	ErrSystemOrderCycle = errors.New("system ordering constraints form a cycle")
+)
+
+func defaultPhases() []Phase {
+	return []Phase{PreUpdate, Update, PostUpdate}
+}
+
+// InPhase puts the system in the given phase. Systems are in the Update phase unless this option is used.
+func InPhase(phase Phase) SystemOption {
+	return func(sys *registeredSystem) {
+		sys.phase = phase
+	}
+}
+
+// Before makes the system run before the systems with the given names. System names are the ones returned by
+// GetSystemNames, e.g. "game.MoveSystem". The other systems may be in the same phase or in a later phase.
+func Before(systemNames ...string) SystemOption {
+	return func(sys *registeredSystem) {
+		sys.before = append(sys.before, systemNames...)
+	}
+}
+
+// After makes the system run after the systems with the given names. The other systems may be in the same phase or in
+// an earlier phase.
+func After(systemNames ...string) SystemOption {
+	return func(sys *registeredSystem) {
+		sys.after = append(sys.after, systemNames...)
+	}
+}
+
+// AddPhaseBefore adds a new phase that runs directly before an existing phase.
+func (w *World) AddPhaseBefore(phase, before Phase) error {
+	return w.insertPhase(phase, before, 0)
+}
+
+// AddPhaseAfter adds a new phase that runs directly after an existing phase.
+func (w *World) AddPhaseAfter(phase, after Phase) error {
+	return w.insertPhase(phase, after, 1)
+}
+
+// ListPhases returns every phase in the order they run in.
+func (w *World) ListPhases() []Phase {
+	return w.phases
+}
+
+func (w *World) insertPhase(phase, existing Phase, offset int) error {
+	if w.stateIsLoaded {
+		panic("cannot add phases after loading game state")
+	}
+	if slices.Contains(w.phases, phase) {
+		return fmt.Errorf("duplicate phase %q: %w", phase, ErrDuplicatePhase)
+	}
+	i := slices.Index(w.phases, existing)
+	if i < 0 {
+		return fmt.Errorf("cannot add phase %q next to %q: %w", phase, existing, ErrUnknownPhase)
+	}
+	w.phases = slices.Insert(w.phases, i+offset, phase)
+	return nil
+}
+
+// orderSystems sorts the systems by phase, and then by their Before and After constraints. Systems that are not
+// constrained keep the order they were added in, so the resolved order is the same every time the world starts. For
+// each system in the new order, the set of systems it was explicitly constrained to run after is returned.
+func (w *World) orderSystems() ([]map[int]bool, error) {
+	n := len(w.systems)
+	phaseOf := make([]int, n)
+	byName := map[string][]int{}
+	for i := range w.systems {
+		sys := &w.systems[i]
+		phaseOf[i] = slices.Index(w.phases, sys.phase)
+		if phaseOf[i] < 0 {
+			return nil, fmt.Errorf("system %q is in phase %q: %w", sys.name, sys.phase, ErrUnknownPhase)
+		}
+		byName[sys.name] = append(byName[sys.name], i)
+	}
+
+	// runsBefore[i] are the systems that must run after system i.
+	runsBefore := make([][]int, n)
+	waitingFor := make([]int, n)
+	addConstraint := func(first, second int) error {
+		if phaseOf[first] > phaseOf[second] {
+			return fmt.Errorf("system %q cannot run before %q because it is in a later phase",
+				w.systems[first].name, w.systems[second].name)
+		}
+		runsBefore[first] = append(runsBefore[first], second)
+		waitingFor[second]++
+		return nil
+	}
+	for i := range w.systems {
+		sys := &w.systems[i]
+		for _, name := range sys.before {
+			others, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("system %q must run before %q: %w", sys.name, name, ErrUnknownSystem)
+			}
+			for _, other := range others {
+				if err := addConstraint(i, other); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, name := range sys.after {
+			others, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("system %q must run after %q: %w", sys.name, name, ErrUnknownSystem)
+			}
+			for _, other := range others {
+				if err := addConstraint(other, i); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// Repeatedly take the system that comes first by phase and then by registration order, out of all the systems
+	// that are no longer waiting for another system.
+	comesFirst := func(a, b int) bool {
+		if phaseOf[a] != phaseOf[b] {
+			return phaseOf[a] < phaseOf[b]
+		}
+		return a < b
+	}
+	var ready []int
+	for i := range w.systems {
+		if waitingFor[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	order := make([]int, 0, n)
+	for len(ready) > 0 {
+		next := 0
+		for k := range ready {
+			if comesFirst(ready[k], ready[next]) {
+				next = k
+			}
+		}
+		curr := ready[next]
+		ready = slices.Delete(ready, next, next+1)
+		order = append(order, curr)
+		for _, other := range runsBefore[curr] {
+			waitingFor[other]--
+			if waitingFor[other] == 0 {
+				ready = append(ready, other)
+			}
+		}
+	}
+	if len(order) < n {
+		var names []string
+		for i := range w.systems {
+			if waitingFor[i] > 0 {
+				names = append(names, w.systems[i].name)
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", ErrSystemOrderCycle, strings.Join(names, ", "))
+	}
+
+	newIndex := make([]int, n)
+	for i, old := range order {
+		newIndex[old] = i
+	}
+	sorted := make([]registeredSystem, 0, n)
+	constrainedAfter := make([]map[int]bool, n)
+	for i, old := range order {
+		sorted = append(sorted, w.systems[old])
+		constrainedAfter[i] = map[int]bool{}
+	}
+	for first, seconds := range runsBefore {
+		for _, second := range seconds {
+			constrainedAfter[newIndex[second]][newIndex[first]] = true
+		}
+	}
+	w.systems = sorted
+	return constrainedAfter, nil
+}
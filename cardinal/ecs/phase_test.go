@@ -0,0 +1,124 @@
+package ecs_test
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+)
+
+// addRecordingSystem adds a system with the given name that appends its name to order when it runs.
+func addRecordingSystem(w *ecs.World, order *[]string, name string, opts ...ecs.SystemOption) {
+	w.AddSystemWithOptions(func(ecs.WorldContext) error {
+		*order = append(*order, name)
+		return nil
+	}, name, opts...)
+}
+
+func TestSystemsRunInPhaseOrder(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	var order []string
+	addRecordingSystem(w, &order, "post", ecs.InPhase(ecs.PostUpdate))
+	addRecordingSystem(w, &order, "update")
+	addRecordingSystem(w, &order, "pre", ecs.InPhase(ecs.PreUpdate))
+	assert.NilError(t, w.AddPhaseAfter("Physics", ecs.PreUpdate))
+	addRecordingSystem(w, &order, "physics", ecs.InPhase("Physics"))
+	assert.NilError(t, w.LoadGameState())
+
+	assert.NilError(t, w.Tick(context.Background()))
+	assert.DeepEqual(t, []string{"pre", "physics", "update", "post"}, order)
+	// The persona systems always run first.
+	assert.DeepEqual(t, []string{
+		"ecs.RegisterPersonaSystem", "ecs.AuthorizePersonaAddressSystem", "pre", "physics", "update", "post",
+	}, w.GetSystemNames())
+}
+
+func TestBeforeAndAfterConstraints(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	var order []string
+	addRecordingSystem(w, &order, "a", ecs.After("c"))
+	addRecordingSystem(w, &order, "b")
+	addRecordingSystem(w, &order, "c")
+	addRecordingSystem(w, &order, "d", ecs.Before("b"))
+	addRecordingSystem(w, &order, "e", ecs.InPhase(ecs.PreUpdate), ecs.Before("b"))
+	assert.NilError(t, w.LoadGameState())
+
+	assert.NilError(t, w.Tick(context.Background()))
+	// Within a phase, each system runs as soon as its constraints allow, with ties going to the earlier registration.
+	assert.DeepEqual(t, []string{"e", "c", "a", "d", "b"}, order)
+}
+
+func TestOrderingCyclesAreRejected(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	var order []string
+	addRecordingSystem(w, &order, "a", ecs.Before("b"))
+	addRecordingSystem(w, &order, "b", ecs.Before("c"))
+	addRecordingSystem(w, &order, "c", ecs.Before("a"))
+	addRecordingSystem(w, &order, "d")
+	err := w.LoadGameState()
+	assert.ErrorIs(t, err, ecs.ErrSystemOrderCycle)
+	assert.ErrorContains(t, err, "a, b, c")
+}
+
+func TestInvalidOrderingIsRejected(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options []ecs.SystemOption
+		wantErr error
+	}{
+		{
+			name:    "unknown phase",
+			options: []ecs.SystemOption{ecs.InPhase("Render")},
+			wantErr: ecs.ErrUnknownPhase,
+		},
+		{
+			name:    "unknown system",
+			options: []ecs.SystemOption{ecs.After("missing")},
+			wantErr: ecs.ErrUnknownSystem,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := ecs.NewTestWorld(t)
+			var order []string
+			addRecordingSystem(w, &order, "sys", tc.options...)
+			assert.ErrorIs(t, w.LoadGameState(), tc.wantErr)
+		})
+	}
+
+	// A system can't run before a system in an earlier phase.
+	w := ecs.NewTestWorld(t)
+	var order []string
+	addRecordingSystem(w, &order, "pre", ecs.InPhase(ecs.PreUpdate))
+	addRecordingSystem(w, &order, "update", ecs.Before("pre"))
+	assert.ErrorContains(t, w.LoadGameState(), "later phase")
+}
+
+func TestPhaseNamesMustBeUnique(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.ErrorIs(t, w.AddPhaseBefore(ecs.Update, ecs.PreUpdate), ecs.ErrDuplicatePhase)
+	assert.ErrorIs(t, w.AddPhaseBefore("Render", "Missing"), ecs.ErrUnknownPhase)
+	assert.NilError(t, w.AddPhaseBefore("Input", ecs.PreUpdate))
+	assert.DeepEqual(t, []ecs.Phase{"Input", ecs.PreUpdate, ecs.Update, ecs.PostUpdate}, w.ListPhases())
+}
+
+func TestParallelSystemsRespectPhasesAndConstraints(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	assert.NilError(t, ecs.RegisterComponent[Armor](w))
+
+	// None of these systems conflict, so only the phases and constraints keep them in order.
+	var order []string
+	addRecordingSystem(w, &order, "post", ecs.InPhase(ecs.PostUpdate), ecs.Writes(Armor{}))
+	addRecordingSystem(w, &order, "second", ecs.Reads(Speed{}))
+	addRecordingSystem(w, &order, "first", ecs.Reads(Speed{}), ecs.Before("second"))
+	assert.NilError(t, w.LoadGameState())
+
+	for i := 0; i < 10; i++ {
+		order = nil
+		assert.NilError(t, w.Tick(context.Background()))
+		assert.DeepEqual(t, []string{"first", "second", "post"}, order)
+	}
+}
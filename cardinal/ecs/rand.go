@@ -0,0 +1,48 @@
+package ecs
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
+)
+
+// randSeed is the seed that every random number source in a tick is derived from.
+type randSeed [sha256.Size]byte
+
+// newTickSeed hashes the namespace, the tick number and the hashes of every transaction in the tick. The transaction
+// hashes are sorted first, so the seed does not depend on the order the transactions arrived in. Replaying the same
+// transactions in the same tick always produces the same seed.
+func (w *World) newTickSeed(tick uint64, queue *transaction.TxQueue) randSeed {
+	var txHashes []string
+	if queue != nil {
+		for _, tx := range w.registeredTransactions {
+			for _, txData := range queue.ForID(tx.ID()) {
+				txHashes = append(txHashes, string(txData.TxHash))
+			}
+		}
+	}
+	sort.Strings(txHashes)
+	txsHash := sha256.New()
+	for _, txHash := range txHashes {
+		txsHash.Write([]byte(txHash))
+	}
+
+	seed := sha256.New()
+	seed.Write([]byte(w.Namespace()))
+	seed.Write(binary.BigEndian.AppendUint64(nil, tick))
+	seed.Write(txsHash.Sum(nil))
+	return randSeed(seed.Sum(nil))
+}
+
+// newRand returns a random number source for the named stream. Each stream only depends on the seed and its own name,
+// so adding a system does not change the random numbers any other system sees.
+func (s randSeed) newRand(streamName string) *rand.Rand {
+	stream := sha256.New()
+	stream.Write(s[:])
+	stream.Write([]byte(streamName))
+	//nolint:gosec // the source must be deterministic, so it must not be cryptographically secure.
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(stream.Sum(nil)))))
+}
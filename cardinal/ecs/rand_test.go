@@ -0,0 +1,102 @@
+package ecs_test
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type RollMsg struct {
+	Sides int
+}
+
+type RollResult struct{}
+
+// rollDice runs 3 ticks in a fresh world and returns the numbers a system called "dice" got from Rand in each tick.
+// A roll transaction signed with the given nonce is queued before the second tick.
+func rollDice(t *testing.T, namespace string, nonce uint64, extraSystems ...string) []int64 {
+	w := ecs.NewTestWorld(t, ecs.WithNamespace(namespace))
+	rollTx := ecs.NewTransactionType[RollMsg, RollResult]("roll")
+	assert.NilError(t, w.RegisterTransactions(rollTx))
+	for _, name := range extraSystems {
+		w.AddSystemWithName(func(wCtx ecs.WorldContext) error {
+			wCtx.Rand().Int63()
+			return nil
+		}, name)
+	}
+	var rolls []int64
+	w.AddSystemWithName(func(wCtx ecs.WorldContext) error {
+		rolls = append(rolls, wCtx.Rand().Int63())
+		return nil
+	}, "dice")
+	assert.NilError(t, w.LoadGameState())
+
+	assert.NilError(t, w.Tick(context.Background()))
+	rollTx.AddToQueue(w, RollMsg{Sides: 6}, &sign.Transaction{PersonaTag: "player", Nonce: nonce, Body: []byte("{}")})
+	assert.NilError(t, w.Tick(context.Background()))
+	assert.NilError(t, w.Tick(context.Background()))
+	return rolls
+}
+
+func TestRandIsDeterministic(t *testing.T) {
+	rolls := rollDice(t, "world", 1)
+	assert.DeepEqual(t, rolls, rollDice(t, "world", 1))
+
+	// Each tick gets different numbers.
+	assert.Check(t, rolls[0] != rolls[1])
+	assert.Check(t, rolls[1] != rolls[2])
+
+	// Adding another system does not change the numbers the dice system gets.
+	assert.DeepEqual(t, rolls, rollDice(t, "world", 1, "other"))
+}
+
+func TestRandDependsOnNamespaceAndTransactions(t *testing.T) {
+	rolls := rollDice(t, "world", 1)
+
+	otherNamespace := rollDice(t, "other-world", 1)
+	assert.Check(t, rolls[0] != otherNamespace[0])
+
+	// Only the tick with a different transaction gets different numbers.
+	otherTx := rollDice(t, "world", 2)
+	assert.Equal(t, rolls[0], otherTx[0])
+	assert.Check(t, rolls[1] != otherTx[1])
+	assert.Equal(t, rolls[2], otherTx[2])
+}
+
+func TestRandReturnsTheSameSourceWithinASystem(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	var first, second []int64
+	w.AddSystemWithName(func(wCtx ecs.WorldContext) error {
+		first = append(first, wCtx.Rand().Int63(), wCtx.Rand().Int63())
+		return nil
+	}, "first")
+	w.AddSystemWithName(func(wCtx ecs.WorldContext) error {
+		second = append(second, wCtx.Rand().Int63(), wCtx.Rand().Int63())
+		return nil
+	}, "second")
+	assert.NilError(t, w.LoadGameState())
+	assert.NilError(t, w.Tick(context.Background()))
+
+	assert.Check(t, first[0] != first[1])
+	assert.Check(t, first[0] != second[0])
+}
+
+func TestRandDiffersBetweenSystemsWithTheSameName(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	var rolls []int64
+	for i := 0; i < 2; i++ {
+		w.AddSystemWithName(func(wCtx ecs.WorldContext) error {
+			rolls = append(rolls, wCtx.Rand().Int63())
+			return nil
+		}, "dice")
+	}
+	assert.NilError(t, w.LoadGameState())
+	assert.NilError(t, w.Tick(context.Background()))
+
+	assert.Equal(t, 2, len(rolls))
+	assert.Check(t, rolls[0] != rolls[1])
+}
@@ -5,6 +5,7 @@ package receipt
 
 import (
 	"errors"
+	"sync"
 	"sync/atomic"
 
 	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
@@ -20,6 +21,8 @@ var (
 type History struct {
 	currTick     *atomic.Uint64
 	ticksToStore uint64
+	// mu guards the maps in history. Systems that run in parallel may add errors and results at the same time.
+	mu sync.Mutex
 	// Receipts for a given tick are assigned to an index into this history slice which acts as a ring buffer.
 	history []map[transaction.TxHash]Receipt
 }
@@ -55,6 +58,8 @@ func (h *History) Size() uint64 {
 // NextTick advances the internal History tick by 1. Errors and results can only be set on the current tick. Receipts
 // from ticks in the past are read only.
 func (h *History) NextTick() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	newCurr := h.currTick.Add(1)
 	mod := newCurr % h.ticksToStore
 	h.history[mod] = map[transaction.TxHash]Receipt{}
@@ -67,6 +72,8 @@ func (h *History) SetTick(tick uint64) {
 // AddError associates the given error with the given transaction hash. Calling this multiple times will append
 // the error any previously added errors.
 func (h *History) AddError(hash transaction.TxHash, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	tick := int(h.currTick.Load() % h.ticksToStore)
 	rec := h.history[tick][hash]
 	rec.TxHash = hash
@@ -77,6 +84,8 @@ func (h *History) AddError(hash transaction.TxHash, err error) {
 // SetResult sets the given transaction hash to the given result. Calling this multiple times will replace any previous
 // results.
 func (h *History) SetResult(hash transaction.TxHash, result any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	tick := int(h.currTick.Load() % h.ticksToStore)
 	rec := h.history[tick][hash]
 	rec.TxHash = hash
@@ -87,6 +96,8 @@ func (h *History) SetResult(hash transaction.TxHash, result any) {
 // GetReceipt gets the receipt (the transaction result and the list of errors) for the given transaction hash in the
 // current tick. To get receipts from previous ticks use GetReceiptsForTick.
 func (h *History) GetReceipt(hash transaction.TxHash) (Receipt, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	tick := int(h.currTick.Load() % h.ticksToStore)
 	rec, ok := h.history[tick][hash]
 	return rec, ok
@@ -104,6 +115,8 @@ func (h *History) GetReceiptsForTick(tick uint64) ([]Receipt, error) {
 	if currTick-tick >= h.ticksToStore {
 		return nil, ErrOldTickHasBeenDiscarded
 	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	mod := tick % h.ticksToStore
 	recs := make([]Receipt, 0, len(h.history[mod]))
 	for _, rec := range h.history[mod] {
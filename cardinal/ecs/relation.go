@@ -0,0 +1,230 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+var (
+	ErrEntityIsReferenced = errors.New("entity is referred to by another entity")
+	ErrRelationCycle      = errors.New("relation would make an entity its own ancestor")
+)
+
+// ChildOf is a relation that makes an entity a child of another entity, e.g. an item in an inventory or a member of
+// a squad. Like any other component, it must be registered before it is used. Entities can be created with a parent
+// by passing ChildOf{Parent: &parent} to component.Create, and the children of an entity can be found with GetChildren.
+type ChildOf struct {
+	// Parent is the parent entity. It is nil if the entity does not have a parent, which is the case when ChildOf is
+	// added to an entity without a value.
+	Parent *entity.ID
+}
+
+func (ChildOf) Name() string {
+	return "ChildOf"
+}
+
+// Target returns the parent entity. false is returned if there is no parent.
+func (c ChildOf) Target() (entity.ID, bool) {
+	if c.Parent == nil {
+		return 0, false
+	}
+	return *c.Parent, true
+}
+
+// RemovePolicy decides what happens to the entities that refer to an entity through a relation when the entity is
+// removed.
+type RemovePolicy int
+
+const (
+	// RemoveRestrict refuses to remove an entity that other entities refer to.
+	RemoveRestrict RemovePolicy = iota
+	// RemoveCascade also removes every entity that refers to the removed entity, every entity that refers to those
+	// entities, and so on.
+	RemoveCascade
+	// RemoveDetach removes the relation components that refer to the removed entity from the entities that refer to
+	// it, so those entities are kept.
+	RemoveDetach
+)
+
+// GetReferrers returns the entities that refer to the target entity through the relation component T, sorted by ID.
+// Relations are indexed, so this does not search every entity.
+func GetReferrers[T metadata.Relation](wCtx WorldContext, target entity.ID) ([]entity.ID, error) {
+	var t T
+	c, err := wCtx.GetWorld().GetComponentByName(t.Name())
+	if err != nil {
+		return nil, err
+	}
+	return wCtx.StoreReader().GetRelationSources(c, target)
+}
+
+// GetChildren returns the entities that are a ChildOf the given parent, sorted by ID.
+func GetChildren(wCtx WorldContext, parent entity.ID) ([]entity.ID, error) {
+	return GetReferrers[ChildOf](wCtx, parent)
+}
+
+// GetParent returns the parent of the given entity. false is returned if the entity does not have a parent.
+func GetParent(wCtx WorldContext, child entity.ID) (entity.ID, bool, error) {
+	c, err := getComponent[ChildOf](wCtx, child)
+	if errors.Is(err, storage.ErrComponentNotOnEntity) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	parent, ok := c.Target()
+	return parent, ok, nil
+}
+
+// SetParent makes child a ChildOf parent, replacing any parent it already had. An error is returned if the parent
+// does not exist, or if the parent is a descendant of the child.
+func SetParent(wCtx WorldContext, child, parent entity.ID) error {
+	if wCtx.IsReadOnly() {
+		return ErrCannotModifyStateWithReadOnlyContext
+	}
+	c, err := wCtx.GetWorld().GetComponentByName(ChildOf{}.Name())
+	if err != nil {
+		return err
+	}
+	if _, err = wCtx.StoreReader().GetComponentTypesForEntity(parent); err != nil {
+		return fmt.Errorf("%w: entity %d: %w", storage.ErrRelationTargetNotFound, parent, err)
+	}
+	ancestor, hasParent := parent, true
+	for hasParent {
+		if ancestor == child {
+			return fmt.Errorf("%w: entity %d cannot be a child of entity %d", ErrRelationCycle, child, parent)
+		}
+		if ancestor, hasParent, err = GetParent(wCtx, ancestor); err != nil {
+			return err
+		}
+	}
+	err = wCtx.StoreManager().AddComponentToEntity(c, child)
+	if err != nil && !errors.Is(err, storage.ErrComponentAlreadyOnEntity) {
+		return err
+	}
+	return setComponent[ChildOf](wCtx, child, &ChildOf{Parent: &parent})
+}
+
+// RemoveParent removes the parent of the given entity, so it is no longer a ChildOf any entity.
+func RemoveParent(wCtx WorldContext, child entity.ID) error {
+	if wCtx.IsReadOnly() {
+		return ErrCannotModifyStateWithReadOnlyContext
+	}
+	c, err := wCtx.GetWorld().GetComponentByName(ChildOf{}.Name())
+	if err != nil {
+		return err
+	}
+	return wCtx.StoreManager().RemoveComponentFromEntity(c, child)
+}
+
+// RemoveEntity removes the given entity, and handles the entities that refer to it through a relation according to
+// the given policy.
+func RemoveEntity(wCtx WorldContext, id entity.ID, policy RemovePolicy) error {
+	if wCtx.IsReadOnly() {
+		return ErrCannotModifyStateWithReadOnlyContext
+	}
+	return wCtx.GetWorld().removeEntity(wCtx.StoreManager(), id, policy)
+}
+
+// removeEntity removes the given entity according to the given policy. Every entity that has to be detached or removed
+// is found and checked before anything is changed, so an error does not leave the removal partially done.
+func (w *World) removeEntity(sm store.IManager, id entity.ID, policy RemovePolicy) error {
+	if _, err := sm.GetComponentTypesForEntity(id); err != nil {
+		return err
+	}
+	relations := w.registeredRelations
+	var toDetach []relationSource
+	var toRemove []entity.ID
+	switch policy {
+	case RemoveRestrict:
+		sources, err := getRelationSources(sm, relations, id)
+		if err != nil {
+			return err
+		}
+		if len(sources) > 0 {
+			return fmt.Errorf("%w: entity %d refers to entity %d through %s", ErrEntityIsReferenced,
+				sources[0].source, id, sources[0].relation.Name())
+		}
+	case RemoveDetach:
+		var err error
+		if toDetach, err = getRelationSources(sm, relations, id); err != nil {
+			return err
+		}
+		// An entity must keep at least one component, so make sure every entity keeps a component after all of its
+		// relations to the removed entity are detached.
+		detached := map[entity.ID]int{}
+		for _, s := range toDetach {
+			detached[s.source]++
+		}
+		for _, s := range toDetach {
+			comps, err := sm.GetComponentTypesForEntity(s.source)
+			if err != nil {
+				return err
+			}
+			if len(comps) <= detached[s.source] {
+				return fmt.Errorf("failed to detach entity %d from entity %d: %w", s.source, id,
+					storage.ErrEntityMustHaveAtLeastOneComponent)
+			}
+		}
+	case RemoveCascade:
+		// Every entity that depends on the removed entity is found before anything is removed, so entities that refer
+		// to each other are handled.
+		toRemove = []entity.ID{id}
+		found := map[entity.ID]bool{id: true}
+		for i := 0; i < len(toRemove); i++ {
+			sources, err := getRelationSources(sm, relations, toRemove[i])
+			if err != nil {
+				return err
+			}
+			for _, s := range sources {
+				if !found[s.source] {
+					found[s.source] = true
+					toRemove = append(toRemove, s.source)
+				}
+			}
+		}
+		toRemove = toRemove[1:]
+	default:
+		return fmt.Errorf("unknown remove policy %d", policy)
+	}
+
+	for _, s := range toDetach {
+		if err := sm.RemoveComponentFromEntity(s.relation, s.source); err != nil {
+			return fmt.Errorf("failed to detach entity %d from entity %d: %w", s.source, id, err)
+		}
+	}
+	for _, current := range toRemove {
+		if err := sm.RemoveEntity(current); err != nil {
+			return err
+		}
+	}
+	return sm.RemoveEntity(id)
+}
+
+// relationSource is an entity that refers to another entity through a relation.
+type relationSource struct {
+	relation metadata.ComponentMetadata
+	source   entity.ID
+}
+
+// getRelationSources returns the entities other than the target that refer to the target through any of the given
+// relations.
+func getRelationSources(sm store.IManager, relations []metadata.ComponentMetadata, target entity.ID) (
+	[]relationSource, error) {
+	var result []relationSource
+	for _, relation := range relations {
+		sources, err := sm.GetRelationSources(relation, target)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range sources {
+			if source != target {
+				result = append(result, relationSource{relation: relation, source: source})
+			}
+		}
+	}
+	return result, nil
+}
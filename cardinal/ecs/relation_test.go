@@ -0,0 +1,152 @@
+package ecs_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+)
+
+type Item struct {
+	Weight int
+}
+
+func (Item) Name() string {
+	return "item"
+}
+
+// newInventory returns a world with an inventory entity that holds a bag, which in turn holds a coin.
+func newInventory(t *testing.T) (wCtx ecs.WorldContext, inventory, bag, coin entity.ID) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Item](w))
+	assert.NilError(t, ecs.RegisterComponent[ecs.ChildOf](w))
+	assert.NilError(t, w.LoadGameState())
+	wCtx = ecs.NewWorldContext(w)
+
+	inventory, err := component.Create(wCtx, Item{})
+	assert.NilError(t, err)
+	bag, err = component.Create(wCtx, Item{Weight: 1}, ecs.ChildOf{Parent: &inventory})
+	assert.NilError(t, err)
+	coin, err = component.Create(wCtx, Item{Weight: 2})
+	assert.NilError(t, err)
+	assert.NilError(t, ecs.SetParent(wCtx, coin, bag))
+	return wCtx, inventory, bag, coin
+}
+
+func TestChildrenAndParentsCanBeFound(t *testing.T) {
+	wCtx, inventory, bag, coin := newInventory(t)
+	children, err := ecs.GetChildren(wCtx, inventory)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []entity.ID{bag}, children)
+
+	parent, ok, err := ecs.GetParent(wCtx, coin)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, bag, parent)
+	_, ok, err = ecs.GetParent(wCtx, inventory)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+
+	// Move the coin into the inventory.
+	assert.NilError(t, ecs.SetParent(wCtx, coin, inventory))
+	children, err = ecs.GetChildren(wCtx, inventory)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []entity.ID{bag, coin}, children)
+	children, err = ecs.GetChildren(wCtx, bag)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(children))
+
+	assert.NilError(t, ecs.RemoveParent(wCtx, coin))
+	children, err = ecs.GetChildren(wCtx, inventory)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []entity.ID{bag}, children)
+}
+
+func TestParentsMustExistAndCannotBeDescendants(t *testing.T) {
+	wCtx, inventory, _, coin := newInventory(t)
+	assert.ErrorIs(t, ecs.SetParent(wCtx, inventory, coin), ecs.ErrRelationCycle)
+	assert.ErrorIs(t, ecs.SetParent(wCtx, coin, coin), ecs.ErrRelationCycle)
+	assert.ErrorIs(t, ecs.SetParent(wCtx, coin, 100), storage.ErrRelationTargetNotFound)
+	missing := entity.ID(100)
+	_, err := component.Create(wCtx, Item{}, ecs.ChildOf{Parent: &missing})
+	assert.ErrorIs(t, err, storage.ErrRelationTargetNotFound)
+}
+
+func TestRemovePolicies(t *testing.T) {
+	wCtx, inventory, bag, coin := newInventory(t)
+	assert.ErrorIs(t, ecs.RemoveEntity(wCtx, inventory, ecs.RemoveRestrict), ecs.ErrEntityIsReferenced)
+	assert.ErrorIs(t, wCtx.GetWorld().Remove(bag), ecs.ErrEntityIsReferenced)
+
+	// Cascading removes the bag and the coin in it.
+	assert.NilError(t, ecs.RemoveEntity(wCtx, inventory, ecs.RemoveCascade))
+	for _, id := range []entity.ID{inventory, bag, coin} {
+		_, err := component.GetComponent[Item](wCtx, id)
+		assert.Check(t, err != nil)
+	}
+
+	// Detaching keeps the bag, without a parent.
+	wCtx, inventory, bag, coin = newInventory(t)
+	assert.NilError(t, ecs.RemoveEntity(wCtx, inventory, ecs.RemoveDetach))
+	_, err := component.GetComponent[Item](wCtx, inventory)
+	assert.Check(t, err != nil)
+	_, ok, err := ecs.GetParent(wCtx, bag)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+	parent, _, err := ecs.GetParent(wCtx, coin)
+	assert.NilError(t, err)
+	assert.Equal(t, bag, parent)
+
+	// Entities without references can be removed with any policy.
+	assert.NilError(t, ecs.RemoveEntity(wCtx, coin, ecs.RemoveRestrict))
+}
+
+func TestRelationsWithoutAValueDoNotReferToEntityZero(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Item](w))
+	assert.NilError(t, ecs.RegisterComponent[ecs.ChildOf](w))
+	assert.NilError(t, w.LoadGameState())
+	wCtx := ecs.NewWorldContext(w)
+
+	first, err := component.Create(wCtx, Item{})
+	assert.NilError(t, err)
+	assert.Equal(t, entity.ID(0), first)
+	orphan, err := component.Create(wCtx, Item{}, ecs.ChildOf{})
+	assert.NilError(t, err)
+	added, err := component.Create(wCtx, Item{})
+	assert.NilError(t, err)
+	assert.NilError(t, component.AddComponentTo[ecs.ChildOf](wCtx, added))
+
+	children, err := ecs.GetChildren(wCtx, first)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(children))
+	_, ok, err := ecs.GetParent(wCtx, orphan)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+
+	// Removing entity 0 does not affect the entities without a parent.
+	assert.NilError(t, ecs.RemoveEntity(wCtx, first, ecs.RemoveCascade))
+	for _, id := range []entity.ID{orphan, added} {
+		_, err = component.GetComponent[Item](wCtx, id)
+		assert.NilError(t, err)
+	}
+}
+
+func TestFailedRemovalsDoNotChangeAnything(t *testing.T) {
+	wCtx, inventory, bag, _ := newInventory(t)
+	// This entity cannot be detached, since it would be left without any components.
+	_, err := component.Create(wCtx, ecs.ChildOf{Parent: &inventory})
+	assert.NilError(t, err)
+
+	err = ecs.RemoveEntity(wCtx, inventory, ecs.RemoveDetach)
+	assert.ErrorIs(t, err, storage.ErrEntityMustHaveAtLeastOneComponent)
+	_, err = component.GetComponent[Item](wCtx, inventory)
+	assert.NilError(t, err)
+	parent, ok, err := ecs.GetParent(wCtx, bag)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, inventory, parent)
+}
@@ -0,0 +1,207 @@
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
+	"pkg.world.dev/world-engine/cardinal/shard"
+	"pkg.world.dev/world-engine/chain/x/shard/types"
+)
+
+// WithTransactionLog makes the world write the transactions and timestamp of every tick it runs to the given writer,
+// as one JSON encoded epoch per line. Unlike the base shard, the log also contains ticks without transactions. The
+// log can be read with ReadTransactionLog and checked with VerifyReplay. Ticks that are recovered from storage or
+// from the base shard are not written again.
+func WithTransactionLog(writer io.Writer) Option {
+	return func(w *World) {
+		w.txLog = json.NewEncoder(writer)
+	}
+}
+
+// ReadTransactionLog reads every epoch that was written by a world created with WithTransactionLog.
+func ReadTransactionLog(reader io.Reader) ([]*types.Epoch, error) {
+	var epochs []*types.Epoch
+	decoder := json.NewDecoder(reader)
+	for {
+		epoch := new(types.Epoch)
+		err := decoder.Decode(epoch)
+		if errors.Is(err, io.EOF) {
+			return epochs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read transaction log: %w", err)
+		}
+		epochs = append(epochs, epoch)
+	}
+}
+
+// QueryEpochs fetches every epoch the base shard has stored for the given namespace.
+func QueryEpochs(ctx context.Context, chain shard.QueryAdapter, namespace string) ([]*types.Epoch, error) {
+	var epochs []*types.Epoch
+	err := queryEpochs(ctx, chain, namespace, 0, func(epoch *types.Epoch) error {
+		epochs = append(epochs, epoch)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return epochs, nil
+}
+
+// logTick writes the tick's transactions to the transaction log. The transaction bodies are re-encoded from the
+// queued values, so the log can be replayed even if a transaction was queued without a signed body.
+func (w *World) logTick(queue *transaction.TxQueue) {
+	prevStateHash, err := w.prevStateHash()
+	if err != nil {
+		w.Logger.Error().Err(err).Msgf("failed to get the state hash of tick %d for the transaction log", w.tick-1)
+	}
+	epoch := &types.Epoch{Epoch: w.tick, Timestamp: queue.Timestamp(), PrevStateHash: prevStateHash}
+	for _, tx := range w.registeredTransactions {
+		for _, txData := range queue.ForID(tx.ID()) {
+			body, err := tx.Encode(txData.Value)
+			if err != nil {
+				w.Logger.Error().Err(err).Msgf("failed to encode transaction %s for the transaction log", txData.TxHash)
+				return
+			}
+			sp := w.goTransactionToProto(txData.Sig)
+			sp.Body = body
+			bz, err := proto.Marshal(sp)
+			if err != nil {
+				w.Logger.Error().Err(err).Msgf("failed to encode transaction %s for the transaction log", txData.TxHash)
+				return
+			}
+			epoch.Txs = append(epoch.Txs, &types.Transaction{TxId: uint64(tx.ID()), GameShardTransaction: bz})
+		}
+	}
+	if err := w.txLog.Encode(epoch); err != nil {
+		w.Logger.Error().Err(err).Msgf("failed to write tick %d to the transaction log", w.tick)
+	}
+}
+
+// Divergence is the first point at which two replays of the same transaction history ended up with different state,
+// or at which the replays ended up with a different state than the one recorded in the history.
+type Divergence struct {
+	// Tick is the tick in which the state first differed.
+	Tick uint64
+	// System is the name of the first system after which the state differed. It is empty if Recorded is true.
+	System string
+	// Recorded is true if both replays had the same state, but it differed from the state hash recorded in the
+	// history. The history only records the state hash at the end of a tick, so the system is unknown.
+	Recorded bool
+}
+
+func (d *Divergence) String() string {
+	if d.Recorded {
+		return fmt.Sprintf("state diverged from the recorded state hash at the end of tick %d", d.Tick)
+	}
+	return fmt.Sprintf("state diverged in tick %d after system %q", d.Tick, d.System)
+}
+
+// systemHash is the state hash of a world right after a system ran.
+type systemHash struct {
+	tick   uint64
+	system string
+	hash   []byte
+}
+
+// VerifyReplay replays the given history into two fresh worlds, and compares the state of the worlds after every
+// system of every tick. Systems that depend on anything other than the world's state, the tick's transactions,
+// WorldContext.Rand and WorldContext.Timestamp (e.g. map iteration order or the wall clock) will make the worlds
+// diverge, which means RecoverFromChain would not reproduce the original state either. Only component state is
+// compared: systems that emit different events or set different transaction results or errors, but make the same
+// state changes, are not reported as a divergence.
+//
+// Epochs that have a PrevStateHash also record the state the original world had at the end of the previous tick. The
+// replays are checked against it, so a history that was produced by a world that behaved differently (e.g. a different
+// version of the game, or a world whose state was changed outside of its systems) is reported as a Divergence with
+// Recorded set, even if the two replays agree with each other.
+//
+// newWorld is called twice, and must return a world with empty storage that has all of its components,
+// transactions and systems registered, but whose state has not been loaded yet. Systems always run one at a time
+// during verification. The history can be read from the base shard with QueryEpochs, or from a local transaction log
+// with ReadTransactionLog. A nil Divergence is returned if the worlds never diverged.
+func VerifyReplay(ctx context.Context, newWorld func() (*World, error), history []*types.Epoch) (*Divergence, error) {
+	var worlds [2]*World
+	var hashes [2][]systemHash
+	for i := range worlds {
+		w, err := newWorld()
+		if err != nil {
+			return nil, err
+		}
+		if err = w.LoadGameState(); err != nil {
+			return nil, err
+		}
+		if w.CurrentTick() != 0 || w.StoreManager().ArchetypeCount() > 0 {
+			return nil, errors.New("replays can only be verified with worlds that have no existing state")
+		}
+		i := i
+		w.afterSystem = func(systemName string) error {
+			hash, err := w.stateHash()
+			if err != nil {
+				return err
+			}
+			hashes[i] = append(hashes[i], systemHash{tick: w.CurrentTick(), system: systemName, hash: hash})
+			return nil
+		}
+		worlds[i] = w
+	}
+
+	for _, epoch := range history {
+		for i, w := range worlds {
+			hashes[i] = hashes[i][:0]
+			if err := w.replayEpoch(ctx, epoch); err != nil {
+				return nil, fmt.Errorf("failed to replay tick %d: %w", epoch.Epoch, err)
+			}
+		}
+		if len(hashes[0]) != len(hashes[1]) {
+			return nil, fmt.Errorf("the worlds ran a different number of systems up to tick %d", epoch.Epoch)
+		}
+		var divergence *Divergence
+		for j, want := range hashes[0] {
+			got := hashes[1][j]
+			if want.system != got.system {
+				return nil, fmt.Errorf("the worlds ran systems %q and %q in tick %d", want.system, got.system, want.tick)
+			}
+			if !bytes.Equal(want.hash, got.hash) {
+				divergence = &Divergence{Tick: want.tick, System: want.system}
+				break
+			}
+		}
+		// The recorded state hash is from the end of the tick before the epoch, so only a divergence between the
+		// replays in an earlier tick happened first.
+		if divergence != nil && divergence.Tick < epoch.Epoch {
+			return divergence, nil
+		}
+		recorded, err := checkPrevStateHash(worlds[0], epoch)
+		if err != nil || recorded != nil {
+			return recorded, err
+		}
+		if divergence != nil {
+			return divergence, nil
+		}
+	}
+	return nil, nil //nolint:nilnil // no divergence is not an error.
+}
+
+// checkPrevStateHash compares the state hash the world had at the end of the tick before the epoch with the state hash
+// that was recorded in the epoch. A nil Divergence is returned if they are the same, or if the epoch did not record a
+// state hash.
+func checkPrevStateHash(w *World, epoch *types.Epoch) (*Divergence, error) {
+	if epoch.Epoch == 0 || len(epoch.PrevStateHash) == 0 {
+		return nil, nil //nolint:nilnil // no divergence is not an error.
+	}
+	hash, err := w.StateHash(epoch.Epoch - 1)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hash, epoch.PrevStateHash) {
+		return &Divergence{Tick: epoch.Epoch - 1, Recorded: true}, nil
+	}
+	return nil, nil //nolint:nilnil // no divergence is not an error.
+}
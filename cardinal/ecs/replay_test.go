@@ -0,0 +1,159 @@
+package ecs_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/chain/x/shard/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type Counter struct {
+	Count uint64
+}
+
+func (Counter) Name() string {
+	return "counter"
+}
+
+type IncrementMsg struct {
+	Amount uint64
+}
+
+type IncrementResult struct{}
+
+// newCounterWorld returns a world with a counter entity that is incremented by increment transactions. If leak is not
+// nil, a system called "leaky" adds leak's value to the counter from tick 2 onward, and then increments leak. Since
+// leak is shared between worlds, the worlds end up with different state.
+func newCounterWorld(t *testing.T, leak *uint64, opts ...ecs.Option) (*ecs.World,
+	*ecs.TransactionType[IncrementMsg, IncrementResult]) {
+	w := ecs.NewTestWorld(t, opts...)
+	assert.NilError(t, ecs.RegisterComponent[Counter](w))
+	incrementTx := ecs.NewTransactionType[IncrementMsg, IncrementResult]("increment")
+	assert.NilError(t, w.RegisterTransactions(incrementTx))
+	w.AddSystemWithName(func(wCtx ecs.WorldContext) error {
+		if wCtx.CurrentTick() == 0 {
+			_, err := component.Create(wCtx, Counter{})
+			return err
+		}
+		for _, tx := range incrementTx.In(wCtx) {
+			err := component.UpdateComponent[Counter](wCtx, 0, func(c *Counter) *Counter {
+				c.Count += tx.Value.Amount
+				return c
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, "increment")
+	w.AddSystemWithName(func(wCtx ecs.WorldContext) error {
+		if leak == nil || wCtx.CurrentTick() < 2 {
+			return nil
+		}
+		*leak++
+		return component.UpdateComponent[Counter](wCtx, 0, func(c *Counter) *Counter {
+			c.Count += *leak
+			return c
+		})
+	}, "leaky")
+	return w, incrementTx
+}
+
+// recordHistory runs a world with a transaction log for 4 ticks, with increment transactions in ticks 1 and 3. leak is
+// passed to newCounterWorld.
+func recordHistory(t *testing.T, leak *uint64) []*types.Epoch {
+	var log bytes.Buffer
+	w, incrementTx := newCounterWorld(t, leak, ecs.WithTransactionLog(&log))
+	assert.NilError(t, w.LoadGameState())
+	ctx := context.Background()
+	for i := uint64(0); i < 4; i++ {
+		if i%2 == 1 {
+			incrementTx.AddToQueue(w, IncrementMsg{Amount: i})
+		}
+		assert.NilError(t, w.Tick(ctx))
+	}
+	history, err := ecs.ReadTransactionLog(&log)
+	assert.NilError(t, err)
+	return history
+}
+
+func TestTransactionLogContainsEveryTick(t *testing.T) {
+	history := recordHistory(t, nil)
+	assert.Equal(t, 4, len(history))
+	for i, epoch := range history {
+		assert.Equal(t, uint64(i), epoch.Epoch)
+		assert.Check(t, epoch.Timestamp > 0)
+	}
+	assert.Equal(t, 0, len(history[2].Txs))
+	assert.Equal(t, 1, len(history[3].Txs))
+}
+
+func TestVerifyReplayOfDeterministicWorld(t *testing.T) {
+	history := recordHistory(t, nil)
+	divergence, err := ecs.VerifyReplay(context.Background(), func() (*ecs.World, error) {
+		w, _ := newCounterWorld(t, nil)
+		return w, nil
+	}, history)
+	assert.NilError(t, err)
+	assert.Check(t, divergence == nil)
+}
+
+func TestVerifyReplayFindsFirstDivergence(t *testing.T) {
+	history := recordHistory(t, nil)
+	var leak uint64
+	divergence, err := ecs.VerifyReplay(context.Background(), func() (*ecs.World, error) {
+		w, _ := newCounterWorld(t, &leak)
+		return w, nil
+	}, history)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &ecs.Divergence{Tick: 2, System: "leaky"}, divergence)
+}
+
+func TestVerifyReplayComparesWithRecordedStateHashes(t *testing.T) {
+	// The recorded history was produced by a world whose state leaked from tick 2 onward, but the replays agree with
+	// each other.
+	var leak uint64
+	history := recordHistory(t, &leak)
+	divergence, err := ecs.VerifyReplay(context.Background(), func() (*ecs.World, error) {
+		w, _ := newCounterWorld(t, nil)
+		return w, nil
+	}, history)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &ecs.Divergence{Tick: 2, Recorded: true}, divergence)
+}
+
+func TestVerifyReplayOfEpochsFromChain(t *testing.T) {
+	ctx := context.Background()
+	adapter := &DummyAdapter{}
+	w, incrementTx := newCounterWorld(t, nil, ecs.WithAdapter(adapter))
+	assert.NilError(t, w.LoadGameState())
+	for i := uint64(0); i < 6; i++ {
+		if i%2 == 1 {
+			msg := IncrementMsg{Amount: i}
+			body, err := incrementTx.Encode(msg)
+			assert.NilError(t, err)
+			incrementTx.AddToQueue(w, msg, &sign.Transaction{PersonaTag: "player", Nonce: i, Body: body})
+		}
+		assert.NilError(t, w.Tick(ctx))
+	}
+	assert.NilError(t, w.SubmitQueuedEpochs(ctx))
+
+	history, err := ecs.QueryEpochs(ctx, adapter, w.Namespace().String())
+	assert.NilError(t, err)
+	// Only the first tick and the ticks with transactions are stored on the base shard.
+	assert.Equal(t, 4, len(history))
+
+	var leak uint64
+	divergence, err := ecs.VerifyReplay(ctx, func() (*ecs.World, error) {
+		replayWorld, _ := newCounterWorld(t, &leak)
+		return replayWorld, nil
+	}, history)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &ecs.Divergence{Tick: 2, System: "leaky"}, divergence)
+}
@@ -1,6 +1,8 @@
 package ecs
 
 import (
+	"errors"
+
 	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	"pkg.world.dev/world-engine/cardinal/ecs/filter"
@@ -22,6 +24,13 @@ type cache struct {
 type Search struct {
 	archMatches map[Namespace]*cache
 	filter      filter.ComponentFilter
+	where       []whereCondition
+}
+
+// whereCondition is an indexed field and the value it must have.
+type whereCondition struct {
+	field string
+	value any
 }
 
 // NewSearch creates a new search.
@@ -35,17 +44,46 @@ func NewSearch(filter filter.ComponentFilter) *Search {
 
 type SearchCallBackFn func(entity.ID) bool
 
+// Where returns a copy of the search that only matches entities whose indexed field has the given value. The field is
+// either "Component.Field", or the name of a field that only one registered component indexes (see WithIndex). If the
+// field is a slice, entities match if any element has the value. Entities found this way are visited in order of ID.
+func (q *Search) Where(field string, value any) *Search {
+	where := make([]whereCondition, 0, len(q.where)+1)
+	where = append(where, q.where...)
+	return &Search{
+		archMatches: q.archMatches,
+		filter:      q.filter,
+		where:       append(where, whereCondition{field: field, value: value}),
+	}
+}
+
 // Each iterates over all entities that match the search.
 // If you would like to stop the iteration, return false to the callback. To continue iterating, return true.
 func (q *Search) Each(wCtx WorldContext, callback SearchCallBackFn) error {
+	if len(q.where) > 0 {
+		ids, err := q.evaluateWhere(wCtx)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if !callback(id) {
+				return nil
+			}
+		}
+		return nil
+	}
 	reader := wCtx.StoreReader()
 	result := q.evaluateSearch(wCtx.GetWorld().Namespace(), reader)
-	iter := storage.NewEntityIterator(0, reader, result)
-	for iter.HasNext() {
-		entities, err := iter.Next()
+	for _, archID := range result {
+		entities, err := reader.GetEntitiesForArchID(archID)
 		if err != nil {
 			return err
 		}
+		if !wCtx.IsReadOnly() {
+			if err = preloadArchetype(reader, archID, entities); err != nil {
+				return err
+			}
+		}
 		for _, id := range entities {
 			cont := callback(id)
 			if !cont {
@@ -56,8 +94,28 @@ func (q *Search) Each(wCtx WorldContext, callback SearchCallBackFn) error {
 	return nil
 }
 
+// preloadArchetype fetches the values of every component of the given archetype's entities with one round trip per
+// component, so the callbacks of Each read them from memory. Read only contexts do not cache values, so they are not
+// preloaded. Components that a system did not declare access to are skipped.
+func preloadArchetype(reader store.Reader, archID archetype.ID, entities []entity.ID) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	for _, cType := range reader.GetComponentTypesForArchID(archID) {
+		_, err := reader.GetComponentsForEntities(cType, entities)
+		if err != nil && !errors.Is(err, ErrComponentAccessNotDeclared) {
+			return err
+		}
+	}
+	return nil
+}
+
 // Count returns the number of entities that match the search.
 func (q *Search) Count(wCtx WorldContext) (int, error) {
+	if len(q.where) > 0 {
+		ids, err := q.evaluateWhere(wCtx)
+		return len(ids), err
+	}
 	namespace := wCtx.GetWorld().Namespace()
 	reader := wCtx.StoreReader()
 	result := q.evaluateSearch(namespace, reader)
@@ -75,6 +133,13 @@ func (q *Search) Count(wCtx WorldContext) (int, error) {
 
 // First returns the first entity that matches the search.
 func (q *Search) First(wCtx WorldContext) (id entity.ID, err error) {
+	if len(q.where) > 0 {
+		ids, err := q.evaluateWhere(wCtx)
+		if err != nil || len(ids) == 0 {
+			return storage.BadID, err
+		}
+		return ids[0], nil
+	}
 	namespace := wCtx.GetWorld().Namespace()
 	reader := wCtx.StoreReader()
 	result := q.evaluateSearch(namespace, reader)
@@ -117,3 +182,54 @@ func (q *Search) evaluateSearch(namespace Namespace, sm store.Reader) []archetyp
 	cache.seen = sm.ArchetypeCount()
 	return cache.archetypes
 }
+
+// evaluateWhere finds the entities that match every where condition using the field indexes, and then drops the
+// entities that do not match the search's filter. The entities are sorted by ID.
+func (q *Search) evaluateWhere(wCtx WorldContext) ([]entity.ID, error) {
+	reader := wCtx.StoreReader()
+	var candidates []entity.ID
+	for i, cond := range q.where {
+		cType, field, err := wCtx.GetWorld().getIndexedField(cond.field)
+		if err != nil {
+			return nil, err
+		}
+		ids, err := reader.GetEntitiesByIndex(cType, field, cond.value)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			candidates = ids
+		} else {
+			candidates = intersectSortedIDs(candidates, ids)
+		}
+	}
+	result := make([]entity.ID, 0, len(candidates))
+	for _, id := range candidates {
+		comps, err := reader.GetComponentTypesForEntity(id)
+		if err != nil {
+			return nil, err
+		}
+		if q.filter.MatchesComponents(comps) {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// intersectSortedIDs returns the entities that are in both of the given sorted slices.
+func intersectSortedIDs(a, b []entity.ID) []entity.ID {
+	result := make([]entity.ID, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
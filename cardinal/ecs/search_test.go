@@ -1,13 +1,18 @@
 package ecs_test
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
 
 	"pkg.world.dev/world-engine/cardinal/ecs"
 	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/cql"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
 )
 
 type FooComponent struct {
@@ -45,3 +50,144 @@ func TestSearchEarlyTermination(t *testing.T) {
 	}))
 	assert.Equal(t, count, total)
 }
+
+// componentGetCountingKVStore counts the component values that are fetched one at a time.
+type componentGetCountingKVStore struct {
+	ecb.KVStore
+	gets int
+}
+
+func (c *componentGetCountingKVStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if strings.HasPrefix(key, "ECB:COMPONENT-VALUE:") {
+		c.gets++
+	}
+	return c.KVStore.Get(ctx, key)
+}
+
+func TestSearchEachPreloadsComponentValues(t *testing.T) {
+	kv := &componentGetCountingKVStore{KVStore: ecb.NewMemoryKVStore()}
+	newWorld := func() *ecs.World {
+		manager, err := ecb.NewManagerWithKVStore(kv)
+		assert.NilError(t, err)
+		world, err := ecs.NewWorld(ecb.NewKVNonceStorage(kv), manager)
+		assert.NilError(t, err)
+		assert.NilError(t, ecs.RegisterComponent[FooComponent](world))
+		assert.NilError(t, ecs.RegisterComponent[LabelComponent](world))
+		assert.NilError(t, world.LoadGameState())
+		return world
+	}
+	world := newWorld()
+	wCtx := ecs.NewWorldContext(world)
+	_, err := component.CreateMany(wCtx, 5, FooComponent{Data: "x"})
+	assert.NilError(t, err)
+	_, err = component.CreateMany(wCtx, 5, FooComponent{Data: "y"}, LabelComponent{Data: "z"})
+	assert.NilError(t, err)
+	assert.NilError(t, world.Tick(context.Background()))
+
+	// A new world only has the values in the KVStore.
+	world = newWorld()
+	wCtx = ecs.NewWorldContext(world)
+	q, err := world.NewSearch(ecs.Contains(FooComponent{}))
+	assert.NilError(t, err)
+	kv.gets = 0
+	count := 0
+	assert.NilError(t, q.Each(wCtx, func(id entity.ID) bool {
+		_, err := component.GetComponent[FooComponent](wCtx, id)
+		assert.NilError(t, err)
+		count++
+		return true
+	}))
+	assert.Equal(t, 10, count)
+	assert.Equal(t, 0, kv.gets)
+}
+
+type LabelComponent struct {
+	Data   string
+	Labels []string
+}
+
+func (LabelComponent) Name() string {
+	return "label"
+}
+
+func TestSearchWhere(t *testing.T) {
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[FooComponent](world, ecs.WithIndex("Data")))
+	assert.NilError(t, ecs.RegisterComponent[LabelComponent](world, ecs.WithIndex("Data"), ecs.WithIndex("Labels")))
+	assert.NilError(t, world.LoadGameState())
+	wCtx := ecs.NewWorldContext(world)
+
+	foo, err := component.Create(wCtx, FooComponent{Data: "x"})
+	assert.NilError(t, err)
+	both, err := component.Create(wCtx, FooComponent{Data: "x"}, LabelComponent{Data: "x", Labels: []string{"a", "b"}})
+	assert.NilError(t, err)
+	label, err := component.Create(wCtx, LabelComponent{Data: "y", Labels: []string{"b"}})
+	assert.NilError(t, err)
+
+	collect := func(q *ecs.Search) []entity.ID {
+		ids := []entity.ID{}
+		assert.NilError(t, q.Each(wCtx, func(id entity.ID) bool {
+			ids = append(ids, id)
+			return true
+		}))
+		return ids
+	}
+	q, err := world.NewSearch(ecs.Contains(LabelComponent{}))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []entity.ID{both, label}, collect(q.Where("Labels", "b")))
+	assert.DeepEqual(t, []entity.ID{both}, collect(q.Where("Labels", "b").Where("label.Data", "x")))
+	count, err := q.Where("Labels", "c").Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, count)
+
+	// The search's filter still applies.
+	q, err = world.NewSearch(ecs.Exact(FooComponent{}))
+	assert.NilError(t, err)
+	first, err := q.Where("foo.Data", "x").First(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, foo, first)
+
+	// Fields indexed by more than one component must name the component.
+	_, err = q.Where("Data", "x").First(wCtx)
+	assert.ErrorContains(t, err, "indexed by both")
+	_, err = q.Where("Missing", "x").First(wCtx)
+	assert.ErrorIs(t, err, storage.ErrFieldNotIndexed)
+
+	// Only exported fields can be indexed.
+	world = ecs.NewTestWorld(t)
+	assert.Check(t, ecs.RegisterComponent[FooComponent](world, ecs.WithIndex("data")) != nil)
+}
+
+func TestSearchCQL(t *testing.T) {
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[FooComponent](world))
+	assert.NilError(t, ecs.RegisterComponent[LabelComponent](world, ecs.WithIndex("Data")))
+	assert.NilError(t, world.LoadGameState())
+	wCtx := ecs.NewWorldContext(world)
+
+	x, err := component.Create(wCtx, LabelComponent{Data: "x", Labels: []string{"a"}})
+	assert.NilError(t, err)
+	xFoo, err := component.Create(wCtx, FooComponent{Data: "x"}, LabelComponent{Data: "x"})
+	assert.NilError(t, err)
+	_, err = component.Create(wCtx, LabelComponent{Data: "y"})
+	assert.NilError(t, err)
+
+	for _, tc := range []struct {
+		cql  string
+		want []entity.ID
+	}{
+		{cql: `CONTAINS(label) WHERE label.Data = "x"`, want: []entity.ID{x, xFoo}},
+		{cql: `EXACT(label) WHERE label.Data = "x"`, want: []entity.ID{x}},
+		{cql: `CONTAINS(label) WHERE label.Data = "x" AND foo.Data = "x"`, want: []entity.ID{xFoo}},
+		{cql: `CONTAINS(label) WHERE label.Data < "x"`, want: []entity.ID{}},
+	} {
+		query, err := cql.ParseQuery(tc.cql, world.GetComponentByName)
+		assert.NilError(t, err)
+		got := []entity.ID{}
+		assert.NilError(t, ecs.SearchCQL(wCtx, query, func(id entity.ID) bool {
+			got = append(got, id)
+			return true
+		}))
+		assert.DeepEqual(t, tc.want, got)
+	}
+}
@@ -0,0 +1,187 @@
+package ecs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+// snapshotVersion is the version of the snapshot format written by World.Snapshot. It must be incremented any time
+// the format changes in a way that older versions of World.Restore would misinterpret.
+const snapshotVersion = 1
+
+var (
+	ErrUnsupportedSnapshotVersion = errors.New("unsupported snapshot version")
+)
+
+// snapshot is the on-disk format of a world snapshot. Components are referenced by name so that a snapshot can be
+// restored even if the components are registered in a different order.
+type snapshot struct {
+	Version      int                 `json:"version"`
+	Namespace    string              `json:"namespace"`
+	Tick         uint64              `json:"tick"`
+	NextEntityID entity.ID           `json:"nextEntityId"`
+	Archetypes   []snapshotArchetype `json:"archetypes"`
+	Nonces       map[string]uint64   `json:"nonces"`
+}
+
+type snapshotArchetype struct {
+	Components []string         `json:"components"`
+	Entities   []snapshotEntity `json:"entities"`
+}
+
+type snapshotEntity struct {
+	ID     entity.ID         `json:"id"`
+	Values []json.RawMessage `json:"values"`
+}
+
+// Snapshot writes the complete state of the world (every archetype, entity, and component value, as well as the
+// nonces, the next entity ID and the current tick) to the given writer. The snapshot can later be loaded into a new
+// world with Restore. Snapshot must not be called while the game loop is running.
+func (w *World) Snapshot(writer io.Writer) error {
+	if !w.stateIsLoaded {
+		return errors.New("must load state before taking a snapshot")
+	}
+	if w.IsGameLoopRunning() {
+		return errors.New("cannot take a snapshot while the game loop is running")
+	}
+	nextEntityID, err := w.StoreManager().NextEntityID()
+	if err != nil {
+		return err
+	}
+	nonces, err := w.nonceStore.GetAllNonces()
+	if err != nil {
+		return err
+	}
+	snap := snapshot{
+		Version:      snapshotVersion,
+		Namespace:    w.Namespace().String(),
+		Tick:         w.CurrentTick(),
+		NextEntityID: nextEntityID,
+		Nonces:       nonces,
+	}
+
+	for i := 0; i < w.StoreManager().ArchetypeCount(); i++ {
+		archID := archetype.ID(i)
+		comps := w.StoreManager().GetComponentTypesForArchID(archID)
+		ids, err := w.StoreManager().GetEntitiesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		arch := snapshotArchetype{
+			Components: make([]string, 0, len(comps)),
+			Entities:   make([]snapshotEntity, 0, len(ids)),
+		}
+		for _, comp := range comps {
+			arch.Components = append(arch.Components, comp.Name())
+		}
+		for _, id := range ids {
+			arch.Entities = append(arch.Entities, snapshotEntity{ID: id, Values: make([]json.RawMessage, 0, len(comps))})
+		}
+		for _, comp := range comps {
+			values, err := w.StoreManager().GetComponentsForEntities(comp, ids)
+			if err != nil {
+				return err
+			}
+			for j, value := range values {
+				bz, err := comp.Encode(value)
+				if err != nil {
+					return err
+				}
+				arch.Entities[j].Values = append(arch.Entities[j].Values, bz)
+			}
+		}
+		snap.Archetypes = append(snap.Archetypes, arch)
+	}
+
+	return json.NewEncoder(writer).Encode(snap)
+}
+
+// Restore loads a snapshot that was created with Snapshot into this world. The world's state will be loaded (see
+// LoadGameState) if it has not been loaded already, however the world must not contain any state. All components
+// that appear in the snapshot must be registered before calling Restore. After a successful restore, the world's
+// current tick will be the tick at which the snapshot was taken, and RecoverFromChain can be used to catch up to the
+// latest tick.
+func (w *World) Restore(reader io.Reader) error {
+	var snap snapshot
+	if err := json.NewDecoder(reader).Decode(&snap); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("snapshot has version %d, but only version %d is supported: %w",
+			snap.Version, snapshotVersion, ErrUnsupportedSnapshotVersion)
+	}
+	if snap.Namespace != w.Namespace().String() {
+		return fmt.Errorf("snapshot was taken in namespace %q, but the world's namespace is %q",
+			snap.Namespace, w.Namespace())
+	}
+
+	if !w.stateIsLoaded {
+		if err := w.LoadGameState(); err != nil {
+			return err
+		}
+	}
+	if w.CurrentTick() > 0 || w.StoreManager().ArchetypeCount() > 0 {
+		return errors.New("a snapshot can only be restored to a world with no existing state")
+	}
+
+	state := store.State{
+		Tick:         snap.Tick,
+		NextEntityID: snap.NextEntityID,
+		Archetypes:   make([]store.ArchetypeState, 0, len(snap.Archetypes)),
+	}
+	for _, arch := range snap.Archetypes {
+		comps := make([]metadata.ComponentMetadata, 0, len(arch.Components))
+		for _, name := range arch.Components {
+			comp, err := w.GetComponentByName(name)
+			if err != nil {
+				return err
+			}
+			comps = append(comps, comp)
+		}
+		archState := store.ArchetypeState{
+			Components: comps,
+			Entities:   make([]store.EntityState, 0, len(arch.Entities)),
+		}
+		for _, ent := range arch.Entities {
+			if len(ent.Values) != len(comps) {
+				return fmt.Errorf("entity %d has %d component values, but %d were expected",
+					ent.ID, len(ent.Values), len(comps))
+			}
+			// Make sure each value can actually be decoded by the registered component.
+			for i, comp := range comps {
+				if _, err := comp.Decode(ent.Values[i]); err != nil {
+					return fmt.Errorf("failed to decode component %q for entity %d: %w", comp.Name(), ent.ID, err)
+				}
+			}
+			archState.Entities = append(archState.Entities, store.EntityState{ID: ent.ID, Values: ent.Values})
+		}
+		state.Archetypes = append(state.Archetypes, archState)
+	}
+
+	// If the store manager also saves the nonces, they are saved in the same batch as the rest of the state. Otherwise
+	// they are saved first, so a failed restore can only leave nonces that are too high. Those never allow a
+	// transaction to be replayed, and they are overwritten when the restore is retried.
+	if manager, ok := w.StoreManager().(*ecb.Manager); ok && manager.SavesNonces(w.nonceStore) {
+		state.Nonces = snap.Nonces
+	} else {
+		for signerAddress, nonce := range snap.Nonces {
+			if err := w.nonceStore.SetNonce(signerAddress, nonce); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.StoreManager().RestoreState(state); err != nil {
+		return err
+	}
+	w.tick = snap.Tick
+	w.receiptHistory.SetTick(w.tick)
+	return nil
+}
@@ -0,0 +1,212 @@
+package ecs_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/ecb"
+)
+
+func newSnapshotTestWorld(t *testing.T) *ecs.World {
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[EnergyComponent](world))
+	assert.NilError(t, ecs.RegisterComponent[OwnableComponent](world))
+	return world
+}
+
+func TestSnapshotCanBeRestoredToNewWorld(t *testing.T) {
+	ctx := context.Background()
+	oneWorld := newSnapshotTestWorld(t)
+	assert.NilError(t, oneWorld.LoadGameState())
+	wCtx := ecs.NewWorldContext(oneWorld)
+
+	energyIDs, err := component.CreateMany(wCtx, 5, EnergyComponent{})
+	assert.NilError(t, err)
+	bothIDs, err := component.CreateMany(wCtx, 3, EnergyComponent{}, OwnableComponent{})
+	assert.NilError(t, err)
+	for i, id := range append(energyIDs, bothIDs...) {
+		assert.NilError(t, component.SetComponent[EnergyComponent](wCtx, id, &EnergyComponent{Amt: int64(i), Cap: 100}))
+	}
+	assert.NilError(t, component.SetComponent[OwnableComponent](wCtx, bothIDs[1], &OwnableComponent{Owner: "alpha"}))
+	assert.NilError(t, oneWorld.Remove(energyIDs[2]))
+	assert.NilError(t, oneWorld.SetNonce("some-address", 99))
+	for i := 0; i < 3; i++ {
+		assert.NilError(t, oneWorld.Tick(ctx))
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NilError(t, oneWorld.Snapshot(buf))
+
+	twoWorld := newSnapshotTestWorld(t)
+	assert.NilError(t, twoWorld.Restore(buf))
+	assert.Equal(t, oneWorld.CurrentTick(), twoWorld.CurrentTick())
+
+	nonce, err := twoWorld.GetNonce("some-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(99), nonce)
+
+	twoCtx := ecs.NewWorldContext(twoWorld)
+	search, err := twoWorld.NewSearch(ecs.Contains(EnergyComponent{}))
+	assert.NilError(t, err)
+	count, err := search.Count(twoCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 7, count)
+
+	for _, id := range append(energyIDs, bothIDs...) {
+		wantEnergy, err := component.GetComponent[EnergyComponent](wCtx, id)
+		if id == energyIDs[2] {
+			assert.Check(t, err != nil)
+			_, err = component.GetComponent[EnergyComponent](twoCtx, id)
+			assert.Check(t, err != nil)
+			continue
+		}
+		assert.NilError(t, err)
+		gotEnergy, err := component.GetComponent[EnergyComponent](twoCtx, id)
+		assert.NilError(t, err)
+		assert.Equal(t, *wantEnergy, *gotEnergy)
+	}
+	gotOwner, err := component.GetComponent[OwnableComponent](twoCtx, bothIDs[1])
+	assert.NilError(t, err)
+	assert.Equal(t, "alpha", gotOwner.Owner)
+
+	// Both worlds should assign the same ID to the next created entity.
+	wantID, err := component.Create(wCtx, OwnableComponent{})
+	assert.NilError(t, err)
+	gotID, err := component.Create(twoCtx, OwnableComponent{})
+	assert.NilError(t, err)
+	assert.Equal(t, wantID, gotID)
+
+	// The restored world should continue ticking from the snapshot's tick.
+	assert.NilError(t, twoWorld.Tick(ctx))
+	assert.Equal(t, oneWorld.CurrentTick()+1, twoWorld.CurrentTick())
+}
+
+func newDiskWorldForSnapshotTest(t *testing.T, dir string) (*ecs.World, ecb.KVStore) {
+	kv, err := ecb.NewDiskKVStore(dir)
+	assert.NilError(t, err)
+	manager, err := ecb.NewManagerWithKVStore(kv)
+	assert.NilError(t, err)
+	world, err := ecs.NewWorld(ecb.NewKVNonceStorage(kv), manager)
+	assert.NilError(t, err)
+	assert.NilError(t, ecs.RegisterComponent[EnergyComponent](world))
+	assert.NilError(t, ecs.RegisterComponent[OwnableComponent](world))
+	return world, kv
+}
+
+func TestRestoredStateSurvivesReload(t *testing.T) {
+	oneWorld := newSnapshotTestWorld(t)
+	assert.NilError(t, oneWorld.LoadGameState())
+	id, err := component.Create(ecs.NewWorldContext(oneWorld), EnergyComponent{Amt: 5})
+	assert.NilError(t, err)
+	assert.NilError(t, oneWorld.SetNonce("some-address", 7))
+	assert.NilError(t, oneWorld.Tick(context.Background()))
+	buf := &bytes.Buffer{}
+	assert.NilError(t, oneWorld.Snapshot(buf))
+
+	dir := t.TempDir()
+	twoWorld, kv := newDiskWorldForSnapshotTest(t, dir)
+	assert.NilError(t, twoWorld.Restore(buf))
+	assert.NilError(t, kv.Close())
+
+	threeWorld, kv := newDiskWorldForSnapshotTest(t, dir)
+	defer kv.Close()
+	assert.NilError(t, threeWorld.LoadGameState())
+	assert.Equal(t, oneWorld.CurrentTick(), threeWorld.CurrentTick())
+	energy, err := component.GetComponent[EnergyComponent](ecs.NewWorldContext(threeWorld), id)
+	assert.NilError(t, err)
+	assert.Equal(t, int64(5), energy.Amt)
+	nonce, err := threeWorld.GetNonce("some-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(7), nonce)
+}
+
+// countingKVStore counts the batches that are applied to a KVStore.
+type countingKVStore struct {
+	ecb.KVStore
+	execs int
+}
+
+func (c *countingKVStore) NewBatch() ecb.KVBatch {
+	return &countingKVBatch{KVBatch: c.KVStore.NewBatch(), store: c}
+}
+
+type countingKVBatch struct {
+	ecb.KVBatch
+	store *countingKVStore
+}
+
+func (b *countingKVBatch) Exec(ctx context.Context) error {
+	b.store.execs++
+	return b.KVBatch.Exec(ctx)
+}
+
+func TestRestoreSavesNoncesInTheSameBatchAsTheState(t *testing.T) {
+	oneWorld := newSnapshotTestWorld(t)
+	assert.NilError(t, oneWorld.LoadGameState())
+	_, err := component.Create(ecs.NewWorldContext(oneWorld), EnergyComponent{Amt: 5})
+	assert.NilError(t, err)
+	assert.NilError(t, oneWorld.SetNonce("some-address", 7))
+	assert.NilError(t, oneWorld.SetNonce("other-address", 3))
+	assert.NilError(t, oneWorld.Tick(context.Background()))
+	buf := &bytes.Buffer{}
+	assert.NilError(t, oneWorld.Snapshot(buf))
+
+	kv := &countingKVStore{KVStore: ecb.NewMemoryKVStore()}
+	manager, err := ecb.NewManagerWithKVStore(kv)
+	assert.NilError(t, err)
+	twoWorld, err := ecs.NewWorld(ecb.NewKVNonceStorage(kv), manager)
+	assert.NilError(t, err)
+	assert.NilError(t, ecs.RegisterComponent[EnergyComponent](twoWorld))
+	assert.NilError(t, ecs.RegisterComponent[OwnableComponent](twoWorld))
+	assert.NilError(t, twoWorld.LoadGameState())
+	kv.execs = 0
+	assert.NilError(t, twoWorld.Restore(buf))
+	assert.Equal(t, 1, kv.execs)
+
+	nonce, err := twoWorld.GetNonce("some-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(7), nonce)
+	nonce, err = twoWorld.GetNonce("other-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+}
+
+func TestRestoreFailsWhenWorldHasState(t *testing.T) {
+	oneWorld := newSnapshotTestWorld(t)
+	assert.NilError(t, oneWorld.LoadGameState())
+	buf := &bytes.Buffer{}
+	assert.NilError(t, oneWorld.Snapshot(buf))
+
+	twoWorld := newSnapshotTestWorld(t)
+	assert.NilError(t, twoWorld.LoadGameState())
+	assert.NilError(t, twoWorld.Tick(context.Background()))
+	err := twoWorld.Restore(buf)
+	assert.ErrorContains(t, err, "no existing state")
+}
+
+func TestRestoreFailsWithUnknownVersion(t *testing.T) {
+	world := newSnapshotTestWorld(t)
+	err := world.Restore(strings.NewReader(`{"version": 1000, "namespace": "world"}`))
+	assert.ErrorIs(t, err, ecs.ErrUnsupportedSnapshotVersion)
+}
+
+func TestRestoreFailsWithUnregisteredComponent(t *testing.T) {
+	oneWorld := newSnapshotTestWorld(t)
+	assert.NilError(t, oneWorld.LoadGameState())
+	_, err := component.Create(ecs.NewWorldContext(oneWorld), OwnableComponent{})
+	assert.NilError(t, err)
+	buf := &bytes.Buffer{}
+	assert.NilError(t, oneWorld.Snapshot(buf))
+
+	twoWorld := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[EnergyComponent](twoWorld))
+	err = twoWorld.Restore(buf)
+	assert.ErrorContains(t, err, "OwnableComponent")
+	assert.Equal(t, 0, twoWorld.StoreManager().ArchetypeCount())
+}
@@ -0,0 +1,45 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+var ErrStateHashNotFound = errors.New("no state hash was saved for the tick")
+
+// StateHash returns the hash of the world's state at the end of the given tick. The hash is the root of a Merkle tree
+// with a leaf for every component value of every entity (see the merkle package), so worlds that processed the same
+// transactions have the same state hash. ErrStateHashNotFound is returned if the tick has not been finalized yet, if
+// it was finalized before state hashes were saved, or if it is older than the state hash retention (see
+// WithStateHashRetention).
+func (w *World) StateHash(tick uint64) ([]byte, error) {
+	hash, err := w.entityStore.GetStateHash(tick)
+	if err != nil {
+		return nil, err
+	}
+	if hash == nil {
+		return nil, fmt.Errorf("%w: tick %d", ErrStateHashNotFound, tick)
+	}
+	return hash, nil
+}
+
+// GetComponentProof returns the value of the given component on the given entity at the end of the given tick, along
+// with a proof that the value is part of that tick's state hash. The proof can be checked with merkle.Verify, so anyone
+// who trusts the state hash (e.g. because it was submitted to the base shard) can trust the value without trusting the
+// world that returned it. Proofs can only be built for ticks that were finalized since the state was loaded, and that
+// are within the proof retention (see WithProofRetention).
+func (w *World) GetComponentProof(cType metadata.ComponentMetadata, id entity.ID, tick uint64) (
+	*store.ComponentProof, error) {
+	return w.entityStore.GetComponentProof(cType, id, tick)
+}
+
+// stateHash returns the state hash of every component value of every entity, including any changes that have not
+// been committed yet. At the end of a tick, this is the same hash that FinalizeTick saves. Only component values are
+// hashed; the events and transaction receipts of a tick are not part of the state hash.
+func (w *World) stateHash() ([]byte, error) {
+	return w.entityStore.GetPendingStateHash()
+}
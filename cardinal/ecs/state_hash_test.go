@@ -0,0 +1,50 @@
+package ecs_test
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestStateHashIsSubmittedWithTheNextEpoch(t *testing.T) {
+	ctx := context.Background()
+	adapter := &DummyAdapter{}
+	w, incrementTx := newCounterWorld(t, nil, ecs.WithAdapter(adapter))
+	assert.NilError(t, w.LoadGameState())
+	for i := uint64(0); i < 4; i++ {
+		if i%2 == 1 {
+			msg := IncrementMsg{Amount: i}
+			body, err := incrementTx.Encode(msg)
+			assert.NilError(t, err)
+			incrementTx.AddToQueue(w, msg, &sign.Transaction{PersonaTag: "player", Nonce: i, Body: body})
+		}
+		assert.NilError(t, w.Tick(ctx))
+	}
+	assert.NilError(t, w.SubmitQueuedEpochs(ctx))
+
+	hashes := make([][]byte, 4)
+	for tick := range hashes {
+		var err error
+		hashes[tick], err = w.StateHash(uint64(tick))
+		assert.NilError(t, err)
+	}
+	// The counter only changes in ticks with increment transactions.
+	assert.DeepEqual(t, hashes[1], hashes[2])
+	assert.Check(t, string(hashes[0]) != string(hashes[1]))
+	assert.Check(t, string(hashes[2]) != string(hashes[3]))
+
+	epochs, err := ecs.QueryEpochs(ctx, adapter, w.Namespace().String())
+	assert.NilError(t, err)
+	// The first tick is also an epoch, but there is no state before it.
+	assert.Equal(t, 3, len(epochs))
+	assert.Check(t, epochs[0].PrevStateHash == nil)
+	assert.DeepEqual(t, hashes[0], epochs[1].PrevStateHash)
+	assert.DeepEqual(t, hashes[2], epochs[2].PrevStateHash)
+
+	_, err = w.StateHash(w.CurrentTick())
+	assert.ErrorIs(t, err, ecs.ErrStateHashNotFound)
+}
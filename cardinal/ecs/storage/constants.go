@@ -13,6 +13,9 @@ var (
 	ErrComponentAlreadyOnEntity          = errors.New("component already on entity")
 	ErrComponentNotOnEntity              = errors.New("component not on entity")
 	ErrEntityMustHaveAtLeastOneComponent = errors.New("entities must have at least 1 component")
+	ErrComponentIsNotARelation           = errors.New("component is not a relation")
+	ErrRelationTargetNotFound            = errors.New("relation target entity does not exist")
+	ErrFieldNotIndexed                   = errors.New("component field is not indexed")
 
 	// ErrComponentMismatchWithSavedState is an error that is returned when a TypeID from
 	// the saved state is not found in the passed in list of components.
@@ -3,4 +3,6 @@ package storage
 type NonceStorage interface {
 	GetNonce(key string) (uint64, error)
 	SetNonce(key string, nonce uint64) error
+	// GetAllNonces returns every saved nonce keyed by signer address.
+	GetAllNonces() (map[string]uint64, error)
 }
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"maps"
+	"sync"
+)
+
+// MemoryStorage keeps nonces in process memory. Nothing is persisted, so it is only suitable for tests and local
+// development.
+type MemoryStorage struct {
+	mu     sync.RWMutex
+	nonces map[string]uint64
+}
+
+var _ NonceStorage = &MemoryStorage{}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		nonces: map[string]uint64{},
+	}
+}
+
+// GetNonce returns the saved nonce for the given signer address. Addresses that have never been saved have a nonce
+// of 0.
+func (m *MemoryStorage) GetNonce(signerAddress string) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.nonces[signerAddress], nil
+}
+
+// SetNonce saves the given nonce value with the given signer address. Any string can be used for the signer address,
+// and no nonce verification takes place.
+func (m *MemoryStorage) SetNonce(signerAddress string, nonce uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nonces[signerAddress] = nonce
+	return nil
+}
+
+// GetAllNonces returns every saved nonce keyed by signer address.
+func (m *MemoryStorage) GetAllNonces() (map[string]uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return maps.Clone(m.nonces), nil
+}
@@ -57,3 +57,15 @@ func (m *MockComponentType[T]) Decode(bytes []byte) (any, error) {
 func (m *MockComponentType[T]) Encode(a any) ([]byte, error) {
 	return codec.Encode(a)
 }
+
+func (m *MockComponentType[T]) AddIndex(string) error {
+	return fmt.Errorf("mock component %s cannot be indexed", m.Name())
+}
+
+func (m *MockComponentType[T]) Indexes() []string {
+	return nil
+}
+
+func (m *MockComponentType[T]) IndexKeys(any, string) ([]string, error) {
+	return nil, fmt.Errorf("mock component %s cannot be indexed", m.Name())
+}
@@ -3,6 +3,7 @@ package storage_test
 import (
 	"fmt"
 	"pkg.world.dev/world-engine/cardinal/ecs/internal/testutil"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -40,3 +41,25 @@ func TestCanStoreManyNonces(t *testing.T) {
 		assert.Equal(t, i, gotNonce)
 	}
 }
+
+func TestGetAllNonces(t *testing.T) {
+	rs := testutil.GetRedisStorage(t)
+	assert.NilError(t, rs.SetNonce("alpha", 10))
+	assert.NilError(t, rs.SetNonce("beta", 20))
+
+	nonces, err := rs.GetAllNonces()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]uint64{"alpha": 10, "beta": 20}, nonces)
+}
+
+func TestMemoryStorageNonces(t *testing.T) {
+	ms := storage.NewMemoryStorage()
+	gotNonce, err := ms.GetNonce("some-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), gotNonce)
+
+	assert.NilError(t, ms.SetNonce("some-address", 100))
+	gotNonce, err = ms.GetNonce("some-address")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(100), gotNonce)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strconv"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
@@ -74,6 +75,24 @@ func (r *RedisStorage) SetNonce(signerAddress string, nonce uint64) error {
 	return r.Client.HSet(ctx, r.nonceKey(), signerAddress, nonce).Err()
 }
 
+// GetAllNonces returns every saved nonce keyed by signer address.
+func (r *RedisStorage) GetAllNonces() (map[string]uint64, error) {
+	ctx := context.Background()
+	saved, err := r.Client.HGetAll(ctx, r.nonceKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+	nonces := make(map[string]uint64, len(saved))
+	for signerAddress, value := range saved {
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		nonces[signerAddress] = n
+	}
+	return nonces, nil
+}
+
 func (r *RedisStorage) Close() error {
 	err := r.Client.Close()
 	if err != nil {
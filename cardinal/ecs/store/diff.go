@@ -0,0 +1,59 @@
+package store
+
+import (
+	"encoding/json"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/archetype"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+)
+
+// DiffStorage exposes the state changes that were made during each tick.
+type DiffStorage interface {
+	// LastTickDiff returns the changes that were committed by the most recent successful call to FinalizeTick. nil is
+	// returned if no tick has been finalized.
+	LastTickDiff() *TickDiff
+}
+
+// TickDiff is the set of state changes that were committed at the end of a single tick. Changes are relative to the
+// state at the end of the previous tick, so an entity that is created and then removed in the same tick does not
+// appear at all. All slices are sorted by entity ID (and then by component name) so identical ticks produce identical
+// diffs.
+type TickDiff struct {
+	Tick uint64 `json:"tick"`
+	// CreatedEntities are the entities that were created during the tick. The value of every component on a created
+	// entity is included in ComponentSets.
+	CreatedEntities []entity.ID `json:"createdEntities"`
+	// RemovedEntities are the entities that existed at the start of the tick, but were removed during the tick.
+	RemovedEntities []entity.ID `json:"removedEntities"`
+	// ComponentSets are the component values that were set or added during the tick.
+	ComponentSets []ComponentChange `json:"componentSets"`
+	// ComponentRemovals are the components that were removed during the tick. Every component that was on a removed
+	// entity is included.
+	ComponentRemovals []ComponentChange `json:"componentRemovals"`
+	// ArchetypeMoves are the entities that ended the tick in a different archetype than they started it in.
+	ArchetypeMoves []ArchetypeMove `json:"archetypeMoves"`
+}
+
+// ComponentChange is a single component on a single entity.
+type ComponentChange struct {
+	EntityID  entity.ID `json:"entityId"`
+	Component string    `json:"component"`
+	// Value is the JSON encoded value of the component. It is empty for removals.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ArchetypeMove is an entity that moved from one archetype to another because components were added or removed.
+type ArchetypeMove struct {
+	EntityID entity.ID    `json:"entityId"`
+	From     archetype.ID `json:"from"`
+	To       archetype.ID `json:"to"`
+}
+
+// IsEmpty returns true if the diff contains no state changes.
+func (d *TickDiff) IsEmpty() bool {
+	return len(d.CreatedEntities) == 0 &&
+		len(d.RemovedEntities) == 0 &&
+		len(d.ComponentSets) == 0 &&
+		len(d.ComponentRemovals) == 0 &&
+		len(d.ArchetypeMoves) == 0
+}
@@ -0,0 +1,17 @@
+package store
+
+import "encoding/json"
+
+// EventStorage saves the events that were emitted during each tick, so clients that missed them can catch up.
+type EventStorage interface {
+	// AddEvent saves the given JSON encoded event as part of the current tick. The event is only saved if the tick's
+	// other state changes are saved.
+	AddEvent(event json.RawMessage)
+	// GetEvents returns the saved events that were emitted during or after the given tick, in the order they were
+	// emitted. The number of ticks read by a single call is limited, so if there are more saved events, nextTick is
+	// the tick to call GetEvents with next; otherwise it is 0. It only reads committed data, so it is safe to call
+	// while a tick is running.
+	GetEvents(fromTick uint64) (evts []json.RawMessage, nextTick uint64, err error)
+	// SetEventRetention sets the number of ticks that events are kept for. A retention of 0 keeps events forever.
+	SetEventRetention(ticks uint64)
+}
@@ -17,6 +17,11 @@ type Reader interface {
 	GetComponentForEntity(cType metadata.ComponentMetadata, id entity.ID) (any, error)
 	GetComponentForEntityInRawJSON(cType metadata.ComponentMetadata, id entity.ID) (json.RawMessage, error)
 
+	// One Component Many Entities
+	// GetComponentsForEntities returns the value of the given component for each of the given entities, in the same
+	// order. Values that are not in memory are fetched in a single round trip.
+	GetComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID) ([]any, error)
+
 	// Many Components One Entity
 	GetComponentTypesForEntity(id entity.ID) ([]metadata.ComponentMetadata, error)
 
@@ -27,6 +32,16 @@ type Reader interface {
 	// One Archetype Many Entities
 	GetEntitiesForArchID(archID archetype.ID) ([]entity.ID, error)
 
+	// One Relation Many Entities
+	// GetRelationSources returns the entities that refer to the target entity through the given relation component,
+	// sorted by entity ID.
+	GetRelationSources(cType metadata.ComponentMetadata, target entity.ID) ([]entity.ID, error)
+
+	// One Index Many Entities
+	// GetEntitiesByIndex returns the entities whose given indexed field of the given component matches the given value
+	// (see metadata.IndexKey), sorted by entity ID.
+	GetEntitiesByIndex(cType metadata.ComponentMetadata, field string, value any) ([]entity.ID, error)
+
 	// Misc
 	SearchFrom(filter filter.ComponentFilter, start int) *storage.ArchetypeIterator
 	ArchetypeCount() int
@@ -45,6 +60,10 @@ type Writer interface {
 	AddComponentToEntity(cType metadata.ComponentMetadata, id entity.ID) error
 	RemoveComponentFromEntity(cType metadata.ComponentMetadata, id entity.ID) error
 
+	// One Component Many Entities
+	// SetComponentsForEntities sets the given component of each of the given entities to the value at the same index.
+	SetComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID, values []any) error
+
 	// Misc
 	InjectLogger(logger *ecslog.Logger)
 	Close() error
@@ -56,12 +75,64 @@ type TickStorage interface {
 	StartNextTick(txs []transaction.ITransaction, queues *transaction.TxQueue) error
 	FinalizeTick() error
 	Recover(txs []transaction.ITransaction) (*transaction.TxQueue, error)
+	// GetTimestamp returns the timestamp of the last started tick, or 0 if no tick was started.
+	GetTimestamp() (uint64, error)
+	// AddSubmission saves the given data as the current tick's submission to the base shard. It is saved along with
+	// the tick's state changes.
+	AddSubmission(data []byte)
+	// GetSubmissions returns every saved submission that has not been removed, in tick order.
+	GetSubmissions() ([]Submission, error)
+	// RemoveSubmission deletes the submission of the given tick once it was submitted.
+	RemoveSubmission(tick uint64) error
+}
+
+// Submission is the data a tick has to submit to the base shard.
+type Submission struct {
+	Tick uint64
+	Data []byte
+}
+
+// SnapshotStorage allows the complete saved state to be exported and imported. This is used to save and restore world
+// snapshots.
+type SnapshotStorage interface {
+	// NextEntityID returns the ID that will be assigned to the next created entity.
+	NextEntityID() (entity.ID, error)
+	// RestoreState atomically saves the given state to an empty store.
+	RestoreState(state State) error
+}
+
+// State is the complete saved state of a store at the end of a specific tick.
+type State struct {
+	Tick         uint64
+	NextEntityID entity.ID
+	// Archetypes holds every archetype. The archetype at index i has an archetype.ID of i.
+	Archetypes []ArchetypeState
+	// Nonces holds the last used nonce of each signer address. It must only be set if the store also saves the nonces
+	// of the world.
+	Nonces map[string]uint64
+}
+
+// ArchetypeState is a set of components, along with the entities that currently have exactly that set of components.
+type ArchetypeState struct {
+	Components []metadata.ComponentMetadata
+	Entities   []EntityState
+}
+
+// EntityState is a single entity and the encoded values of each of its components. Values are in the same order as
+// the components of the entity's archetype.
+type EntityState struct {
+	ID     entity.ID
+	Values []json.RawMessage
 }
 
 // IManager represents all the methods required to track Component, Entity, and Archetype information
 // which powers the ECS storage layer.
 type IManager interface {
 	TickStorage
+	SnapshotStorage
+	DiffStorage
+	EventStorage
+	StateHashStorage
 	Reader
 	Writer
 	ToReadOnly() Reader
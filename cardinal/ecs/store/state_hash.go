@@ -0,0 +1,42 @@
+package store
+
+import (
+	"encoding/json"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/merkle"
+)
+
+// StateHashStorage saves a hash of the complete state at the end of each tick.
+type StateHashStorage interface {
+	// GetStateHash returns the state hash that was saved at the end of the given tick. nil is returned if no state
+	// hash was saved for the tick, or if it is older than the state hash retention. It only reads committed data, so
+	// it is safe to call while a tick is running.
+	GetStateHash(tick uint64) ([]byte, error)
+	// GetPendingStateHash returns the state hash of the current state, including changes that have not been
+	// committed yet.
+	GetPendingStateHash() ([]byte, error)
+	// GetComponentProof returns the value the given component had on the given entity at the end of the given tick,
+	// along with a proof that the value is part of the tick's state hash. Proofs can only be built for recently
+	// finalized ticks. It is safe to call while a tick is running.
+	GetComponentProof(cType metadata.ComponentMetadata, id entity.ID, tick uint64) (*ComponentProof, error)
+	// SetProofRetention sets the number of finalized ticks that proofs can be built for. A retention of 0 keeps every
+	// tick since the state was loaded.
+	SetProofRetention(ticks uint64)
+	// SetStateHashRetention sets the number of ticks that state hashes are kept for. A retention of 0 keeps the state
+	// hash of every tick.
+	SetStateHashRetention(ticks uint64)
+}
+
+// ComponentProof is the value of a single component on a single entity at the end of a tick, along with a proof that
+// the value is part of that tick's state hash. It can be checked with merkle.Verify.
+type ComponentProof struct {
+	Tick            uint64
+	StateHash       []byte
+	ComponentTypeID metadata.TypeID
+	EntityID        entity.ID
+	// Value is the component value exactly as it was hashed.
+	Value json.RawMessage
+	Proof *merkle.Proof
+}
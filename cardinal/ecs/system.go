@@ -1,3 +1,327 @@
 package ecs
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	ecslog "pkg.world.dev/world-engine/cardinal/ecs/log"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
+	"pkg.world.dev/world-engine/cardinal/events"
+)
+
 type System func(WorldContext) error
+
+var (
+	ErrComponentAccessNotDeclared = errors.New("system did not declare access to component")
+	ErrStructuralChangeNotAllowed = errors.New(
+		"systems that declare component access cannot create or remove entities, or add or remove components")
+)
+
+// SystemOption configures how a system is scheduled.
+type SystemOption func(sys *registeredSystem)
+
+// Reads declares that the system reads the given components. A system that declares its reads and writes may run at
+// the same time as other systems that don't write to the components it uses.
+func Reads(components ...metadata.Component) SystemOption {
+	return func(sys *registeredSystem) {
+		sys.declaresAccess = true
+		for _, comp := range components {
+			sys.reads[comp.Name()] = true
+		}
+	}
+}
+
+// Writes declares that the system sets the value of the given components. Writing a component implies reading it.
+func Writes(components ...metadata.Component) SystemOption {
+	return func(sys *registeredSystem) {
+		sys.declaresAccess = true
+		for _, comp := range components {
+			sys.writes[comp.Name()] = true
+		}
+	}
+}
+
+// registeredSystem is a system along with everything the world needs to know to run it.
+type registeredSystem struct {
+	fn     System
+	name   string
+	logger *ecslog.Logger
+	// randStream is the name of the system's random number stream. It is the system's name, followed by the number of
+	// systems that were registered with the same name before it, if there are any.
+	randStream string
+
+	phase  Phase
+	before []string
+	after  []string
+
+	// The system is skipped in ticks where its triggers are not met. interval is converted into everyNTicks, and
+	// txNames into txIDs, when the game state is loaded.
+	everyNTicks uint64
+	interval    time.Duration
+	txNames     []string
+	txIDs       []transaction.TypeID
+
+	// reads and writes are the names of the components the system declared it uses. A system that does not declare
+	// its access is exclusive: it may use any state, and it never runs at the same time as another system.
+	declaresAccess bool
+	reads          map[string]bool
+	writes         map[string]bool
+}
+
+func (s *registeredSystem) canRead(name string) bool {
+	return !s.declaresAccess || s.reads[name] || s.writes[name]
+}
+
+func (s *registeredSystem) canWrite(name string) bool {
+	return !s.declaresAccess || s.writes[name]
+}
+
+// conflictsWith returns true if the two systems must not run at the same time. This is the case when either system is
+// exclusive, or when either system writes a component that the other system uses.
+func (s *registeredSystem) conflictsWith(other *registeredSystem) bool {
+	if !s.declaresAccess || !other.declaresAccess {
+		return true
+	}
+	for name := range s.writes {
+		if other.reads[name] || other.writes[name] {
+			return true
+		}
+	}
+	for name := range other.writes {
+		if s.reads[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleSystems makes sure every declared component has been registered, puts the systems in the order given by
+// their phases and ordering constraints, and works out which earlier systems each system has to wait for. A system
+// waits for every system in an earlier phase, every system it was constrained to run after, and every earlier system
+// it conflicts with. systemDependencies is left nil when every system is exclusive, in which case the systems simply
+// run one after another.
+func (w *World) scheduleSystems() error {
+	w.systemDependencies = nil
+	runInParallel := false
+	for i := range w.systems {
+		sys := &w.systems[i]
+		for _, names := range []map[string]bool{sys.reads, sys.writes} {
+			for name := range names {
+				if _, ok := w.nameToComponent[name]; !ok {
+					return fmt.Errorf("system %q uses component %q which has not been registered", sys.name, name)
+				}
+			}
+		}
+		runInParallel = runInParallel || sys.declaresAccess
+	}
+	if err := w.resolveTriggers(); err != nil {
+		return err
+	}
+	constrainedAfter, err := w.orderSystems()
+	if err != nil {
+		return err
+	}
+	if !runInParallel {
+		// Every system is exclusive, so they just run one after another.
+		return nil
+	}
+	w.systemDependencies = make([][]int, len(w.systems))
+	for i := range w.systems {
+		for j := 0; j < i; j++ {
+			if w.systems[i].phase != w.systems[j].phase || constrainedAfter[i][j] ||
+				w.systems[i].conflictsWith(&w.systems[j]) {
+				w.systemDependencies[i] = append(w.systemDependencies[i], j)
+			}
+		}
+	}
+	return nil
+}
+
+// receiptChange is an error that was added to, or a result that was set on, the receipt of a transaction.
+type receiptChange struct {
+	hash   transaction.TxHash
+	err    error
+	result any
+}
+
+// systemSideEffects holds the events a system emitted and the receipt changes it made while it ran in parallel with
+// other systems.
+type systemSideEffects struct {
+	events   []*events.Event
+	receipts []receiptChange
+}
+
+// apply makes the receipt changes and emits the events. Each is applied in the order the system made them.
+func (s *systemSideEffects) apply(w *World) {
+	for _, change := range s.receipts {
+		if change.err != nil {
+			w.AddTransactionError(change.hash, change.err)
+		} else {
+			w.SetTransactionResult(change.hash, change.result)
+		}
+	}
+	for _, event := range s.events {
+		w.EmitEvent(event)
+	}
+}
+
+// runSystemsInParallel runs every system that is due on its own goroutine as soon as the systems it depends on have
+// finished. Once a system fails, systems that have not started yet are skipped. The error of the earliest registered
+// system that failed is returned. If a system panics, currentSystem is set to its name and the panic is repeated on
+// the calling goroutine.
+//
+// The events and receipt changes of each system are buffered, and applied in system order after every system has
+// finished, so the event log and the receipts do not depend on which system happened to finish first.
+func (w *World) runSystemsInParallel(txQueue *transaction.TxQueue, currentSystem *string) error {
+	done := make([]chan struct{}, len(w.systems))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	errs := make([]error, len(w.systems))
+	panics := make([]any, len(w.systems))
+	sideEffects := make([]systemSideEffects, len(w.systems))
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i := range w.systems {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+			for _, dep := range w.systemDependencies[i] {
+				<-done[dep]
+			}
+			sys := &w.systems[i]
+			if failed.Load() || !sys.isDue(w.tick, txQueue) {
+				return
+			}
+			defer func() {
+				if panicValue := recover(); panicValue != nil {
+					panics[i] = panicValue
+					failed.Store(true)
+				}
+			}()
+			if errs[i] = sys.fn(newSystemWorldContext(w, txQueue, sys, &sideEffects[i])); errs[i] != nil {
+				failed.Store(true)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range sideEffects {
+		sideEffects[i].apply(w)
+	}
+
+	for i := range w.systems {
+		if panics[i] != nil {
+			*currentSystem = w.systems[i].name
+			panic(panics[i])
+		}
+		if errs[i] != nil {
+			return errs[i]
+		}
+	}
+	return nil
+}
+
+// systemStoreManager limits a system that declared its component access to the components it declared. Structural
+// changes are not allowed because entity IDs and archetypes would depend on which of the parallel systems got to the
+// store first.
+type systemStoreManager struct {
+	store.IManager
+	system *registeredSystem
+}
+
+func (s *systemStoreManager) checkRead(cType metadata.ComponentMetadata) error {
+	if !s.system.canRead(cType.Name()) {
+		return fmt.Errorf("system %q cannot read %q: %w", s.system.name, cType.Name(), ErrComponentAccessNotDeclared)
+	}
+	return nil
+}
+
+func (s *systemStoreManager) GetComponentForEntity(cType metadata.ComponentMetadata, id entity.ID) (any, error) {
+	if err := s.checkRead(cType); err != nil {
+		return nil, err
+	}
+	return s.IManager.GetComponentForEntity(cType, id)
+}
+
+func (s *systemStoreManager) GetComponentForEntityInRawJSON(cType metadata.ComponentMetadata, id entity.ID) (
+	json.RawMessage, error) {
+	if err := s.checkRead(cType); err != nil {
+		return nil, err
+	}
+	return s.IManager.GetComponentForEntityInRawJSON(cType, id)
+}
+
+func (s *systemStoreManager) GetComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID) (
+	[]any, error) {
+	if err := s.checkRead(cType); err != nil {
+		return nil, err
+	}
+	return s.IManager.GetComponentsForEntities(cType, ids)
+}
+
+func (s *systemStoreManager) GetRelationSources(cType metadata.ComponentMetadata, target entity.ID) (
+	[]entity.ID, error) {
+	if err := s.checkRead(cType); err != nil {
+		return nil, err
+	}
+	return s.IManager.GetRelationSources(cType, target)
+}
+
+func (s *systemStoreManager) GetEntitiesByIndex(cType metadata.ComponentMetadata, field string, value any) (
+	[]entity.ID, error) {
+	if err := s.checkRead(cType); err != nil {
+		return nil, err
+	}
+	return s.IManager.GetEntitiesByIndex(cType, field, value)
+}
+
+func (s *systemStoreManager) checkWrite(cType metadata.ComponentMetadata) error {
+	if !s.system.canWrite(cType.Name()) {
+		return fmt.Errorf("system %q cannot write %q: %w", s.system.name, cType.Name(), ErrComponentAccessNotDeclared)
+	}
+	return nil
+}
+
+func (s *systemStoreManager) SetComponentForEntity(cType metadata.ComponentMetadata, id entity.ID, value any) error {
+	if err := s.checkWrite(cType); err != nil {
+		return err
+	}
+	return s.IManager.SetComponentForEntity(cType, id, value)
+}
+
+func (s *systemStoreManager) SetComponentsForEntities(cType metadata.ComponentMetadata, ids []entity.ID,
+	values []any) error {
+	if err := s.checkWrite(cType); err != nil {
+		return err
+	}
+	return s.IManager.SetComponentsForEntities(cType, ids, values)
+}
+
+func (s *systemStoreManager) RemoveEntity(entity.ID) error {
+	return ErrStructuralChangeNotAllowed
+}
+
+func (s *systemStoreManager) CreateEntity(...metadata.ComponentMetadata) (entity.ID, error) {
+	return 0, ErrStructuralChangeNotAllowed
+}
+
+func (s *systemStoreManager) CreateManyEntities(int, ...metadata.ComponentMetadata) ([]entity.ID, error) {
+	return nil, ErrStructuralChangeNotAllowed
+}
+
+func (s *systemStoreManager) AddComponentToEntity(metadata.ComponentMetadata, entity.ID) error {
+	return ErrStructuralChangeNotAllowed
+}
+
+func (s *systemStoreManager) RemoveComponentFromEntity(metadata.ComponentMetadata, entity.ID) error {
+	return ErrStructuralChangeNotAllowed
+}
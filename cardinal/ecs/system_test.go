@@ -0,0 +1,211 @@
+package ecs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	ecslog "pkg.world.dev/world-engine/cardinal/ecs/log"
+	"pkg.world.dev/world-engine/cardinal/events"
+)
+
+type Speed struct {
+	Value int
+}
+
+func (Speed) Name() string { return "speed" }
+
+type Armor struct {
+	Value int
+}
+
+func (Armor) Name() string { return "armor" }
+
+func TestSystemsWithDisjointAccessRunInParallel(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	assert.NilError(t, ecs.RegisterComponent[Armor](w))
+
+	// Each system waits for the other one to start. If the systems ran one after another, both would time out.
+	speedStarted := make(chan struct{})
+	armorStarted := make(chan struct{})
+	waitFor := func(ch chan struct{}) error {
+		select {
+		case <-ch:
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("systems did not run at the same time")
+		}
+	}
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		close(speedStarted)
+		return waitFor(armorStarted)
+	}, "speed", ecs.Writes(Speed{}))
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		close(armorStarted)
+		return waitFor(speedStarted)
+	}, "armor", ecs.Writes(Armor{}))
+	assert.NilError(t, w.LoadGameState())
+
+	assert.NilError(t, w.Tick(context.Background()))
+}
+
+func TestParallelSystemEventsAreSavedInSystemOrder(t *testing.T) {
+	logger := zerolog.Nop()
+	w := ecs.NewTestWorld(t, ecs.WithLoggingEventHub(&ecslog.Logger{Logger: &logger}))
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	assert.NilError(t, ecs.RegisterComponent[Armor](w))
+
+	// The armor system emits its event first, but the speed system was registered first.
+	armorEmitted := make(chan struct{})
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		select {
+		case <-armorEmitted:
+		case <-time.After(5 * time.Second):
+			return errors.New("systems did not run at the same time")
+		}
+		wCtx.EmitEvent(&events.Event{Message: "speed"})
+		return nil
+	}, "speed", ecs.Writes(Speed{}))
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		wCtx.EmitEvent(&events.Event{Message: "armor"})
+		close(armorEmitted)
+		return nil
+	}, "armor", ecs.Writes(Armor{}))
+	assert.NilError(t, w.LoadGameState())
+	assert.NilError(t, w.Tick(context.Background()))
+
+	saved, _, err := w.StoreManager().GetEvents(0)
+	assert.NilError(t, err)
+	var messages []string
+	for _, bz := range saved {
+		var event events.Event
+		assert.NilError(t, json.Unmarshal(bz, &event))
+		messages = append(messages, event.Message)
+	}
+	assert.DeepEqual(t, []string{"speed", "armor"}, messages)
+}
+
+func TestConflictingSystemsRunInRegistrationOrder(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	assert.NilError(t, ecs.RegisterComponent[Armor](w))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+	var id entity.ID
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		record("writer")
+		return component.UpdateComponent[Speed](wCtx, id, func(s *Speed) *Speed {
+			s.Value++
+			return s
+		})
+	}, "writer", ecs.Writes(Speed{}))
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		record("armor")
+		return nil
+	}, "armor", ecs.Writes(Armor{}))
+	var seenSpeed int
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		record("reader")
+		speed, err := component.GetComponent[Speed](wCtx, id)
+		if err != nil {
+			return err
+		}
+		seenSpeed = speed.Value
+		return nil
+	}, "reader", ecs.Reads(Speed{}))
+	assert.NilError(t, w.LoadGameState())
+
+	var err error
+	id, err = component.Create(ecs.NewWorldContext(w), Speed{}, Armor{})
+	assert.NilError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		order = nil
+		assert.NilError(t, w.Tick(context.Background()))
+		assert.Equal(t, i, seenSpeed)
+		assert.Equal(t, 3, len(order))
+		// The reader must always see the writer's change, but the armor system may run at any time.
+		writerIndex, readerIndex := -1, -1
+		for j, name := range order {
+			switch name {
+			case "writer":
+				writerIndex = j
+			case "reader":
+				readerIndex = j
+			}
+		}
+		assert.Check(t, writerIndex < readerIndex)
+	}
+}
+
+func TestSystemsCanOnlyUseDeclaredComponents(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	assert.NilError(t, ecs.RegisterComponent[Armor](w))
+
+	var id entity.ID
+	var readErr, writeErr, createErr, undeclaredReadErr error
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		_, readErr = component.GetComponent[Speed](wCtx, id)
+		writeErr = component.SetComponent[Speed](wCtx, id, &Speed{Value: 1})
+		_, undeclaredReadErr = component.GetComponent[Armor](wCtx, id)
+		_, createErr = component.Create(wCtx, Speed{})
+		return nil
+	}, "reader", ecs.Reads(Speed{}))
+	assert.NilError(t, w.LoadGameState())
+
+	var err error
+	id, err = component.Create(ecs.NewWorldContext(w), Speed{}, Armor{})
+	assert.NilError(t, err)
+	assert.NilError(t, w.Tick(context.Background()))
+
+	assert.NilError(t, readErr)
+	assert.ErrorIs(t, writeErr, ecs.ErrComponentAccessNotDeclared)
+	assert.ErrorIs(t, undeclaredReadErr, ecs.ErrComponentAccessNotDeclared)
+	assert.ErrorIs(t, createErr, ecs.ErrStructuralChangeNotAllowed)
+}
+
+func TestDeclaredComponentsMustBeRegistered(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	w.AddSystemWithOptions(func(ecs.WorldContext) error {
+		return nil
+	}, "armor", ecs.Writes(Armor{}))
+	assert.ErrorContains(t, w.LoadGameState(), "armor")
+}
+
+func TestParallelSystemErrorStopsTheTick(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	assert.NilError(t, ecs.RegisterComponent[Armor](w))
+
+	errBoom := errors.New("boom")
+	laterSystemRan := false
+	w.AddSystemWithOptions(func(ecs.WorldContext) error {
+		return errBoom
+	}, "fails", ecs.Writes(Speed{}))
+	w.AddSystemWithOptions(func(ecs.WorldContext) error {
+		laterSystemRan = true
+		return nil
+	}, "waits", ecs.Reads(Speed{}))
+	assert.NilError(t, w.LoadGameState())
+
+	assert.ErrorIs(t, w.Tick(context.Background()), errBoom)
+	assert.Check(t, !laterSystemRan)
+}
@@ -0,0 +1,174 @@
+package ecs
+
+import (
+	"sort"
+	"sync"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/cql"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/filter"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+	"pkg.world.dev/world-engine/cardinal/events"
+)
+
+// TickDiffHandler is called with the state changes of a tick after those changes have been committed to storage.
+type TickDiffHandler func(diff *store.TickDiff)
+
+// tickDiffSubscribers holds the handlers that were registered with SubscribeToTickDiffs. Handlers can be added and
+// removed from any goroutine.
+type tickDiffSubscribers struct {
+	mu       sync.Mutex
+	nextID   int
+	handlers map[int]TickDiffHandler
+}
+
+// SubscribeToTickDiffs registers a handler that will be called with the state changes of every tick, after the tick
+// has been successfully committed to storage. Handlers are called in the order they were registered on the goroutine
+// that is running the tick, so they should return quickly and must not modify the diff. The returned function removes
+// the subscription.
+func (w *World) SubscribeToTickDiffs(handler TickDiffHandler) (unsubscribe func()) {
+	subs := &w.tickDiffSubscribers
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	if subs.handlers == nil {
+		subs.handlers = map[int]TickDiffHandler{}
+	}
+	id := subs.nextID
+	subs.nextID++
+	subs.handlers[id] = handler
+	return func() {
+		subs.mu.Lock()
+		defer subs.mu.Unlock()
+		delete(subs.handlers, id)
+	}
+}
+
+// publishTickDiff sends the diff of the most recently finalized tick to every subscriber.
+func (w *World) publishTickDiff() {
+	subs := &w.tickDiffSubscribers
+	subs.mu.Lock()
+	ids := make([]int, 0, len(subs.handlers))
+	for id := range subs.handlers {
+		ids = append(ids, id)
+	}
+	handlers := make([]TickDiffHandler, 0, len(ids))
+	sort.Ints(ids)
+	for _, id := range ids {
+		handlers = append(handlers, subs.handlers[id])
+	}
+	subs.mu.Unlock()
+	if len(handlers) == 0 && w.eventHub == nil {
+		return
+	}
+
+	diff := w.entityStore.LastTickDiff()
+	if diff == nil {
+		return
+	}
+	for _, handler := range handlers {
+		handler(diff)
+	}
+	if w.eventHub != nil {
+		w.eventHub.PublishTickDiff(diff)
+	}
+}
+
+// ParseChangeFilter parses the given CQL expression into an events.ChangeFilter that reduces a TickDiff to the changes
+// of the entities that match the expression.
+func (w *World) ParseChangeFilter(cqlText string) (events.ChangeFilter, error) {
+	componentFilter, err := cql.Parse(cqlText, w.GetComponentByName)
+	if err != nil {
+		return nil, err
+	}
+	return func(diff *store.TickDiff) (*store.TickDiff, error) {
+		return w.FilterTickDiff(diff, componentFilter)
+	}, nil
+}
+
+// FilterTickDiff returns the part of the given diff that belongs to entities that match the given filter. An entity
+// matches if its components matched the filter at the start or at the end of the tick, so an entity that stops
+// matching the filter is still reported. nil is returned if no entity in the diff matches. FilterTickDiff reads the
+// world's current state, so it must be called before any further changes are made to the world.
+func (w *World) FilterTickDiff(diff *store.TickDiff, componentFilter filter.ComponentFilter) (*store.TickDiff, error) {
+	matches := map[entity.ID]bool{}
+
+	// Removed entities can no longer be looked up, but all of their components are listed in the removals.
+	removedComps := map[entity.ID][]metadata.ComponentMetadata{}
+	for _, id := range diff.RemovedEntities {
+		removedComps[id] = nil
+	}
+	for _, change := range diff.ComponentRemovals {
+		comps, ok := removedComps[change.EntityID]
+		if !ok {
+			continue
+		}
+		comp, err := w.GetComponentByName(change.Component)
+		if err != nil {
+			return nil, err
+		}
+		removedComps[change.EntityID] = append(comps, comp)
+	}
+	for id, comps := range removedComps {
+		matches[id] = componentFilter.MatchesComponents(comps)
+	}
+	// An entity that matched at the start of the tick is reported even if it no longer matches.
+	for _, move := range diff.ArchetypeMoves {
+		if componentFilter.MatchesComponents(w.StoreManager().GetComponentTypesForArchID(move.From)) {
+			matches[move.EntityID] = true
+		}
+	}
+	isMatch := func(id entity.ID) (bool, error) {
+		if match, ok := matches[id]; ok {
+			return match, nil
+		}
+		comps, err := w.StoreManager().GetComponentTypesForEntity(id)
+		if err != nil {
+			return false, err
+		}
+		matches[id] = componentFilter.MatchesComponents(comps)
+		return matches[id], nil
+	}
+
+	result := &store.TickDiff{Tick: diff.Tick}
+	var err error
+	if result.CreatedEntities, err = filterByEntity(diff.CreatedEntities,
+		func(id entity.ID) entity.ID { return id }, isMatch); err != nil {
+		return nil, err
+	}
+	if result.RemovedEntities, err = filterByEntity(diff.RemovedEntities,
+		func(id entity.ID) entity.ID { return id }, isMatch); err != nil {
+		return nil, err
+	}
+	if result.ComponentSets, err = filterByEntity(diff.ComponentSets,
+		func(c store.ComponentChange) entity.ID { return c.EntityID }, isMatch); err != nil {
+		return nil, err
+	}
+	if result.ComponentRemovals, err = filterByEntity(diff.ComponentRemovals,
+		func(c store.ComponentChange) entity.ID { return c.EntityID }, isMatch); err != nil {
+		return nil, err
+	}
+	if result.ArchetypeMoves, err = filterByEntity(diff.ArchetypeMoves,
+		func(m store.ArchetypeMove) entity.ID { return m.EntityID }, isMatch); err != nil {
+		return nil, err
+	}
+	if result.IsEmpty() {
+		return nil, nil //nolint:nilnil // no matching changes is not an error
+	}
+	return result, nil
+}
+
+// filterByEntity returns the items in src that belong to a matching entity.
+func filterByEntity[T any](src []T, getID func(T) entity.ID, isMatch func(entity.ID) (bool, error)) ([]T, error) {
+	dst := make([]T, 0)
+	for _, item := range src {
+		match, err := isMatch(getID(item))
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			dst = append(dst, item)
+		}
+	}
+	return dst, nil
+}
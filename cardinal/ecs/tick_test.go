@@ -15,9 +15,11 @@ import (
 	"github.com/rs/zerolog"
 	"pkg.world.dev/world-engine/cardinal/ecs"
 	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	"pkg.world.dev/world-engine/cardinal/ecs/internal/testutil"
 	"pkg.world.dev/world-engine/cardinal/ecs/log"
 	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
 )
 
 func TestTickHappyPath(t *testing.T) {
@@ -390,3 +392,77 @@ func TestCanRecoverTransactionsFromFailedSystemRun(t *testing.T) {
 		}
 	}
 }
+
+func TestTickDiffSubscribersReceiveEachTick(t *testing.T) {
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[EnergyComponent](world))
+	world.AddSystem(func(wCtx ecs.WorldContext) error {
+		_, err := component.Create(wCtx, EnergyComponent{Amt: int64(wCtx.CurrentTick())})
+		return err
+	})
+	assert.NilError(t, world.LoadGameState())
+
+	var diffs []*store.TickDiff
+	unsubscribe := world.SubscribeToTickDiffs(func(diff *store.TickDiff) {
+		diffs = append(diffs, diff)
+	})
+	ctx := context.Background()
+	assert.NilError(t, world.Tick(ctx))
+	assert.NilError(t, world.Tick(ctx))
+
+	assert.Equal(t, 2, len(diffs))
+	for i, diff := range diffs {
+		assert.Equal(t, uint64(i), diff.Tick)
+		assert.Equal(t, 1, len(diff.CreatedEntities))
+		assert.Equal(t, 1, len(diff.ComponentSets))
+		assert.Equal(t, "EnergyComponent", diff.ComponentSets[0].Component)
+	}
+
+	unsubscribe()
+	assert.NilError(t, world.Tick(ctx))
+	assert.Equal(t, 2, len(diffs))
+}
+
+func TestFilterTickDiffIncludesEntitiesThatStopMatching(t *testing.T) {
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[EnergyComponent](world))
+	assert.NilError(t, ecs.RegisterComponent[OwnableComponent](world))
+	assert.NilError(t, world.LoadGameState())
+	wCtx := ecs.NewWorldContext(world)
+	ids, err := component.CreateMany(wCtx, 4, EnergyComponent{}, OwnableComponent{})
+	assert.NilError(t, err)
+	otherID, err := component.Create(wCtx, OwnableComponent{})
+	assert.NilError(t, err)
+	ctx := context.Background()
+	assert.NilError(t, world.Tick(ctx))
+
+	// ids[0] still matches, ids[1] stops matching, ids[2] is removed and ids[3] is not changed.
+	assert.NilError(t, component.SetComponent[EnergyComponent](wCtx, ids[0], &EnergyComponent{Amt: 10}))
+	assert.NilError(t, component.RemoveComponentFrom[EnergyComponent](wCtx, ids[1]))
+	assert.NilError(t, world.Remove(ids[2]))
+	assert.NilError(t, component.SetComponent[OwnableComponent](wCtx, otherID, &OwnableComponent{Owner: "x"}))
+	assert.NilError(t, world.Tick(ctx))
+
+	changeFilter, err := world.ParseChangeFilter("CONTAINS(EnergyComponent)")
+	assert.NilError(t, err)
+	diff, err := changeFilter(world.StoreManager().LastTickDiff())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []entity.ID{ids[2]}, diff.RemovedEntities)
+	assert.Equal(t, 1, len(diff.ComponentSets))
+	assert.Equal(t, ids[0], diff.ComponentSets[0].EntityID)
+	assert.Equal(t, 1, len(diff.ArchetypeMoves))
+	assert.Equal(t, ids[1], diff.ArchetypeMoves[0].EntityID)
+	for _, removal := range diff.ComponentRemovals {
+		assert.Check(t, removal.EntityID == ids[1] || removal.EntityID == ids[2])
+	}
+
+	// A tick without any matching changes should not produce a diff.
+	assert.NilError(t, component.SetComponent[OwnableComponent](wCtx, otherID, &OwnableComponent{Owner: "y"}))
+	assert.NilError(t, world.Tick(ctx))
+	diff, err = changeFilter(world.StoreManager().LastTickDiff())
+	assert.NilError(t, err)
+	assert.Check(t, diff == nil)
+
+	_, err = world.ParseChangeFilter("CONTAINS(NotAComponent)")
+	assert.Check(t, err != nil)
+}
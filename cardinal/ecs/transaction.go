@@ -130,18 +130,17 @@ type TxData[In any] struct {
 }
 
 func (t *TransactionType[In, Out]) AddError(wCtx WorldContext, hash transaction.TxHash, err error) {
-	wCtx.GetWorld().AddTransactionError(hash, err)
+	wCtx.AddTransactionError(hash, err)
 }
 
 func (t *TransactionType[In, Out]) SetResult(wCtx WorldContext, hash transaction.TxHash, result Out) {
-	wCtx.GetWorld().SetTransactionResult(hash, result)
+	wCtx.SetTransactionResult(hash, result)
 }
 
 func (t *TransactionType[In, Out]) GetReceipt(wCtx WorldContext, hash transaction.TxHash) (
 	v Out, errs []error, ok bool,
 ) {
-	world := wCtx.GetWorld()
-	iface, errs, ok := world.GetTransactionReceipt(hash)
+	iface, errs, ok := wCtx.GetTransactionReceipt(hash)
 	if !ok {
 		return v, nil, false
 	}
@@ -9,6 +9,7 @@ import (
 type TxQueue struct {
 	m          txMap
 	txsInQueue int
+	timestamp  uint64
 	mux        *sync.Mutex
 }
 
@@ -76,6 +77,21 @@ func (t *TxQueue) CopyTransactions() *TxQueue {
 func (t *TxQueue) reset() {
 	t.m = txMap{}
 	t.txsInQueue = 0
+	t.timestamp = 0
+}
+
+// Timestamp returns the unix time, in milliseconds, at which the tick that processes these transactions was started.
+// It is 0 if the tick has not been started yet.
+func (t *TxQueue) Timestamp() uint64 {
+	return t.timestamp
+}
+
+// SetTimestamp sets the time at which the tick that processes these transactions was started. It is used to replay a
+// tick with the same timestamp it originally had.
+func (t *TxQueue) SetTimestamp(timestamp uint64) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.timestamp = timestamp
 }
 
 func (t *TxQueue) ForID(id TypeID) []TxAny {
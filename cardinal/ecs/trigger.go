@@ -0,0 +1,95 @@
+package ecs
+
+import (
+	"fmt"
+	"time"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
+)
+
+// DefaultTickInterval is the expected time between ticks, unless WithTickInterval is used.
+const DefaultTickInterval = time.Second
+
+// EveryNTicks makes the system only run in ticks whose tick number is a multiple of n. The tick number is used instead
+// of the wall clock so that every replay of the world runs the system in exactly the same ticks.
+func EveryNTicks(n uint64) SystemOption {
+	if n == 0 {
+		panic("EveryNTicks must be given a value of at least 1")
+	}
+	return func(sys *registeredSystem) {
+		sys.everyNTicks = n
+		sys.interval = 0
+	}
+}
+
+// Interval makes the system run about once every d. The interval is converted to a number of ticks using the world's
+// tick interval, rounding up, and then works just like EveryNTicks.
+func Interval(d time.Duration) SystemOption {
+	if d <= 0 {
+		panic("Interval must be given a positive duration")
+	}
+	return func(sys *registeredSystem) {
+		sys.interval = d
+		sys.everyNTicks = 0
+	}
+}
+
+// OnTransactions makes the system only run in ticks where at least one transaction of the given types is queued.
+func OnTransactions(txs ...transaction.ITransaction) SystemOption {
+	return func(sys *registeredSystem) {
+		for _, tx := range txs {
+			sys.txNames = append(sys.txNames, tx.Name())
+		}
+	}
+}
+
+// TickInterval returns the expected time between ticks. It is used to convert system intervals into ticks.
+func (w *World) TickInterval() time.Duration {
+	return w.tickInterval
+}
+
+// resolveTriggers converts the intervals of every system into a number of ticks, and looks up the IDs of the
+// transactions that trigger each system.
+func (w *World) resolveTriggers() error {
+	for i := range w.systems {
+		sys := &w.systems[i]
+		if sys.interval > 0 {
+			sys.everyNTicks = uint64((sys.interval + w.tickInterval - 1) / w.tickInterval)
+		}
+		sys.txIDs = sys.txIDs[:0]
+		for _, name := range sys.txNames {
+			tx, ok := w.getTransactionByName(name)
+			if !ok {
+				return fmt.Errorf("system %q is triggered by transaction %q which has not been registered",
+					sys.name, name)
+			}
+			sys.txIDs = append(sys.txIDs, tx.ID())
+		}
+	}
+	return nil
+}
+
+func (w *World) getTransactionByName(name string) (transaction.ITransaction, bool) {
+	for _, tx := range w.registeredTransactions {
+		if tx.Name() == name {
+			return tx, true
+		}
+	}
+	return nil, false
+}
+
+// isDue returns true if the system should run in the given tick. Every trigger the system was given must be met.
+func (s *registeredSystem) isDue(tick uint64, queue *transaction.TxQueue) bool {
+	if s.everyNTicks > 1 && tick%s.everyNTicks != 0 {
+		return false
+	}
+	if len(s.txIDs) == 0 {
+		return true
+	}
+	for _, id := range s.txIDs {
+		if len(queue.ForID(id)) > 0 {
+			return true
+		}
+	}
+	return false
+}
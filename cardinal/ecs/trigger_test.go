@@ -0,0 +1,79 @@
+package ecs_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+)
+
+// addTickRecordingSystem adds a system that appends the current tick to ticks whenever it runs.
+func addTickRecordingSystem(w *ecs.World, ticks *[]uint64, opts ...ecs.SystemOption) {
+	w.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		*ticks = append(*ticks, wCtx.CurrentTick())
+		return nil
+	}, "", opts...)
+}
+
+func TestSystemsCanRunEveryNTicks(t *testing.T) {
+	w := ecs.NewTestWorld(t, ecs.WithTickInterval(200*time.Millisecond))
+	var everyThird, everySecond []uint64
+	addTickRecordingSystem(w, &everyThird, ecs.EveryNTicks(3))
+	// 300ms is 1.5 ticks, which is rounded up to 2 ticks.
+	addTickRecordingSystem(w, &everySecond, ecs.Interval(300*time.Millisecond))
+	assert.NilError(t, w.LoadGameState())
+
+	for i := 0; i < 7; i++ {
+		assert.NilError(t, w.Tick(context.Background()))
+	}
+	assert.DeepEqual(t, []uint64{0, 3, 6}, everyThird)
+	assert.DeepEqual(t, []uint64{0, 2, 4, 6}, everySecond)
+}
+
+func TestSystemsCanRunOnlyWhenTransactionsAreQueued(t *testing.T) {
+	type MoveMsg struct {
+		X int
+	}
+	type MoveResult struct{}
+	w := ecs.NewTestWorld(t)
+	moveTx := ecs.NewTransactionType[MoveMsg, MoveResult]("move")
+	otherTx := ecs.NewTransactionType[MoveMsg, MoveResult]("other")
+	assert.NilError(t, w.RegisterTransactions(moveTx, otherTx))
+	var ticks []uint64
+	addTickRecordingSystem(w, &ticks, ecs.OnTransactions(moveTx))
+	assert.NilError(t, w.LoadGameState())
+
+	assert.NilError(t, w.Tick(context.Background()))
+	otherTx.AddToQueue(w, MoveMsg{})
+	assert.NilError(t, w.Tick(context.Background()))
+	moveTx.AddToQueue(w, MoveMsg{X: 1})
+	assert.NilError(t, w.Tick(context.Background()))
+	assert.NilError(t, w.Tick(context.Background()))
+
+	assert.DeepEqual(t, []uint64{2}, ticks)
+}
+
+func TestTriggerTransactionsMustBeRegistered(t *testing.T) {
+	type MoveMsg struct{}
+	w := ecs.NewTestWorld(t)
+	moveTx := ecs.NewTransactionType[MoveMsg, MoveMsg]("move")
+	var ticks []uint64
+	addTickRecordingSystem(w, &ticks, ecs.OnTransactions(moveTx))
+	assert.ErrorContains(t, w.LoadGameState(), "move")
+}
+
+func TestParallelSystemsAreSkippedWhenNotDue(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[Speed](w))
+	var ticks []uint64
+	addTickRecordingSystem(w, &ticks, ecs.Reads(Speed{}), ecs.EveryNTicks(2))
+	assert.NilError(t, w.LoadGameState())
+
+	for i := 0; i < 4; i++ {
+		assert.NilError(t, w.Tick(context.Background()))
+	}
+	assert.DeepEqual(t, []uint64{0, 2}, ticks)
+}
@@ -0,0 +1,242 @@
+package ecs
+
+import (
+	"bytes"
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/codec"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+)
+
+// typedQuery visits the entities that have a fixed set of components, along with the values of those components.
+type typedQuery struct {
+	wCtx      WorldContext
+	writeBack bool
+}
+
+// TypedQuery1 visits every entity that has component A.
+type TypedQuery1[A metadata.Component] struct {
+	typedQuery
+}
+
+// TypedQuery2 visits every entity that has components A and B.
+type TypedQuery2[A, B metadata.Component] struct {
+	typedQuery
+}
+
+// TypedQuery3 visits every entity that has components A, B and C.
+type TypedQuery3[A, B, C metadata.Component] struct {
+	typedQuery
+}
+
+// Query1 returns a query over every entity that has component A. Component values are fetched one archetype at a
+// time, so prefer it over a Search followed by a GetComponent call for every entity.
+func Query1[A metadata.Component](wCtx WorldContext) *TypedQuery1[A] {
+	return &TypedQuery1[A]{typedQuery{wCtx: wCtx}}
+}
+
+// Query2 returns a query over every entity that has components A and B.
+func Query2[A, B metadata.Component](wCtx WorldContext) *TypedQuery2[A, B] {
+	return &TypedQuery2[A, B]{typedQuery{wCtx: wCtx}}
+}
+
+// Query3 returns a query over every entity that has components A, B and C.
+func Query3[A, B, C metadata.Component](wCtx WorldContext) *TypedQuery3[A, B, C] {
+	return &TypedQuery3[A, B, C]{typedQuery{wCtx: wCtx}}
+}
+
+// WriteBack makes Each save the component values that the callback changed, after the callback returns.
+func (q *TypedQuery1[A]) WriteBack() *TypedQuery1[A] {
+	q.writeBack = true
+	return q
+}
+
+// WriteBack makes Each save the component values that the callback changed, after the callback returns.
+func (q *TypedQuery2[A, B]) WriteBack() *TypedQuery2[A, B] {
+	q.writeBack = true
+	return q
+}
+
+// WriteBack makes Each save the component values that the callback changed, after the callback returns.
+func (q *TypedQuery3[A, B, C]) WriteBack() *TypedQuery3[A, B, C] {
+	q.writeBack = true
+	return q
+}
+
+// Each calls the callback with every matching entity and a copy of its component value. Changes to the value are
+// discarded unless WriteBack was called. Return false from the callback to stop the iteration.
+func (q *TypedQuery1[A]) Each(callback func(entity.ID, *A) bool) error {
+	var a A
+	return q.each([]metadata.Component{a}, func(id entity.ID, values []any, originals [][]byte) ([]any, bool, error) {
+		pa, err := copyComponent[A](values[0], &originals[0])
+		if err != nil {
+			return nil, false, err
+		}
+		return []any{pa}, callback(id, pa), nil
+	})
+}
+
+// Each calls the callback with every matching entity and copies of its component values. Changes to the values are
+// discarded unless WriteBack was called. Return false from the callback to stop the iteration.
+func (q *TypedQuery2[A, B]) Each(callback func(entity.ID, *A, *B) bool) error {
+	var a A
+	var b B
+	return q.each([]metadata.Component{a, b}, func(id entity.ID, values []any, originals [][]byte) ([]any, bool, error) {
+		pa, err := copyComponent[A](values[0], &originals[0])
+		if err != nil {
+			return nil, false, err
+		}
+		pb, err := copyComponent[B](values[1], &originals[1])
+		if err != nil {
+			return nil, false, err
+		}
+		return []any{pa, pb}, callback(id, pa, pb), nil
+	})
+}
+
+// Each calls the callback with every matching entity and copies of its component values. Changes to the values are
+// discarded unless WriteBack was called. Return false from the callback to stop the iteration.
+func (q *TypedQuery3[A, B, C]) Each(callback func(entity.ID, *A, *B, *C) bool) error {
+	var a A
+	var b B
+	var c C
+	visit := func(id entity.ID, values []any, originals [][]byte) ([]any, bool, error) {
+		pa, err := copyComponent[A](values[0], &originals[0])
+		if err != nil {
+			return nil, false, err
+		}
+		pb, err := copyComponent[B](values[1], &originals[1])
+		if err != nil {
+			return nil, false, err
+		}
+		pc, err := copyComponent[C](values[2], &originals[2])
+		if err != nil {
+			return nil, false, err
+		}
+		return []any{pa, pb, pc}, callback(id, pa, pb, pc), nil
+	}
+	return q.each([]metadata.Component{a, b, c}, visit)
+}
+
+// typedQueryVisitor receives the stored values of an entity's components, and returns the pointers that were passed
+// to the user's callback along with the callback's result. It sets originals to the encoded values that the copies
+// were made from.
+type typedQueryVisitor func(id entity.ID, values []any, originals [][]byte) (pointers []any, cont bool, err error)
+
+// each visits the entities that have every one of the given components, one archetype at a time. The values of every
+// entity in an archetype are fetched before the first entity of the archetype is visited, and changed values are saved
+// once the archetype has been visited.
+func (q *typedQuery) each(comps []metadata.Component, visit typedQueryVisitor) error {
+	if q.writeBack && q.wCtx.IsReadOnly() {
+		return ErrCannotModifyStateWithReadOnlyContext
+	}
+	world := q.wCtx.GetWorld()
+	types := make([]metadata.ComponentMetadata, 0, len(comps))
+	for _, comp := range comps {
+		c, err := world.GetComponentByName(comp.Name())
+		if err != nil {
+			return fmt.Errorf("%s is not registered, please register it before querying", comp.Name())
+		}
+		types = append(types, c)
+	}
+	search, err := q.wCtx.NewSearch(Contains(comps...))
+	if err != nil {
+		return err
+	}
+	reader := q.wCtx.StoreReader()
+	for _, archID := range search.evaluateSearch(world.Namespace(), reader) {
+		ids, err := reader.GetEntitiesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		values := make([][]any, len(types))
+		for i, c := range types {
+			if values[i], err = reader.GetComponentsForEntities(c, ids); err != nil {
+				return err
+			}
+		}
+		changes := make([]typedQueryChanges, len(types))
+		entityValues := make([]any, len(types))
+		originals := make([][]byte, len(types))
+		for j, id := range ids {
+			for i := range types {
+				entityValues[i] = values[i][j]
+			}
+			pointers, cont, err := visit(id, entityValues, originals)
+			if err != nil {
+				return err
+			}
+			if q.writeBack {
+				if err = addChanges(changes, id, originals, pointers); err != nil {
+					return err
+				}
+			}
+			if !cont {
+				return q.saveChanges(types, changes)
+			}
+		}
+		if err = q.saveChanges(types, changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typedQueryChanges is the entities whose value of a single component was changed, along with the new values.
+type typedQueryChanges struct {
+	ids    []entity.ID
+	values []any
+}
+
+// addChanges adds the values that the callback changed to the changes of each component. A value is changed if its
+// encoding differs from the encoding it was copied from.
+func addChanges(changes []typedQueryChanges, id entity.ID, originals [][]byte, pointers []any) error {
+	for i := range changes {
+		bz, err := codec.Encode(pointers[i])
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(originals[i], bz) {
+			continue
+		}
+		changes[i].ids = append(changes[i].ids, id)
+		changes[i].values = append(changes[i].values, pointers[i])
+	}
+	return nil
+}
+
+// saveChanges sets the changed values of each component.
+func (q *typedQuery) saveChanges(types []metadata.ComponentMetadata, changes []typedQueryChanges) error {
+	for i, c := range types {
+		if len(changes[i].ids) == 0 {
+			continue
+		}
+		if err := q.wCtx.StoreManager().SetComponentsForEntities(c, changes[i].ids, changes[i].values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyComponent returns a pointer to a deep copy of a stored component value, which is either a T or a *T, and sets
+// original to the encoded value. The copy is decoded from the encoded value, so the callback cannot change the stored
+// value in place, not even through a slice or map field.
+func copyComponent[T metadata.Component](value any, original *[]byte) (*T, error) {
+	switch value.(type) {
+	case T, *T:
+	default:
+		var t T
+		return nil, fmt.Errorf("type assertion for component failed: %v to %s", value, t.Name())
+	}
+	bz, err := codec.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	c, err := codec.Decode[T](bz)
+	if err != nil {
+		return nil, err
+	}
+	*original = bz
+	return &c, nil
+}
@@ -0,0 +1,110 @@
+package ecs_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+)
+
+type InventoryComponent struct {
+	Items []int
+}
+
+func (InventoryComponent) Name() string {
+	return "inventory"
+}
+
+func TestTypedQuery(t *testing.T) {
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[EnergyComponent](world))
+	assert.NilError(t, ecs.RegisterComponent[HealthComponent](world))
+	assert.NilError(t, ecs.RegisterComponent[OwnableComponent](world))
+	assert.NilError(t, world.LoadGameState())
+	wCtx := ecs.NewWorldContext(world)
+
+	// The matching entities are spread over two archetypes.
+	ids, err := component.CreateMany(wCtx, 3, EnergyComponent{Amt: 10}, HealthComponent{HP: 1})
+	assert.NilError(t, err)
+	owned, err := component.Create(wCtx, EnergyComponent{Amt: 10}, HealthComponent{HP: 2}, OwnableComponent{})
+	assert.NilError(t, err)
+	ids = append(ids, owned)
+	_, err = component.Create(wCtx, EnergyComponent{Amt: 10})
+	assert.NilError(t, err)
+
+	visited := map[entity.ID]int{}
+	assert.NilError(t, ecs.Query2[EnergyComponent, HealthComponent](wCtx).Each(
+		func(id entity.ID, energy *EnergyComponent, health *HealthComponent) bool {
+			visited[id] = health.HP
+			// Without WriteBack, changes are discarded.
+			energy.Amt = 0
+			return true
+		}))
+	assert.DeepEqual(t, map[entity.ID]int{ids[0]: 1, ids[1]: 1, ids[2]: 1, owned: 2}, visited)
+	energy, err := component.GetComponent[EnergyComponent](wCtx, owned)
+	assert.NilError(t, err)
+	assert.Equal(t, int64(10), energy.Amt)
+
+	assert.NilError(t, ecs.Query1[EnergyComponent](wCtx).WriteBack().Each(
+		func(id entity.ID, energy *EnergyComponent) bool {
+			energy.Amt += 5
+			return true
+		}))
+	for _, id := range ids {
+		energy, err = component.GetComponent[EnergyComponent](wCtx, id)
+		assert.NilError(t, err)
+		assert.Equal(t, int64(15), energy.Amt)
+	}
+
+	count := 0
+	assert.NilError(t, ecs.Query3[EnergyComponent, HealthComponent, OwnableComponent](wCtx).Each(
+		func(id entity.ID, _ *EnergyComponent, _ *HealthComponent, _ *OwnableComponent) bool {
+			assert.Equal(t, owned, id)
+			count++
+			return true
+		}))
+	assert.Equal(t, 1, count)
+
+	count = 0
+	assert.NilError(t, ecs.Query1[HealthComponent](wCtx).Each(func(entity.ID, *HealthComponent) bool {
+		count++
+		return count != 2
+	}))
+	assert.Equal(t, 2, count)
+
+	readOnly := ecs.NewReadOnlyWorldContext(world)
+	err = ecs.Query1[HealthComponent](readOnly).WriteBack().Each(func(entity.ID, *HealthComponent) bool {
+		return true
+	})
+	assert.ErrorIs(t, err, ecs.ErrCannotModifyStateWithReadOnlyContext)
+}
+
+func TestTypedQueryCopiesSliceFields(t *testing.T) {
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[InventoryComponent](world))
+	assert.NilError(t, world.LoadGameState())
+	wCtx := ecs.NewWorldContext(world)
+	id, err := component.Create(wCtx, InventoryComponent{Items: []int{1, 2}})
+	assert.NilError(t, err)
+
+	// Without WriteBack, changes to the elements of a slice are discarded as well.
+	assert.NilError(t, ecs.Query1[InventoryComponent](wCtx).Each(func(_ entity.ID, inv *InventoryComponent) bool {
+		inv.Items[0] = 10
+		return true
+	}))
+	inv, err := component.GetComponent[InventoryComponent](wCtx, id)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []int{1, 2}, inv.Items)
+
+	assert.NilError(t, ecs.Query1[InventoryComponent](wCtx).WriteBack().Each(
+		func(_ entity.ID, inv *InventoryComponent) bool {
+			inv.Items[0] = 10
+			return true
+		}))
+	inv, err = component.GetComponent[InventoryComponent](wCtx, id)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []int{10, 2}, inv.Items)
+}
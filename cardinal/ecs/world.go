@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"runtime"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"pkg.world.dev/world-engine/cardinal/ecs/codec"
 	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	ecslog "pkg.world.dev/world-engine/cardinal/ecs/log"
@@ -41,14 +43,19 @@ type World struct {
 	namespace                Namespace
 	nonceStore               storage.NonceStorage
 	entityStore              store.IManager
-	systems                  []System
-	systemLoggers            []*ecslog.Logger
-	systemNames              []string
+	systems                  []registeredSystem
+	systemDependencies       [][]int
+	phases                   []Phase
+	tickInterval             time.Duration
+	tickSeed                 randSeed
+	timestamp                uint64
 	tick                     uint64
 	nameToComponent          map[string]metadata.ComponentMetadata
 	registeredComponents     []metadata.ComponentMetadata
+	registeredRelations      []metadata.ComponentMetadata
 	registeredTransactions   []transaction.ITransaction
 	registeredQueries        []IQuery
+	registeredEvents         []IEvent
 	isComponentsRegistered   bool
 	isTransactionsRegistered bool
 	stateIsLoaded            bool
@@ -59,10 +66,20 @@ type World struct {
 
 	receiptHistory *receipt.History
 
-	chain shard.QueryAdapter
+	chain shard.Adapter
+	// txLog receives the transactions of every tick. See WithTransactionLog.
+	txLog *json.Encoder
+	// afterSystem is called after each system runs. It is used by VerifyReplay to find the system that caused a
+	// divergence.
+	afterSystem func(systemName string) error
 	// isRecovering indicates that the world is recovering from the DA layer.
 	// this is used to prevent ticks from submitting duplicate transactions the DA layer.
 	isRecovering bool
+	// startsNewEpoch indicates that the next tick is an epoch even if it has no transactions (see Tick). It is set when
+	// the world is created and after RecoverFromChain.
+	startsNewEpoch bool
+	// submitMu makes sure queued epochs are only submitted by one caller of SubmitQueuedEpochs at a time.
+	submitMu sync.Mutex
 
 	Logger *ecslog.Logger
 
@@ -72,6 +89,11 @@ type World struct {
 	nextComponentID metadata.TypeID
 
 	eventHub events.EventHub
+
+	tickDiffSubscribers tickDiffSubscribers
+
+	// handshakeMu makes checking and saving the nonce of an event handshake atomic.
+	handshakeMu sync.Mutex
 }
 
 var (
@@ -90,9 +112,30 @@ func (w *World) SetEventHub(eventHub events.EventHub) {
 }
 
 func (w *World) EmitEvent(event *events.Event) {
+	if event.PersonaTag != "" && event.SignerAddress == "" {
+		// The signer of the target persona is also allowed to receive the event. It is looked up now because the
+		// event hub can't safely read the world's state.
+		if signerAddress, err := w.GetSignerForPersonaTag(event.PersonaTag, 0); err == nil {
+			event.SignerAddress = signerAddress
+		}
+	}
+	event.Tick = w.tick
+	// The event is saved to the store along with the rest of the tick, so clients can replay it later.
+	if bz, err := json.Marshal(event); err != nil {
+		w.Logger.Error().Err(err).Msg("failed to save event")
+	} else {
+		w.entityStore.AddEvent(bz)
+	}
 	w.eventHub.EmitEvent(event)
 }
 
+// EmitEventTo emits an event that is only sent to clients that have authenticated as the given persona (or as its
+// signer).
+func (w *World) EmitEventTo(personaTag string, event *events.Event) {
+	event.PersonaTag = personaTag
+	w.EmitEvent(event)
+}
+
 func (w *World) FlushEvents() {
 	w.eventHub.FlushEvents()
 }
@@ -128,6 +171,12 @@ func (w *World) AddSystems(systems ...System) {
 }
 
 func (w *World) AddSystemWithName(system System, functionName string) {
+	w.AddSystemWithOptions(system, functionName)
+}
+
+// AddSystemWithOptions adds a system that is scheduled according to the given options. If functionName is empty, the
+// name of the system function is used. The name is also how other systems refer to this one in Before and After.
+func (w *World) AddSystemWithOptions(system System, functionName string, opts ...SystemOption) {
 	if w.stateIsLoaded {
 		panic("cannot register systems after loading game state")
 	}
@@ -135,13 +184,32 @@ func (w *World) AddSystemWithName(system System, functionName string) {
 		functionName = filepath.Base(runtime.FuncForPC(reflect.ValueOf(system).Pointer()).Name())
 	}
 	sysLogger := w.Logger.CreateSystemLogger(functionName)
-	w.systemLoggers = append(w.systemLoggers, &sysLogger)
-	w.systemNames = append(w.systemNames, functionName)
+	sys := registeredSystem{
+		fn:         system,
+		name:       functionName,
+		logger:     &sysLogger,
+		randStream: functionName,
+		phase:      Update,
+		reads:      map[string]bool{},
+		writes:     map[string]bool{},
+	}
+	sameName := 0
+	for _, other := range w.systems {
+		if other.name == functionName {
+			sameName++
+		}
+	}
+	if sameName > 0 {
+		sys.randStream = fmt.Sprintf("%s#%d", functionName, sameName)
+	}
+	for _, opt := range opts {
+		opt(&sys)
+	}
 	// appends registeredSystem into the member system list in world.
-	w.systems = append(w.systems, system)
+	w.systems = append(w.systems, sys)
 }
 
-func RegisterComponent[T metadata.Component](world *World) error {
+func RegisterComponent[T metadata.Component](world *World, opts ...ComponentOption) error {
 	if world.stateIsLoaded {
 		panic("cannot register components after loading game state")
 	}
@@ -151,6 +219,11 @@ func RegisterComponent[T metadata.Component](world *World) error {
 		return fmt.Errorf("component with name '%s' is already registered", t.Name())
 	}
 	c := metadata.NewComponentMetadata[T]()
+	for _, opt := range opts {
+		if err = opt(c); err != nil {
+			return err
+		}
+	}
 	err = c.SetID(world.nextComponentID)
 	if err != nil {
 		return err
@@ -162,8 +235,8 @@ func RegisterComponent[T metadata.Component](world *World) error {
 	return nil
 }
 
-func MustRegisterComponent[T metadata.Component](world *World) {
-	err := RegisterComponent[T](world)
+func MustRegisterComponent[T metadata.Component](world *World, opts ...ComponentOption) {
+	err := RegisterComponent[T](world, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -249,7 +322,9 @@ func NewWorld(nonceStore storage.NonceStorage, entityStore store.IManager, opts
 		entityStore:       entityStore,
 		namespace:         "world",
 		tick:              0,
-		systems:           make([]System, 0),
+		systems:           make([]registeredSystem, 0),
+		phases:            defaultPhases(),
+		tickInterval:      DefaultTickInterval,
 		nameToComponent:   make(map[string]metadata.ComponentMetadata),
 		txQueue:           transaction.NewTxQueue(),
 		Logger:            logger,
@@ -257,10 +332,12 @@ func NewWorld(nonceStore storage.NonceStorage, entityStore store.IManager, opts
 		endGameLoopCh:     make(chan bool),
 		nextComponentID:   1,
 		evmTxReceipts:     make(map[string]EVMTxReceipt),
+		startsNewEpoch:    true,
 	}
 	w.isGameLoopRunning.Store(false)
-	w.AddSystems(RegisterPersonaSystem, AuthorizePersonaAddressSystem)
-	err := RegisterComponent[SignerComponent](w)
+	w.AddSystemWithOptions(RegisterPersonaSystem, "", InPhase(PreUpdate))
+	w.AddSystemWithOptions(AuthorizePersonaAddressSystem, "", InPhase(PreUpdate))
+	err := RegisterComponent[SignerComponent](w, signerComponentIndexes...)
 	if err != nil {
 		return nil, err
 	}
@@ -281,9 +358,10 @@ func (w *World) ReceiptHistorySize() uint64 {
 	return w.receiptHistory.Size()
 }
 
-// Remove removes the given Entity from the world.
+// Remove removes the given Entity from the world. An error is returned if another entity refers to it through a
+// relation; use RemoveEntity to remove or detach those entities too.
 func (w *World) Remove(id entity.ID) error {
-	return w.StoreManager().RemoveEntity(id)
+	return w.removeEntity(w.StoreManager(), id, RemoveRestrict)
 }
 
 // ConsumeEVMTxResult consumes a tx result from an EVM originated Cardinal transaction.
@@ -320,8 +398,14 @@ const (
 )
 
 // Tick performs one game tick. This consists of taking a snapshot of all pending transactions, then calling
-// each System in turn with the snapshot of transactions.
-func (w *World) Tick(_ context.Context) error {
+// each System that is due with the snapshot of transactions. Systems that declared their component access run at the
+// same time as the systems they don't conflict with.
+//
+// A tick with transactions, and the first tick after the world is created or recovered from the base shard, is an
+// epoch. If the world has a base shard adapter, epochs are saved along with the tick and submitted by
+// SubmitQueuedEpochs. Epochs are timestamped with the current time, and every other tick with the timestamp of the
+// previous tick plus the tick interval, so RecoverFromChain can rebuild the timestamp of every tick.
+func (w *World) Tick(ctx context.Context) error {
 	nullSystemName := "No system is running."
 	nameOfCurrentRunningSystem := nullSystemName
 	defer func() {
@@ -337,19 +421,50 @@ func (w *World) Tick(_ context.Context) error {
 		return errors.New("must load state before first tick")
 	}
 	txQueue := w.txQueue.CopyTransactions()
+	// A tick that is being recovered already has its original timestamp.
+	isNewTick := txQueue.Timestamp() == 0 && !w.isRecovering
+	epochTxs := w.epochTransactions(txQueue)
+	isEpoch := w.startsNewEpoch || len(epochTxs) > 0
+	if isNewTick {
+		txQueue.SetTimestamp(w.nextTimestamp(startTime, isEpoch))
+	}
+	w.timestamp = txQueue.Timestamp()
 
 	if err := w.TickStore().StartNextTick(w.registeredTransactions, txQueue); err != nil {
 		return err
 	}
+	if w.chain != nil && !w.isRecovering && isEpoch {
+		if err := w.addSubmission(epochTxs); err != nil {
+			return err
+		}
+	}
+	if isNewTick && w.txLog != nil {
+		w.logTick(txQueue)
+	}
+	w.tickSeed = w.newTickSeed(w.tick, txQueue)
 
-	for i, sys := range w.systems {
-		nameOfCurrentRunningSystem = w.systemNames[i]
-		wCtx := NewWorldContextForTick(w, txQueue, w.systemLoggers[i])
-		err := sys(wCtx)
-		nameOfCurrentRunningSystem = nullSystemName
-		if err != nil {
+	if w.systemDependencies != nil && w.afterSystem == nil {
+		if err := w.runSystemsInParallel(txQueue, &nameOfCurrentRunningSystem); err != nil {
 			return err
 		}
+	} else {
+		for i := range w.systems {
+			sys := &w.systems[i]
+			if !sys.isDue(w.tick, txQueue) {
+				continue
+			}
+			nameOfCurrentRunningSystem = sys.name
+			err := sys.fn(newSystemWorldContext(w, txQueue, sys, nil))
+			nameOfCurrentRunningSystem = nullSystemName
+			if err != nil {
+				return err
+			}
+			if w.afterSystem != nil {
+				if err = w.afterSystem(sys.name); err != nil {
+					return err
+				}
+			}
+		}
 	}
 	if w.eventHub != nil {
 		// world can be optionally loaded with or without an eventHub. If there is one, on every tick it must flush events.
@@ -358,7 +473,9 @@ func (w *World) Tick(_ context.Context) error {
 	if err := w.TickStore().FinalizeTick(); err != nil {
 		return err
 	}
+	w.publishTickDiff()
 	w.setEvmResults(txQueue.GetEVMTxs())
+	w.startsNewEpoch = false
 	w.tick++
 	w.receiptHistory.NextTick()
 	elapsedTime := time.Since(startTime)
@@ -378,6 +495,127 @@ func (w *World) Tick(_ context.Context) error {
 	return nil
 }
 
+// nextTimestamp returns the timestamp of a new tick. Only epochs are stored on the base shard, so only epochs use the
+// current time (but never go back in time); every other tick is exactly one tick interval after the previous tick. This
+// way the timestamp of every tick can be derived from the epochs when the world is recovered.
+func (w *World) nextTimestamp(now time.Time, isEpoch bool) uint64 {
+	if isEpoch {
+		return max(uint64(now.UnixMilli()), w.timestamp)
+	}
+	return w.timestamp + uint64(w.tickInterval.Milliseconds())
+}
+
+// epochTransactions returns the transactions of the tick that have to be stored on the base shard, in the order they
+// are run. Transactions that originated from the EVM are skipped, as they are already stored on the base shard.
+func (w *World) epochTransactions(queue *transaction.TxQueue) []shard.EpochTransaction {
+	var txs []shard.EpochTransaction
+	for _, tx := range w.registeredTransactions {
+		for _, txData := range queue.ForID(tx.ID()) {
+			if txData.EVMSourceTxHash != "" {
+				continue
+			}
+			txs = append(txs, shard.EpochTransaction{TxID: uint64(tx.ID()), Tx: txData.Sig})
+		}
+	}
+	return txs
+}
+
+// addSubmission saves the current tick as an epoch that has to be submitted to the base shard. The epoch is saved
+// along with the rest of the tick, and submitted by SubmitQueuedEpochs once the tick is done, so the tick never waits
+// for (or fails because of) the base shard.
+//
+// The epoch is saved before the tick runs, so it carries the state hash at the end of the previous tick rather than the
+// hash of the state it produces.
+func (w *World) addSubmission(txs []shard.EpochTransaction) error {
+	prevStateHash, err := w.prevStateHash()
+	if err != nil {
+		return err
+	}
+	bz, err := codec.Encode(shard.Epoch{
+		Namespace:     w.Namespace().String(),
+		Epoch:         w.tick,
+		Timestamp:     w.timestamp,
+		PrevStateHash: prevStateHash,
+		Txs:           txs,
+	})
+	if err != nil {
+		return err
+	}
+	w.TickStore().AddSubmission(bz)
+	return nil
+}
+
+// SubmitQueuedEpochs submits every epoch that was saved by a finished tick, but not yet submitted to the base shard, in
+// tick order. Each epoch is removed from the queue once it was submitted. It stops at the first error, and the
+// remaining epochs are submitted by the next call. Submitting an epoch replaces any transactions that were submitted
+// for it before, so an epoch that was submitted right before the world stopped is not duplicated when it is submitted
+// again. The game loop calls this in the background after every tick.
+func (w *World) SubmitQueuedEpochs(ctx context.Context) error {
+	if w.chain == nil {
+		return nil
+	}
+	w.submitMu.Lock()
+	defer w.submitMu.Unlock()
+	submissions, err := w.TickStore().GetSubmissions()
+	if err != nil {
+		return err
+	}
+	for _, submission := range submissions {
+		epoch, err := codec.Decode[shard.Epoch](submission.Data)
+		if err != nil {
+			return err
+		}
+		if err = w.chain.SubmitEpoch(ctx, &epoch); err != nil {
+			return fmt.Errorf("failed to submit epoch %d to the base shard: %w", submission.Tick, err)
+		}
+		if err = w.TickStore().RemoveSubmission(submission.Tick); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	submitRetryDelay    = 100 * time.Millisecond
+	maxSubmitRetryDelay = 10 * time.Second
+)
+
+// submitEpochs calls SubmitQueuedEpochs every time a tick is done, until done is closed. Failed submissions are
+// retried, waiting twice as long before each retry, so the base shard being unavailable only delays the submissions.
+func (w *World) submitEpochs(ctx context.Context, tickDone <-chan struct{}, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-tickDone:
+		}
+		for delay := submitRetryDelay; ; delay = min(delay*2, maxSubmitRetryDelay) {
+			err := w.SubmitQueuedEpochs(ctx)
+			if err == nil {
+				break
+			}
+			w.Logger.Warn().Err(err).Msgf("failed to submit epochs to the base shard, retrying in %s", delay)
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}
+}
+
+// prevStateHash returns the state hash at the end of the previous tick, or nil if there is none.
+func (w *World) prevStateHash() ([]byte, error) {
+	if w.tick == 0 {
+		return nil, nil
+	}
+	return w.entityStore.GetStateHash(w.tick - 1)
+}
+
 type EVMTxReceipt struct {
 	ABIResult []byte
 	Errs      []error
@@ -426,12 +664,24 @@ func (w *World) StartGameLoop(ctx context.Context, tickStart <-chan time.Time, t
 		w.Logger.Warn().Msg("No systems registered.")
 	}
 
+	// Epochs left over from before the world was started are submitted right away.
+	submitCh := make(chan struct{}, 1)
+	submitCh <- struct{}{}
+	submitDone := make(chan struct{})
+	if w.chain != nil {
+		go w.submitEpochs(ctx, submitCh, submitDone)
+	}
+
 	go func() {
 		tickTheWorld := func() {
 			currTick := w.CurrentTick()
 			if err := w.Tick(ctx); err != nil {
 				w.Logger.Panic().Err(err).Msg("Error running Tick in Game Loop.")
 			}
+			select {
+			case submitCh <- struct{}{}:
+			default:
+			}
 			if tickDone != nil {
 				tickDone <- currTick
 			}
@@ -449,6 +699,7 @@ func (w *World) StartGameLoop(ctx context.Context, tickStart <-chan time.Time, t
 				break loop
 			}
 		}
+		close(submitDone)
 		w.isGameLoopRunning.Store(false)
 	}()
 }
@@ -506,8 +757,10 @@ func (w *World) recoverGameState() (recoveredTxs *transaction.TxQueue, err error
 	w.tick = end
 	// We successfully completed the last tick. Everything is fine
 	if start == end {
+		// The timestamp of the next tick is derived from the timestamp of the last tick.
+		w.timestamp, err = w.TickStore().GetTimestamp()
 		//nolint:nilnil // its ok.
-		return nil, nil
+		return nil, err
 	}
 	return w.TickStore().Recover(w.registeredTransactions)
 }
@@ -523,7 +776,7 @@ func (w *World) LoadGameState() error {
 	}
 
 	if !w.isComponentsRegistered {
-		err := RegisterComponent[SignerComponent](w)
+		err := RegisterComponent[SignerComponent](w, signerComponentIndexes...)
 		if err != nil {
 			return err
 		}
@@ -532,6 +785,15 @@ func (w *World) LoadGameState() error {
 	if err := w.entityStore.RegisterComponents(w.registeredComponents); err != nil {
 		return err
 	}
+	for _, c := range w.registeredComponents {
+		if metadata.IsRelation(c) {
+			w.registeredRelations = append(w.registeredRelations, c)
+		}
+	}
+
+	if err := w.scheduleSystems(); err != nil {
+		return err
+	}
 
 	w.stateIsLoaded = true
 	recoveredTxs, err := w.recoverGameState()
@@ -553,65 +815,47 @@ func (w *World) LoadGameState() error {
 // RecoverFromChain will attempt to recover the state of the world based on historical transaction data.
 // The function puts the world in a recovery state, and then queries all transaction batches under the world's
 // namespace. The function will continuously ask the EVM base shard for batches, and run ticks for each batch returned.
-//
-//nolint:gocognit
+// If the world already has state (e.g. it was loaded from storage or restored from a snapshot), only the batches
+// from the world's current tick onward are requested, so the world catches up to the chain without being wiped.
 func (w *World) RecoverFromChain(ctx context.Context) error {
 	if w.chain == nil {
 		return fmt.Errorf("chain adapter was nil. " +
 			"be sure to use the `WithAdapter` option when creating the world")
 	}
-	if w.tick > 0 {
-		return fmt.Errorf("world recovery should not occur in a world with existing state. please verify all " +
-			"state has been cleared before running recovery")
-	}
-
 	w.isRecovering = true
 	defer func() {
 		w.isRecovering = false
+		w.startsNewEpoch = true
 	}()
-	namespace := w.Namespace().String()
+	// every tick before startTick has already been run, so there is no need to fetch those batches again.
+	startTick := w.CurrentTick()
+	return queryEpochs(ctx, w.chain, w.Namespace().String(), startTick, func(epoch *types.Epoch) error {
+		// base shards that don't support StartEpoch will return every batch. skip the ones we've already run.
+		if epoch.Epoch < startTick {
+			return nil
+		}
+		return w.replayEpoch(ctx, epoch)
+	})
+}
+
+// queryEpochs calls fn for every epoch the base shard has stored for the namespace, starting at startEpoch. The
+// function will continuously ask the EVM base shard for batches until there are none left.
+func queryEpochs(ctx context.Context, chain shard.QueryAdapter, namespace string, startEpoch uint64,
+	fn func(*types.Epoch) error) error {
 	var nextKey []byte
 	for {
-		res, err := w.chain.QueryTransactions(ctx, &types.QueryTransactionsRequest{
+		res, err := chain.QueryTransactions(ctx, &types.QueryTransactionsRequest{
 			Namespace: namespace,
 			Page: &types.PageRequest{
 				Key: nextKey,
 			},
+			StartEpoch: startEpoch,
 		})
 		if err != nil {
 			return err
 		}
-		for _, tickedTxs := range res.Epochs {
-			target := tickedTxs.Epoch
-			// tick up to target
-			if target < w.CurrentTick() {
-				return fmt.Errorf("got tx for tick %d, but world is at tick %d", target, w.CurrentTick())
-			}
-			for current := w.CurrentTick(); current != target; {
-				if err = w.Tick(ctx); err != nil {
-					return err
-				}
-				current = w.CurrentTick()
-			}
-			// we've now reached target. we need to inject the transactions and tick.
-			transactions := tickedTxs.Txs
-			for _, tx := range transactions {
-				sp, err := w.decodeTransaction(tx.GameShardTransaction)
-				if err != nil {
-					return err
-				}
-				itx := w.getITx(transaction.TypeID(tx.TxId))
-				if itx == nil {
-					return fmt.Errorf("error recovering tx with ID %d: tx id not found", tx.TxId)
-				}
-				v, err := itx.Decode(sp.Body)
-				if err != nil {
-					return err
-				}
-				w.AddTransaction(transaction.TypeID(tx.TxId), v, w.protoTransactionToGo(sp))
-			}
-			// run the tick for this batch
-			if err = w.Tick(ctx); err != nil {
+		for _, epoch := range res.Epochs {
+			if err = fn(epoch); err != nil {
 				return err
 			}
 		}
@@ -631,6 +875,50 @@ func (w *World) RecoverFromChain(ctx context.Context) error {
 	return nil
 }
 
+// replayEpoch ticks the world up to and including the given epoch. The ticks before the epoch were not epochs, so each
+// of them is run one tick interval after the previous tick (see Tick), and the epoch's transactions are run in its tick
+// with the timestamp the tick originally had.
+func (w *World) replayEpoch(ctx context.Context, epoch *types.Epoch) error {
+	target := epoch.Epoch
+	if target < w.CurrentTick() {
+		return fmt.Errorf("got tx for tick %d, but world is at tick %d", target, w.CurrentTick())
+	}
+	// tick up to target
+	for current := w.CurrentTick(); current != target; current = w.CurrentTick() {
+		w.txQueue.SetTimestamp(w.nextTimestamp(time.Time{}, false))
+		if err := w.Tick(ctx); err != nil {
+			return err
+		}
+	}
+	// we've now reached target. we need to inject the transactions and tick.
+	if err := w.queueEpoch(epoch); err != nil {
+		return err
+	}
+	return w.Tick(ctx)
+}
+
+// queueEpoch adds the transactions of an epoch to the transaction queue, so they are run in the next tick with the
+// timestamp the epoch originally had.
+func (w *World) queueEpoch(epoch *types.Epoch) error {
+	for _, tx := range epoch.Txs {
+		sp, err := w.decodeTransaction(tx.GameShardTransaction)
+		if err != nil {
+			return err
+		}
+		itx := w.getITx(transaction.TypeID(tx.TxId))
+		if itx == nil {
+			return fmt.Errorf("error recovering tx with ID %d: tx id not found", tx.TxId)
+		}
+		v, err := itx.Decode(sp.Body)
+		if err != nil {
+			return err
+		}
+		w.AddTransaction(transaction.TypeID(tx.TxId), v, w.protoTransactionToGo(sp))
+	}
+	w.txQueue.SetTimestamp(epoch.Timestamp)
+	return nil
+}
+
 func (w *World) protoTransactionToGo(sp *shardv1.Transaction) *sign.Transaction {
 	return &sign.Transaction{
 		PersonaTag: sp.PersonaTag,
@@ -641,6 +929,16 @@ func (w *World) protoTransactionToGo(sp *shardv1.Transaction) *sign.Transaction
 	}
 }
 
+func (w *World) goTransactionToProto(sp *sign.Transaction) *shardv1.Transaction {
+	return &shardv1.Transaction{
+		PersonaTag: sp.PersonaTag,
+		Namespace:  sp.Namespace,
+		Nonce:      sp.Nonce,
+		Signature:  sp.Signature,
+		Body:       sp.Body,
+	}
+}
+
 func (w *World) decodeTransaction(bz []byte) (*shardv1.Transaction, error) {
 	payload := new(shardv1.Transaction)
 	err := proto.Unmarshal(bz, payload)
@@ -692,7 +990,11 @@ func (w *World) GetComponents() []metadata.ComponentMetadata {
 }
 
 func (w *World) GetSystemNames() []string {
-	return w.systemNames
+	names := make([]string, 0, len(w.systems))
+	for _, sys := range w.systems {
+		names = append(names, sys.name)
+	}
+	return names
 }
 
 func (w *World) InjectLogger(logger *ecslog.Logger) {
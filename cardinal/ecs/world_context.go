@@ -2,17 +2,24 @@ package ecs
 
 import (
 	"errors"
+	"math/rand"
+	"time"
 
 	"github.com/rs/zerolog"
 	ecslog "pkg.world.dev/world-engine/cardinal/ecs/log"
 	"pkg.world.dev/world-engine/cardinal/ecs/store"
 	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
+	"pkg.world.dev/world-engine/cardinal/events"
 )
 
 type WorldContext interface {
 	CurrentTick() uint64
 	Logger() *zerolog.Logger
 	NewSearch(filter Filterable) (*Search, error)
+	// Rand returns a random number source that produces the same numbers every time the tick is replayed.
+	Rand() *rand.Rand
+	// Timestamp returns the timestamp of the current tick. Every tick sees the same time every time it is replayed.
+	Timestamp() time.Time
 
 	// For internal use.
 	GetWorld() *World
@@ -20,6 +27,10 @@ type WorldContext interface {
 	StoreManager() store.IManager
 	GetTxQueue() *transaction.TxQueue
 	IsReadOnly() bool
+	EmitEvent(event *events.Event)
+	AddTransactionError(hash transaction.TxHash, err error)
+	SetTransactionResult(hash transaction.TxHash, result any)
+	GetTransactionReceipt(hash transaction.TxHash) (any, []error, bool)
 }
 
 var (
@@ -31,6 +42,12 @@ type worldContext struct {
 	txQueue  *transaction.TxQueue
 	logger   *ecslog.Logger
 	readOnly bool
+	// system is the system this context was made for. It is nil outside of systems.
+	system *registeredSystem
+	// sideEffects holds the events and receipts of a system that runs in parallel with other systems. It is nil if
+	// they are applied to the world right away.
+	sideEffects *systemSideEffects
+	rand        *rand.Rand
 }
 
 func NewWorldContextForTick(world *World, queue *transaction.TxQueue, logger *ecslog.Logger) WorldContext {
@@ -42,6 +59,17 @@ func NewWorldContextForTick(world *World, queue *transaction.TxQueue, logger *ec
 	}
 }
 
+func newSystemWorldContext(world *World, queue *transaction.TxQueue, sys *registeredSystem,
+	sideEffects *systemSideEffects) WorldContext {
+	return &worldContext{
+		world:       world,
+		txQueue:     queue,
+		logger:      sys.logger,
+		system:      sys,
+		sideEffects: sideEffects,
+	}
+}
+
 func NewWorldContext(world *World) WorldContext {
 	return &worldContext{
 		world:    world,
@@ -81,6 +109,9 @@ func (w *worldContext) IsReadOnly() bool {
 }
 
 func (w *worldContext) StoreManager() store.IManager {
+	if w.system != nil && w.system.declaresAccess {
+		return &systemStoreManager{IManager: w.world.StoreManager(), system: w.system}
+	}
 	return w.world.StoreManager()
 }
 
@@ -92,6 +123,80 @@ func (w *worldContext) StoreReader() store.Reader {
 	return sm
 }
 
+// EmitEvent emits the given event. The events of a system that runs in parallel with other systems are emitted once
+// every system in the tick has finished.
+func (w *worldContext) EmitEvent(event *events.Event) {
+	if w.sideEffects != nil {
+		w.sideEffects.events = append(w.sideEffects.events, event)
+		return
+	}
+	w.world.EmitEvent(event)
+}
+
+// AddTransactionError adds the given error to the receipt of the given transaction. Like events, the receipts of a
+// system that runs in parallel with other systems are updated once every system in the tick has finished.
+func (w *worldContext) AddTransactionError(hash transaction.TxHash, err error) {
+	if w.sideEffects != nil {
+		w.sideEffects.receipts = append(w.sideEffects.receipts, receiptChange{hash: hash, err: err})
+		return
+	}
+	w.world.AddTransactionError(hash, err)
+}
+
+// SetTransactionResult sets the result of the receipt of the given transaction. See AddTransactionError.
+func (w *worldContext) SetTransactionResult(hash transaction.TxHash, result any) {
+	if w.sideEffects != nil {
+		w.sideEffects.receipts = append(w.sideEffects.receipts, receiptChange{hash: hash, result: result})
+		return
+	}
+	w.world.SetTransactionResult(hash, result)
+}
+
+// GetTransactionReceipt returns the result and errors of the given transaction, including the receipt changes this
+// context has not applied to the world yet.
+func (w *worldContext) GetTransactionReceipt(hash transaction.TxHash) (any, []error, bool) {
+	result, errs, ok := w.world.GetTransactionReceipt(hash)
+	if w.sideEffects == nil {
+		return result, errs, ok
+	}
+	for _, change := range w.sideEffects.receipts {
+		if change.hash != hash {
+			continue
+		}
+		ok = true
+		if change.err != nil {
+			errs = append(errs[:len(errs):len(errs)], change.err)
+		} else {
+			result = change.result
+		}
+	}
+	return result, errs, ok
+}
+
 func (w *worldContext) NewSearch(filter Filterable) (*Search, error) {
 	return w.world.NewSearch(filter)
 }
+
+// Rand returns a deterministic random number source. In a system, the source is seeded from the namespace, the tick
+// number, the hashes of the tick's transactions and the name of the system, so each system gets its own stream.
+// Systems that share a name are told apart by their registration order among the systems with that name. The same
+// source is returned every time Rand is called on this context.
+func (w *worldContext) Rand() *rand.Rand {
+	if w.rand == nil {
+		if w.system != nil {
+			w.rand = w.world.tickSeed.newRand(w.system.randStream)
+		} else {
+			w.rand = w.world.newTickSeed(w.world.CurrentTick(), w.txQueue).newRand("")
+		}
+	}
+	return w.rand
+}
+
+// Timestamp returns the timestamp of the current tick. Epochs (ticks with transactions, and the first tick after the
+// world is started or recovered from the base shard) use the time at which they were started, and every other tick is
+// exactly one tick interval after the previous tick. Epochs are stored on the base shard along with their timestamps,
+// so every tick sees the same time when it is recovered from storage or from the base shard. Outside a tick, it
+// returns the timestamp of the last tick.
+func (w *worldContext) Timestamp() time.Time {
+	return time.UnixMilli(int64(w.world.timestamp)).UTC()
+}
@@ -0,0 +1,54 @@
+package cardinal
+
+import (
+	"pkg.world.dev/world-engine/cardinal/ecs"
+)
+
+// AnyEventType is implemented by the return value of NewEventType and is used in RegisterEvents; any event type
+// created by NewEventType can be registered with a World object via RegisterEvents.
+type AnyEventType interface {
+	Convert() ecs.IEvent
+}
+
+// EventType represents a kind of event that systems can emit. The Payload struct is JSON encoded and sent to clients
+// connected to the /events websocket along with the event name and the tick it was emitted in.
+type EventType[Payload any] struct {
+	impl *ecs.EventType[Payload]
+}
+
+// EventOption sets optional fields on an event before it is emitted.
+type EventOption = ecs.EventOption
+
+// WithEventEntityID marks the emitted event as being about the given entity.
+func WithEventEntityID(id EntityID) EventOption {
+	return ecs.WithEventEntityID(id)
+}
+
+// WithEventPersonaTag marks the emitted event as being meant for the given persona.
+func WithEventPersonaTag(personaTag string) EventOption {
+	return ecs.WithEventPersonaTag(personaTag)
+}
+
+// NewEventType creates a new instance of an EventType.
+func NewEventType[Payload any](name string) *EventType[Payload] {
+	return &EventType[Payload]{
+		impl: ecs.NewEventType[Payload](name),
+	}
+}
+
+// Convert implements the AnyEventType interface which allows an EventType to be registered
+// with a World via RegisterEvents.
+func (e *EventType[Payload]) Convert() ecs.IEvent {
+	return e.impl
+}
+
+// Emit queues up an event with the given payload. The event is sent to clients when the current tick ends.
+func (e *EventType[Payload]) Emit(wCtx WorldContext, payload Payload, opts ...EventOption) error {
+	return e.impl.Emit(wCtx.getECSWorldContext(), payload, opts...)
+}
+
+// EmitTo queues up an event with the given payload that is only sent to clients that have authenticated as the given
+// persona.
+func (e *EventType[Payload]) EmitTo(wCtx WorldContext, personaTag string, payload Payload, opts ...EventOption) error {
+	return e.impl.EmitTo(wCtx.getECSWorldContext(), personaTag, payload, opts...)
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"pkg.world.dev/world-engine/sign"
+)
+
+var ErrAuthenticationNotSupported = errors.New("authentication is not supported")
+
+// Identity is what a websocket connection has proven about itself with a signed handshake. A connection that has
+// authenticated as a persona receives the events that target that persona. A connection that has authenticated as a
+// signer (via a system transaction) receives the events that target any persona the signer has signed for.
+type Identity struct {
+	PersonaTag    string `json:"personaTag,omitempty"`
+	SignerAddress string `json:"signerAddress,omitempty"`
+}
+
+// Authenticator verifies a signed handshake and returns the identity it proves.
+type Authenticator func(handshake *sign.Transaction) (Identity, error)
+
+// AuthenticationRequest can be sent by a client on the /events websocket to receive the events that target a persona.
+// Auth must be signed by the persona's signer, with a nonce greater than that of any earlier handshake of the signer.
+type AuthenticationRequest struct {
+	Auth *sign.Transaction `json:"auth"`
+}
+
+// AuthenticationMessage is sent to a websocket connection in response to an AuthenticationRequest.
+type AuthenticationMessage struct {
+	Authenticated *Identity `json:"authenticated,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// canReceive returns true if a connection with this identity may receive the given event.
+func (id Identity) canReceive(event *Event) bool {
+	if event.PersonaTag == "" {
+		return true
+	}
+	if id.PersonaTag != "" {
+		return id.PersonaTag == event.PersonaTag
+	}
+	return id.SignerAddress != "" && id.SignerAddress == event.SignerAddress
+}
+
+func newAuthenticationMessage(identity Identity, err error) ([]byte, error) {
+	msg := AuthenticationMessage{}
+	if err != nil {
+		msg.Error = err.Error()
+	} else {
+		msg.Authenticated = &identity
+	}
+	return json.Marshal(msg)
+}
+
+func handleAuthenticationRequest(hub EventHub, conn *websocket.Conn, handshake *sign.Transaction,
+	authenticate Authenticator) {
+	if authenticate == nil {
+		hub.AuthenticateConnection(conn, Identity{}, ErrAuthenticationNotSupported)
+		return
+	}
+	identity, err := authenticate(handshake)
+	if err != nil {
+		log.Logger.Debug().Err(err).Msg("invalid event handshake")
+	}
+	hub.AuthenticateConnection(conn, identity, err)
+}
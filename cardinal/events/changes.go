@@ -0,0 +1,100 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
+)
+
+var ErrChangeSubscriptionsNotSupported = errors.New("change subscriptions are not supported")
+
+// ChangeFilter reduces a TickDiff to the changes a single websocket connection has asked for. nil is returned if none
+// of the changes are relevant. ChangeFilters are called on the goroutine that runs the tick, so they may safely read
+// the world's state.
+type ChangeFilter func(diff *store.TickDiff) (*store.TickDiff, error)
+
+// ChangeFilterParser converts a CQL expression sent by a websocket client into a ChangeFilter.
+type ChangeFilterParser func(cqlText string) (ChangeFilter, error)
+
+// ChangeSubscriptionRequest can be sent by a client on the /events websocket to receive the component changes of the
+// entities that match the given CQL expression after each tick. Sending a new request replaces the previous one, and an
+// empty CQL expression cancels the subscription.
+type ChangeSubscriptionRequest struct {
+	CQL string `json:"cql"`
+}
+
+// clientMessage is any message a client can send on the /events websocket. A message with an Auth field is an
+// AuthenticationRequest, a message with a ReplayFromTick field is a ReplayRequest, and anything else is a
+// ChangeSubscriptionRequest.
+type clientMessage struct {
+	ChangeSubscriptionRequest
+	AuthenticationRequest
+	ReplayRequest
+}
+
+// ChangeMessage is sent to a websocket connection that has subscribed to changes. Changes is set after each tick in
+// which a matching entity changed. Error is set if the connection's subscription request could not be processed.
+type ChangeMessage struct {
+	Changes *store.TickDiff `json:"changes,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// connMessage is a message that must be written to a single websocket connection.
+type connMessage struct {
+	conn    *websocket.Conn
+	payload []byte
+}
+
+func newChangeMessage(diff *store.TickDiff, err error) ([]byte, error) {
+	msg := ChangeMessage{Changes: diff}
+	if err != nil {
+		msg.Error = err.Error()
+	}
+	return json.Marshal(msg)
+}
+
+// readClientMessages reads ChangeSubscriptionRequests, AuthenticationRequests and ReplayRequests from the given
+// connection until the connection is closed. The connection is unregistered from the hub once it can no longer be read.
+func readClientMessages(hub EventHub, conn *websocket.Conn, parse ChangeFilterParser, authenticate Authenticator,
+	readLog EventLogReader) {
+	defer hub.UnregisterConnection(conn)
+	for {
+		_, bz, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg clientMessage
+		if err = json.Unmarshal(bz, &msg); err != nil {
+			hub.SubscribeToChanges(conn, nil, err)
+			continue
+		}
+		if msg.Auth != nil {
+			handleAuthenticationRequest(hub, conn, msg.Auth, authenticate)
+			continue
+		}
+		if msg.ReplayFromTick != nil {
+			handleReplayRequest(hub, conn, *msg.ReplayFromTick, readLog)
+			continue
+		}
+		handleChangeSubscriptionRequest(hub, conn, msg.CQL, parse)
+	}
+}
+
+func handleChangeSubscriptionRequest(hub EventHub, conn *websocket.Conn, cqlText string, parse ChangeFilterParser) {
+	if cqlText == "" {
+		hub.SubscribeToChanges(conn, nil, nil)
+		return
+	}
+	if parse == nil {
+		hub.SubscribeToChanges(conn, nil, ErrChangeSubscriptionsNotSupported)
+		return
+	}
+	changeFilter, err := parse(cqlText)
+	if err != nil {
+		log.Logger.Debug().Err(err).Msg("invalid change subscription")
+	}
+	hub.SubscribeToChanges(conn, changeFilter, err)
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"encoding/json"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -9,7 +10,9 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	ecslog "pkg.world.dev/world-engine/cardinal/ecs/log"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
 )
 
 const shutdownPollInterval = 200
@@ -21,6 +24,18 @@ type EventHub interface {
 	Run()
 	UnregisterConnection(ws *websocket.Conn)
 	RegisterConnection(ws *websocket.Conn)
+	// SubscribeToChanges replaces the change subscription of the given connection with the given filter. A nil filter
+	// cancels the subscription. If err is not nil, the subscription is cancelled and err is reported to the connection.
+	SubscribeToChanges(ws *websocket.Conn, filter ChangeFilter, err error)
+	// PublishTickDiff sends the relevant part of the given diff to every connection that has subscribed to changes.
+	PublishTickDiff(diff *store.TickDiff)
+	// ReplayEvents sends the given saved events to the given connection, skipping any events the connection is not
+	// allowed to receive. If err is not nil, no events are sent and err is reported to the connection.
+	ReplayEvents(ws *websocket.Conn, replay Replay, err error)
+	// AuthenticateConnection sets the identity of the given connection, which decides which persona targeted events it
+	// receives. If err is not nil, the identity of the connection is left unchanged and err is reported to the
+	// connection.
+	AuthenticateConnection(ws *websocket.Conn, identity Identity, err error)
 }
 
 const (
@@ -49,6 +64,14 @@ func (eh *loggingEventHub) UnregisterConnection(_ *websocket.Conn) {}
 
 func (eh *loggingEventHub) RegisterConnection(_ *websocket.Conn) {}
 
+func (eh *loggingEventHub) SubscribeToChanges(_ *websocket.Conn, _ ChangeFilter, _ error) {}
+
+func (eh *loggingEventHub) PublishTickDiff(_ *store.TickDiff) {}
+
+func (eh *loggingEventHub) AuthenticateConnection(_ *websocket.Conn, _ Identity, _ error) {}
+
+func (eh *loggingEventHub) ReplayEvents(_ *websocket.Conn, _ Replay, _ error) {}
+
 func (eh *loggingEventHub) Run() {
 	if eh.running.Load() {
 		return
@@ -64,7 +87,12 @@ func (eh *loggingEventHub) Run() {
 			go func() {
 				defer wg.Done()
 				for _, event := range eh.eventQueue {
-					eh.logger.Info().Msg("EVENT: " + event.Message)
+					payload, err := event.Encode()
+					if err != nil {
+						eh.logger.Error().Err(err).Msg("failed to encode event")
+						continue
+					}
+					eh.logger.Info().Msg("EVENT: " + string(payload))
 				}
 			}() // a goroutine is not technically necessary here but this imitates the websocket eventhub as much as possible.
 			wg.Wait()
@@ -97,13 +125,18 @@ func CreateLoggingEventHub(logger *ecslog.Logger) EventHub {
 
 func CreateWebSocketEventHub() EventHub {
 	res := webSocketEventHub{
-		websocketConnections: map[*websocket.Conn]bool{},
+		websocketConnections: map[*websocket.Conn]Identity{},
 		broadcast:            make(chan *Event),
 		flush:                make(chan bool),
 		register:             make(chan *websocket.Conn),
 		unregister:           make(chan *websocket.Conn),
 		shutdown:             make(chan bool),
+		done:                 make(chan struct{}),
 		running:              atomic.Bool{},
+		changeFilters:        map[*websocket.Conn]ChangeFilter{},
+		messages:             make(chan []connMessage),
+		authenticate:         make(chan connIdentity),
+		replays:              make(chan connReplay),
 	}
 	res.running.Store(false)
 	go func() {
@@ -112,19 +145,70 @@ func CreateWebSocketEventHub() EventHub {
 	return &res
 }
 
+// Event is a message that is sent to every client connected to the /events websocket when the tick it was emitted in
+// ends. Events created with a plain string only have a Message, and the message is sent verbatim unless the event
+// targets a persona. Typed events (see ecs.NewEventType) have a Name and a JSON Payload. Typed events and events that
+// target a persona are sent as a JSON encoded Event, so receivers such as the relay can tell who they are meant for.
 type Event struct {
-	Message string
+	Message string `json:"message,omitempty"`
+	// Name is the name of the registered event type. It is empty for plain string events.
+	Name    string          `json:"name,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Tick    uint64          `json:"tick"`
+	// EntityID is the entity this event is about, if any.
+	EntityID *entity.ID `json:"entityId,omitempty"`
+	// PersonaTag is the persona this event is meant for, if any. Events with a PersonaTag are only sent to connections
+	// that have authenticated as the persona or as its signer.
+	PersonaTag string `json:"personaTag,omitempty"`
+	// SignerAddress is the signer of PersonaTag at the time the event was emitted. It is saved in the event log, but
+	// is not sent to clients.
+	SignerAddress string `json:"signerAddress,omitempty"`
+}
+
+// IsTyped returns true if the event was created from a registered event type rather than a plain string.
+func (e *Event) IsTyped() bool {
+	return e.Name != ""
+}
+
+// Encode returns the bytes that are sent to clients for this event.
+func (e *Event) Encode() ([]byte, error) {
+	if !e.IsTyped() && e.PersonaTag == "" {
+		return []byte(e.Message), nil
+	}
+	sent := *e
+	sent.SignerAddress = ""
+	return json.Marshal(sent)
+}
+
+// connIdentity is the result of a connection's authentication request.
+type connIdentity struct {
+	conn     *websocket.Conn
+	identity Identity
+	err      error
 }
 
 type webSocketEventHub struct {
-	websocketConnections map[*websocket.Conn]bool
+	// websocketConnections maps each connection to the identity it has authenticated as. Connections that have not
+	// authenticated have an empty Identity.
+	websocketConnections map[*websocket.Conn]Identity
 	broadcast            chan *Event
 	flush                chan bool
 	unregister           chan *websocket.Conn
 	register             chan *websocket.Conn
 	shutdown             chan bool
-	eventQueue           []*Event
-	running              atomic.Bool
+	// done is closed once Run exits, so other goroutines no longer wait on the hub.
+	done       chan struct{}
+	eventQueue []*Event
+	running    atomic.Bool
+
+	// changeFilters is read on the tick goroutine when a TickDiff is published, so it is guarded by a mutex instead of
+	// being owned by Run.
+	changeFiltersMutex sync.Mutex
+	changeFilters      map[*websocket.Conn]ChangeFilter
+	// messages are written to individual connections by Run.
+	messages     chan []connMessage
+	authenticate chan connIdentity
+	replays      chan connReplay
 }
 
 func (eh *webSocketEventHub) EmitEvent(event *Event) {
@@ -140,7 +224,76 @@ func (eh *webSocketEventHub) RegisterConnection(ws *websocket.Conn) {
 }
 
 func (eh *webSocketEventHub) UnregisterConnection(ws *websocket.Conn) {
-	eh.unregister <- ws
+	select {
+	case eh.unregister <- ws:
+	case <-eh.done:
+	}
+}
+
+func (eh *webSocketEventHub) SubscribeToChanges(ws *websocket.Conn, filter ChangeFilter, err error) {
+	eh.changeFiltersMutex.Lock()
+	if filter == nil || err != nil {
+		delete(eh.changeFilters, ws)
+	} else {
+		eh.changeFilters[ws] = filter
+	}
+	eh.changeFiltersMutex.Unlock()
+	if err == nil {
+		return
+	}
+	payload, encodeErr := newChangeMessage(nil, err)
+	if encodeErr != nil {
+		log.Logger.Error().Err(encodeErr).Msg("failed to encode change subscription error")
+		return
+	}
+	eh.sendMessages([]connMessage{{conn: ws, payload: payload}})
+}
+
+func (eh *webSocketEventHub) ReplayEvents(ws *websocket.Conn, replay Replay, err error) {
+	select {
+	case eh.replays <- connReplay{conn: ws, replay: replay, err: err}:
+	case <-eh.done:
+	}
+}
+
+func (eh *webSocketEventHub) AuthenticateConnection(ws *websocket.Conn, identity Identity, err error) {
+	select {
+	case eh.authenticate <- connIdentity{conn: ws, identity: identity, err: err}:
+	case <-eh.done:
+	}
+}
+
+func (eh *webSocketEventHub) PublishTickDiff(diff *store.TickDiff) {
+	var msgs []connMessage
+	eh.changeFiltersMutex.Lock()
+	for conn, filter := range eh.changeFilters {
+		filtered, err := filter(diff)
+		if err != nil {
+			log.Logger.Error().Err(err).Msg("failed to filter tick diff")
+			continue
+		}
+		if filtered == nil {
+			continue
+		}
+		payload, err := newChangeMessage(filtered, nil)
+		if err != nil {
+			log.Logger.Error().Err(err).Msg("failed to encode tick diff")
+			continue
+		}
+		msgs = append(msgs, connMessage{conn: conn, payload: payload})
+	}
+	eh.changeFiltersMutex.Unlock()
+	if len(msgs) > 0 {
+		eh.sendMessages(msgs)
+	}
+}
+
+// sendMessages hands the given messages to Run so they can be written to their connections.
+func (eh *webSocketEventHub) sendMessages(msgs []connMessage) {
+	select {
+	case eh.messages <- msgs:
+	case <-eh.done:
+	}
 }
 
 func (eh *webSocketEventHub) ShutdownEventHub() {
@@ -160,9 +313,13 @@ func (eh *webSocketEventHub) Run() {
 		return
 	}
 	eh.running.Store(true)
+	defer close(eh.done)
 	unregisterConnection := func(conn *websocket.Conn) {
 		if _, ok := eh.websocketConnections[conn]; ok {
 			delete(eh.websocketConnections, conn)
+			eh.changeFiltersMutex.Lock()
+			delete(eh.changeFilters, conn)
+			eh.changeFiltersMutex.Unlock()
 			err := conn.Close()
 			if err != nil {
 				log.Logger.Error().Err(err)
@@ -173,40 +330,42 @@ Loop:
 	for eh.running.Load() {
 		select {
 		case conn := <-eh.register:
-			eh.websocketConnections[conn] = true
+			eh.websocketConnections[conn] = Identity{}
 		case conn := <-eh.unregister:
 			unregisterConnection(conn)
 		case event := <-eh.broadcast:
 			eh.eventQueue = append(eh.eventQueue, event)
 		case <-eh.flush:
-			var waitGroup sync.WaitGroup
-			for conn := range eh.websocketConnections {
-				waitGroup.Add(1)
-				conn := conn
-				go func() {
-					defer waitGroup.Done()
-					for _, event := range eh.eventQueue {
-						err := conn.SetWriteDeadline(time.Now().Add(writeDeadline))
-						if err != nil {
-							go func() {
-								eh.UnregisterConnection(conn)
-							}()
-							log.Logger.Error().Err(err)
-							break
-						}
-						err = conn.WriteMessage(websocket.TextMessage, []byte(event.Message))
-						if err != nil {
-							go func() {
-								eh.UnregisterConnection(conn)
-							}()
-							log.Logger.Error().Err(err)
-							break
-						}
-					}
-				}()
-			}
-			waitGroup.Wait()
+			eh.writeMessages(eh.eventPayloadsForConnections())
 			eh.eventQueue = eh.eventQueue[:0]
+		case auth := <-eh.authenticate:
+			if _, ok := eh.websocketConnections[auth.conn]; !ok {
+				continue
+			}
+			if auth.err == nil {
+				eh.websocketConnections[auth.conn] = auth.identity
+			}
+			payload, err := newAuthenticationMessage(auth.identity, auth.err)
+			if err != nil {
+				log.Logger.Error().Err(err).Msg("failed to encode authentication response")
+				continue
+			}
+			eh.writeMessages(map[*websocket.Conn][][]byte{auth.conn: {payload}})
+		case r := <-eh.replays:
+			identity, ok := eh.websocketConnections[r.conn]
+			if !ok {
+				continue
+			}
+			eh.writeMessages(map[*websocket.Conn][][]byte{r.conn: replayPayloads(identity, r.replay, r.err)})
+		case msgs := <-eh.messages:
+			connToPayloads := map[*websocket.Conn][][]byte{}
+			for _, msg := range msgs {
+				// the connection may have been closed since the message was created.
+				if _, ok := eh.websocketConnections[msg.conn]; ok {
+					connToPayloads[msg.conn] = append(connToPayloads[msg.conn], msg.payload)
+				}
+			}
+			eh.writeMessages(connToPayloads)
 		case <-eh.shutdown:
 			for conn := range eh.websocketConnections {
 				unregisterConnection(conn)
@@ -217,6 +376,64 @@ Loop:
 	eh.running.Store(false)
 }
 
+// eventPayloadsForConnections encodes the queued events, and returns the events each connection is allowed to receive.
+func (eh *webSocketEventHub) eventPayloadsForConnections() map[*websocket.Conn][][]byte {
+	queued := make([]*Event, 0, len(eh.eventQueue))
+	payloads := make([][]byte, 0, len(eh.eventQueue))
+	for _, event := range eh.eventQueue {
+		payload, err := event.Encode()
+		if err != nil {
+			log.Logger.Error().Err(err).Msg("failed to encode event")
+			continue
+		}
+		queued = append(queued, event)
+		payloads = append(payloads, payload)
+	}
+	connToPayloads := make(map[*websocket.Conn][][]byte, len(eh.websocketConnections))
+	for conn, identity := range eh.websocketConnections {
+		connPayloads := make([][]byte, 0, len(payloads))
+		for i, event := range queued {
+			if identity.canReceive(event) {
+				connPayloads = append(connPayloads, payloads[i])
+			}
+		}
+		connToPayloads[conn] = connPayloads
+	}
+	return connToPayloads
+}
+
+// writeMessages writes the payloads to each connection in parallel, and blocks until all writes have finished.
+// Connections that cannot be written to are unregistered.
+func (eh *webSocketEventHub) writeMessages(connToPayloads map[*websocket.Conn][][]byte) {
+	var waitGroup sync.WaitGroup
+	for conn, payloads := range connToPayloads {
+		waitGroup.Add(1)
+		conn, payloads := conn, payloads
+		go func() {
+			defer waitGroup.Done()
+			for _, payload := range payloads {
+				err := conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+				if err != nil {
+					go func() {
+						eh.UnregisterConnection(conn)
+					}()
+					log.Logger.Error().Err(err)
+					break
+				}
+				err = conn.WriteMessage(websocket.TextMessage, payload)
+				if err != nil {
+					go func() {
+						eh.UnregisterConnection(conn)
+					}()
+					log.Logger.Error().Err(err)
+					break
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+}
+
 type webSocketHandler struct {
 	internalServe func(*websocket.Conn) error
 	path          string
@@ -260,9 +477,18 @@ func CreateNewWebSocketBuilder(path string, websocketConnectionHandler func(conn
 	}
 }
 
-func CreateWebSocketEventHandler(hub EventHub) func(conn *websocket.Conn) error {
+// CreateWebSocketEventHandler returns a websocket handler that registers each new connection with the given hub. If
+// parseFilter is not nil, each connection may also send ChangeSubscriptionRequests to receive entity and component
+// changes after each tick. If authenticate is not nil, each connection may also send an AuthenticationRequest to
+// receive the events that target a persona. If readLog is not nil, each connection may also send a ReplayRequest to
+// receive the events it missed.
+func CreateWebSocketEventHandler(hub EventHub, parseFilter ChangeFilterParser, authenticate Authenticator,
+	readLog EventLogReader) func(conn *websocket.Conn) error {
 	return func(conn *websocket.Conn) error {
 		hub.RegisterConnection(conn)
+		if parseFilter != nil || authenticate != nil || readLog != nil {
+			go readClientMessages(hub, conn, parseFilter, authenticate, readLog)
+		}
 		return nil
 	}
 }
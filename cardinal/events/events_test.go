@@ -3,22 +3,30 @@ package events_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"pkg.world.dev/world-engine/cardinal/testutils"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	ecslog "pkg.world.dev/world-engine/cardinal/ecs/log"
 	"pkg.world.dev/world-engine/cardinal/events"
 	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/sign"
 )
 
 func TestEvents(t *testing.T) {
@@ -147,9 +155,228 @@ func TestEventsThroughSystems(t *testing.T) {
 	assert.Equal(t, counter2.Load(), int32(numberToTest*numberToTest))
 }
 
+type DamageEvent struct {
+	Amount int `json:"amount"`
+}
+
+func TestTypedEventsAreSentAsJSON(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	damageEvent := ecs.NewEventType[DamageEvent]("damage")
+	assert.NilError(t, w.RegisterEvents(damageEvent))
+	assert.NilError(t, ecs.RegisterComponent[garbageStructAlpha](w))
+	var targetID atomic.Uint64
+	w.AddSystem(func(wCtx ecs.WorldContext) error {
+		if wCtx.CurrentTick() == 0 {
+			id, err := component.Create(wCtx, garbageStructAlpha{})
+			if err != nil {
+				return err
+			}
+			targetID.Store(uint64(id))
+			return nil
+		}
+		return damageEvent.Emit(wCtx, DamageEvent{Amount: 10}, ecs.WithEventEntityID(entity.ID(targetID.Load())))
+	})
+	assert.NilError(t, w.LoadGameState())
+	txh := testutils.MakeTestTransactionHandler(t, w, server.DisableSignatureVerification())
+	dial, _, err := websocket.DefaultDialer.Dial(txh.MakeWebSocketURL("events"), nil)
+	assert.NilError(t, err)
+
+	ctx := context.Background()
+	assert.NilError(t, w.Tick(ctx))
+	assert.NilError(t, w.Tick(ctx))
+
+	assert.NilError(t, dial.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, message, err := dial.ReadMessage()
+	assert.NilError(t, err)
+	var event events.Event
+	assert.NilError(t, json.Unmarshal(message, &event))
+	assert.Equal(t, "damage", event.Name)
+	assert.Equal(t, uint64(1), event.Tick)
+	assert.Equal(t, "", event.PersonaTag)
+	assert.Assert(t, event.EntityID != nil)
+	assert.Equal(t, entity.ID(targetID.Load()), *event.EntityID)
+	var payload DamageEvent
+	assert.NilError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, 10, payload.Amount)
+}
+
+func TestPersonaEventsOnlyReachAuthenticatedConnections(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	privateKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	signerAddress := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	ecs.CreatePersonaTx.AddToQueue(w, ecs.CreatePersonaTransaction{
+		PersonaTag:    "alice",
+		SignerAddress: signerAddress,
+	})
+	w.AddSystem(func(wCtx ecs.WorldContext) error {
+		if wCtx.CurrentTick() > 0 {
+			wCtx.GetWorld().EmitEventTo("alice", &events.Event{Message: "secret"})
+			wCtx.GetWorld().EmitEvent(&events.Event{Message: "public"})
+		}
+		return nil
+	})
+	assert.NilError(t, w.LoadGameState())
+	txh := testutils.MakeTestTransactionHandler(t, w, server.DisableSignatureVerification())
+	ctx := context.Background()
+	// The persona is created during the first tick.
+	assert.NilError(t, w.Tick(ctx))
+
+	dial := func() *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial(txh.MakeWebSocketURL("events"), nil)
+		assert.NilError(t, err)
+		assert.NilError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		return conn
+	}
+	authenticate := func(conn *websocket.Conn, handshake *sign.Transaction) events.AuthenticationMessage {
+		assert.NilError(t, conn.WriteJSON(events.AuthenticationRequest{Auth: handshake}))
+		var msg events.AuthenticationMessage
+		assert.NilError(t, conn.ReadJSON(&msg))
+		return msg
+	}
+	namespace := w.Namespace().String()
+
+	anonConn := dial()
+	personaConn := dial()
+	handshake, err := sign.NewTransaction(privateKey, "alice", namespace, 1,
+		ecs.EventHandshake{SignerAddress: signerAddress})
+	assert.NilError(t, err)
+	msg := authenticate(personaConn, handshake)
+	assert.Equal(t, "", msg.Error)
+	assert.Equal(t, "alice", msg.Authenticated.PersonaTag)
+
+	// The same handshake cannot be replayed.
+	replayConn := dial()
+	msg = authenticate(replayConn, handshake)
+	assert.Check(t, msg.Authenticated == nil)
+	assert.ErrorContains(t, errors.New(msg.Error), ecs.ErrInvalidEventHandshake.Error())
+
+	// A handshake signed by the wrong key is rejected.
+	otherKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	forgedConn := dial()
+	forged, err := sign.NewTransaction(otherKey, "alice", namespace, 2,
+		ecs.EventHandshake{SignerAddress: signerAddress})
+	assert.NilError(t, err)
+	msg = authenticate(forgedConn, forged)
+	assert.Check(t, msg.Authenticated == nil)
+	assert.Check(t, msg.Error != "")
+
+	signerConn := dial()
+	signerHandshake, err := sign.NewSystemTransaction(privateKey, namespace, 3,
+		ecs.EventHandshake{SignerAddress: signerAddress})
+	assert.NilError(t, err)
+	msg = authenticate(signerConn, signerHandshake)
+	assert.Equal(t, "", msg.Error)
+	assert.Equal(t, signerAddress, msg.Authenticated.SignerAddress)
+
+	// Handshakes have their own nonces, and do not use up the transaction nonces of the signer.
+	txNonce, err := w.GetNonce(signerAddress)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), txNonce)
+
+	assert.NilError(t, w.Tick(ctx))
+
+	readAll := func(conn *websocket.Conn, count int) []string {
+		var messages []string
+		for i := 0; i < count; i++ {
+			_, message, err := conn.ReadMessage()
+			assert.NilError(t, err)
+			messages = append(messages, string(message))
+		}
+		return messages
+	}
+	// Events that target a persona are JSON encoded along with the persona tag, so they can be routed by the receiver.
+	secret := `{"message":"secret","tick":1,"personaTag":"alice"}`
+	assert.DeepEqual(t, []string{secret, "public"}, readAll(personaConn, 2))
+	assert.DeepEqual(t, []string{secret, "public"}, readAll(signerConn, 2))
+	// Events are sent in the order they were emitted, so the first message would be the secret one if it was sent.
+	assert.DeepEqual(t, []string{"public"}, readAll(anonConn, 1))
+	assert.DeepEqual(t, []string{"public"}, readAll(forgedConn, 1))
+}
+
+func TestReconnectingClientsCanReplayMissedEvents(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	privateKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	ecs.CreatePersonaTx.AddToQueue(w, ecs.CreatePersonaTransaction{
+		PersonaTag:    "alice",
+		SignerAddress: crypto.PubkeyToAddress(privateKey.PublicKey).Hex(),
+	})
+	w.AddSystem(func(wCtx ecs.WorldContext) error {
+		tick := wCtx.CurrentTick()
+		wCtx.GetWorld().EmitEvent(&events.Event{Message: fmt.Sprintf("public-%d", tick)})
+		wCtx.GetWorld().EmitEventTo("alice", &events.Event{Message: fmt.Sprintf("secret-%d", tick)})
+		return nil
+	})
+	assert.NilError(t, w.LoadGameState())
+	txh := testutils.MakeTestTransactionHandler(t, w, server.DisableSignatureVerification())
+	ctx := context.Background()
+	// No client is connected while these ticks run.
+	for i := 0; i < 3; i++ {
+		assert.NilError(t, w.Tick(ctx))
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(txh.MakeWebSocketURL("events"), nil)
+	assert.NilError(t, err)
+	assert.NilError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	fromTick := uint64(1)
+	assert.NilError(t, conn.WriteJSON(events.ReplayRequest{ReplayFromTick: &fromTick}))
+	readReplay := func(count int) []string {
+		var messages []string
+		for i := 0; i < count; i++ {
+			_, message, err := conn.ReadMessage()
+			assert.NilError(t, err)
+			messages = append(messages, string(message))
+		}
+		var msg events.ReplayMessage
+		assert.NilError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, "", msg.Error)
+		assert.Equal(t, fromTick, msg.Replayed.FromTick)
+		assert.Equal(t, count, msg.Replayed.Count)
+		// Every saved event fits in a single replay.
+		assert.Equal(t, uint64(0), msg.Replayed.NextTick)
+		return messages
+	}
+	// Events that target a persona are not replayed to unauthenticated connections.
+	assert.DeepEqual(t, []string{"public-1", "public-2"}, readReplay(2))
+
+	handshake, err := sign.NewTransaction(privateKey, "alice", w.Namespace().String(), 1,
+		ecs.EventHandshake{SignerAddress: crypto.PubkeyToAddress(privateKey.PublicKey).Hex()})
+	assert.NilError(t, err)
+	assert.NilError(t, conn.WriteJSON(events.AuthenticationRequest{Auth: handshake}))
+	var authMsg events.AuthenticationMessage
+	assert.NilError(t, conn.ReadJSON(&authMsg))
+	assert.Equal(t, "", authMsg.Error)
+
+	assert.NilError(t, conn.WriteJSON(events.ReplayRequest{ReplayFromTick: &fromTick}))
+	secret := func(tick int) string {
+		return fmt.Sprintf(`{"message":"secret-%d","tick":%d,"personaTag":"alice"}`, tick, tick)
+	}
+	assert.DeepEqual(t, []string{"public-1", secret(1), "public-2", secret(2)}, readReplay(4))
+}
+
+// syncBuffer is a bytes.Buffer that can be written by the logging event hub while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 func TestEventHubLogger(t *testing.T) {
 	// replaces internal Logger with one that logs to the buf variable above.
-	var buf bytes.Buffer
+	var buf syncBuffer
 	bufLogger := zerolog.New(&buf)
 	cardinalLogger := ecslog.Logger{
 		&bufLogger,
@@ -169,11 +396,70 @@ func TestEventHubLogger(t *testing.T) {
 		assert.NilError(t, err)
 	}
 	testString := "{\"level\":\"info\",\"message\":\"EVENT: test\"}\n"
-	eventsLogs := buf.String()
-	splitLogs := strings.Split(eventsLogs, "\n")
-	splitLogs = splitLogs[:len(splitLogs)-1]
+	// The events are logged asynchronously, so wait for the events of the last tick to be logged.
+	var splitLogs []string
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		splitLogs = strings.Split(buf.String(), "\n")
+		splitLogs = splitLogs[:len(splitLogs)-1]
+		if len(splitLogs) < 25 {
+			return poll.Continue("%d of 25 events logged", len(splitLogs))
+		}
+		return poll.Success()
+	}, poll.WithTimeout(5*time.Second))
 	assert.Equal(t, 25, len(splitLogs))
 	for _, logEntry := range splitLogs {
 		require.JSONEq(t, testString, logEntry)
 	}
 }
+
+func TestEventsWebSocketCanSubscribeToFilteredChanges(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[garbageStructAlpha](w))
+	assert.NilError(t, ecs.RegisterComponent[garbageStructBeta](w))
+	w.AddSystem(func(wCtx ecs.WorldContext) error {
+		if _, err := component.Create(wCtx, garbageStructAlpha{Something: 1}); err != nil {
+			return err
+		}
+		_, err := component.Create(wCtx, garbageStructBeta{Something: 2})
+		return err
+	})
+	assert.NilError(t, w.LoadGameState())
+	txh := testutils.MakeTestTransactionHandler(t, w, server.DisableSignatureVerification())
+	conn, _, err := websocket.DefaultDialer.Dial(txh.MakeWebSocketURL("events"), nil)
+	assert.NilError(t, err)
+
+	// Invalid CQL should be reported back to the client.
+	assert.NilError(t, conn.WriteJSON(events.ChangeSubscriptionRequest{CQL: "CONTAINS(not_a_component)"}))
+	var msg events.ChangeMessage
+	assert.NilError(t, conn.ReadJSON(&msg))
+	assert.Check(t, msg.Error != "")
+
+	assert.NilError(t, conn.WriteJSON(events.ChangeSubscriptionRequest{CQL: "CONTAINS(alpha)"}))
+	// The subscription is processed in the background, so keep ticking until the first change arrives.
+	stopTicking := make(chan struct{})
+	tickingDone := make(chan struct{})
+	go func() {
+		defer close(tickingDone)
+		for {
+			select {
+			case <-stopTicking:
+				return
+			case <-time.After(10 * time.Millisecond):
+				assert.NilError(t, w.Tick(context.Background()))
+			}
+		}
+	}()
+	msg = events.ChangeMessage{}
+	assert.NilError(t, conn.ReadJSON(&msg))
+	close(stopTicking)
+	<-tickingDone
+
+	assert.Equal(t, "", msg.Error)
+	changes := msg.Changes
+	assert.Check(t, changes != nil)
+	// Only the alpha entity created during the tick should be included.
+	assert.Equal(t, 1, len(changes.CreatedEntities))
+	assert.Equal(t, 1, len(changes.ComponentSets))
+	assert.Equal(t, "alpha", changes.ComponentSets[0].Component)
+	assert.Equal(t, changes.CreatedEntities[0], changes.ComponentSets[0].EntityID)
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrReplayNotSupported = errors.New("event replay is not supported")
+
+// EventLogReader returns the saved events that were emitted during or after the given tick, in the order they were
+// emitted. If only some of the events were read, nextTick is the tick to read the rest from; otherwise it is 0.
+type EventLogReader func(fromTick uint64) (evts []*Event, nextTick uint64, err error)
+
+// ReplayRequest can be sent by a client on the /events websocket to receive the saved events that were emitted during
+// or after the given tick, e.g. after reconnecting. Only the events the connection is currently allowed to receive are
+// replayed, so a connection should authenticate before asking for a replay. Events that are emitted while the replay
+// is being read may be sent both live and in the replay; clients can use the tick of each event to skip duplicates.
+// The number of ticks replayed at once is limited. If ReplaySummary.NextTick is set, the client should send another
+// ReplayRequest from that tick to receive the rest of the events.
+type ReplayRequest struct {
+	ReplayFromTick *uint64 `json:"replayFromTick"`
+}
+
+// Replay is the result of a ReplayRequest.
+type Replay struct {
+	FromTick uint64
+	// NextTick is the tick to replay the rest of the events from, or 0 if every saved event was read.
+	NextTick uint64
+	Events   []*Event
+}
+
+// ReplayMessage is sent to a websocket connection after the replayed events.
+type ReplayMessage struct {
+	Replayed *ReplaySummary `json:"replayed,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// ReplaySummary describes the events that were replayed.
+type ReplaySummary struct {
+	FromTick uint64 `json:"fromTick"`
+	Count    int    `json:"count"`
+	// NextTick is set if there are more saved events, which can be replayed from this tick.
+	NextTick uint64 `json:"nextTick,omitempty"`
+}
+
+// connReplay is the result of a connection's replay request.
+type connReplay struct {
+	conn   *websocket.Conn
+	replay Replay
+	err    error
+}
+
+// replayPayloads encodes the replayed events the given identity is allowed to receive, followed by a ReplayMessage.
+func replayPayloads(identity Identity, replay Replay, replayErr error) [][]byte {
+	var payloads [][]byte
+	msg := ReplayMessage{}
+	if replayErr != nil {
+		msg.Error = replayErr.Error()
+	} else {
+		msg.Replayed = &ReplaySummary{FromTick: replay.FromTick, NextTick: replay.NextTick}
+		for _, event := range replay.Events {
+			if !identity.canReceive(event) {
+				continue
+			}
+			payload, err := event.Encode()
+			if err != nil {
+				log.Logger.Error().Err(err).Msg("failed to encode event")
+				continue
+			}
+			payloads = append(payloads, payload)
+		}
+		msg.Replayed.Count = len(payloads)
+	}
+	bz, err := json.Marshal(msg)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("failed to encode replay response")
+		return payloads
+	}
+	return append(payloads, bz)
+}
+
+func handleReplayRequest(hub EventHub, conn *websocket.Conn, fromTick uint64, readLog EventLogReader) {
+	if readLog == nil {
+		hub.ReplayEvents(conn, Replay{}, ErrReplayNotSupported)
+		return
+	}
+	evts, nextTick, err := readLog(fromTick)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("failed to read event log")
+	}
+	hub.ReplayEvents(conn, Replay{FromTick: fromTick, NextTick: nextTick, Events: evts}, err)
+}
@@ -11,7 +11,7 @@ import (
 
 	"pkg.world.dev/world-engine/cardinal/ecs"
 	"pkg.world.dev/world-engine/cardinal/ecs/component"
-	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/storage"
 	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
 	"pkg.world.dev/world-engine/sign"
 
@@ -270,34 +270,19 @@ func (s *msgServerImpl) SendMessage(_ context.Context, msg *routerv1.SendMessage
 // getSignerComponentForAuthorizedAddr attempts to find a stored SignerComponent which contains the provided `addr`
 // within its authorized addresses slice.
 func (s *msgServerImpl) getSignerComponentForAuthorizedAddr(addr string) (*ecs.SignerComponent, error) {
-	var sc *ecs.SignerComponent
-	var err error
 	wCtx := ecs.NewReadOnlyWorldContext(s.world)
 	q, err := wCtx.NewSearch(ecs.Exact(ecs.SignerComponent{}))
 	if err != nil {
 		return nil, err
 	}
-	err = q.Each(wCtx, func(id entity.ID) bool {
-		var signerComp *ecs.SignerComponent
-		signerComp, err = component.GetComponent[ecs.SignerComponent](wCtx, id)
-		if err != nil {
-			return false
-		}
-		for _, authAddr := range signerComp.AuthorizedAddresses {
-			if authAddr == addr {
-				sc = signerComp
-				return false
-			}
-		}
-		return true
-	})
+	id, err := q.Where("AuthorizedAddresses", addr).First(wCtx)
 	if err != nil {
 		return nil, err
 	}
-	if sc == nil {
+	if id == storage.BadID {
 		return nil, fmt.Errorf("address %s does not have a linked persona tag", addr)
 	}
-	return sc, nil
+	return component.GetComponent[ecs.SignerComponent](wCtx, id)
 }
 
 func (s *msgServerImpl) QueryShard(_ context.Context, req *routerv1.QueryShardRequest) (
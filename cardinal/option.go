@@ -1,6 +1,7 @@
 package cardinal
 
 import (
+	"io"
 	"time"
 
 	"pkg.world.dev/world-engine/cardinal/ecs"
@@ -57,8 +58,9 @@ func WithDisableSignatureVerification() WorldOption {
 }
 
 // WithTickChannel sets the channel that will be used to decide when world.Tick is executed. If unset, a loop interval
-// of 1 second will be set. To set some other time, use: WithTickChannel(time.Tick(<some-duration>)). Tests can pass
-// in a channel controlled by the test for fine-grained control over when ticks are executed.
+// of 1 second (or the duration given to WithTickInterval) will be set. To set some other time, use:
+// WithTickChannel(time.Tick(<some-duration>)). Tests can pass in a channel controlled by the test for fine-grained
+// control over when ticks are executed.
 func WithTickChannel(ch <-chan time.Time) WorldOption {
 	return WorldOption{
 		cardinalOption: func(world *World) {
@@ -67,6 +69,23 @@ func WithTickChannel(ch <-chan time.Time) WorldOption {
 	}
 }
 
+// WithTickInterval sets the time between ticks. The default is 1 second. Systems registered with Interval run once
+// every interval, rounded up to a whole number of ticks. If WithTickChannel is also used, the tick channel decides
+// when ticks happen, and this duration is only used to convert system intervals into ticks.
+func WithTickInterval(d time.Duration) WorldOption {
+	return WorldOption{
+		ecsOption: ecs.WithTickInterval(d),
+	}
+}
+
+// WithTransactionLog makes the world write the transactions of every tick to the given writer. The log can be read
+// with ReadTransactionLog and replayed with VerifyReplay to check that the game's systems are deterministic.
+func WithTransactionLog(writer io.Writer) WorldOption {
+	return WorldOption{
+		ecsOption: ecs.WithTransactionLog(writer),
+	}
+}
+
 // WithTickDoneChannel sets a channel that will be notified each time a tick completes. The completed tick will be
 // pushed to the channel. This option is useful in tests when assertions need to be performed at the end of a tick.
 func WithTickDoneChannel(ch chan<- uint64) WorldOption {
@@ -82,3 +101,27 @@ func WithPrettyLog() WorldOption {
 		ecsOption: ecs.WithPrettyLog(),
 	}
 }
+
+// WithEventRetention specifies how many ticks worth of emitted events are saved so clients can replay the events they
+// missed. The default is 1000. A retention of 0 keeps events forever.
+func WithEventRetention(ticks uint64) WorldOption {
+	return WorldOption{
+		ecsOption: ecs.WithEventRetention(ticks),
+	}
+}
+
+// WithProofRetention specifies how many finalized ticks component proofs can be built for (see GetComponentProof). The
+// default is 100. A retention of 0 keeps every tick since the world was started.
+func WithProofRetention(ticks uint64) WorldOption {
+	return WorldOption{
+		ecsOption: ecs.WithProofRetention(ticks),
+	}
+}
+
+// WithStateHashRetention specifies how many ticks worth of state hashes are saved (see StateHash). The default is
+// 1000. A retention of 0 keeps the state hash of every tick.
+func WithStateHashRetention(ticks uint64) WorldOption {
+	return WorldOption{
+		ecsOption: ecs.WithStateHashRetention(ticks),
+	}
+}
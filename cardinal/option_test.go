@@ -5,13 +5,13 @@ import (
 	"errors"
 	"testing"
 
+	"pkg.world.dev/world-engine/cardinal/shard"
 	"pkg.world.dev/world-engine/chain/x/shard/types"
-	"pkg.world.dev/world-engine/sign"
 )
 
 type DummyAdapter struct{}
 
-func (d *DummyAdapter) Submit(_ context.Context, _ *sign.Transaction, _, _ uint64) error {
+func (d *DummyAdapter) SubmitEpoch(_ context.Context, _ *shard.Epoch) error {
 	return nil
 }
 
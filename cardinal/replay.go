@@ -0,0 +1,42 @@
+package cardinal
+
+import (
+	"context"
+	"io"
+
+	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/chain/x/shard/types"
+)
+
+// ReplayDivergence is the first tick and system in which two replays of the same transaction history ended up with
+// different state, or the first tick at whose end the replays differed from the state hash recorded in the history.
+type ReplayDivergence = ecs.Divergence
+
+// ReadTransactionLog reads every epoch that was written by a world created with WithTransactionLog.
+func ReadTransactionLog(reader io.Reader) ([]*types.Epoch, error) {
+	return ecs.ReadTransactionLog(reader)
+}
+
+// VerifyReplay replays the given history into two fresh worlds and reports the first tick and system after which
+// their state differed, or the first tick after which they differed from the state hashes recorded in the history.
+// newWorld must return a world with empty storage that has all of its components, transactions
+// and systems registered, but that has not been started. A nil ReplayDivergence means the replays matched.
+func VerifyReplay(ctx context.Context, newWorld func() (*World, error), history []*types.Epoch) (
+	*ReplayDivergence, error) {
+	var worlds []*World
+	defer func() {
+		for _, w := range worlds {
+			if w.cleanup != nil {
+				w.cleanup()
+			}
+		}
+	}()
+	return ecs.VerifyReplay(ctx, func() (*ecs.World, error) {
+		w, err := newWorld()
+		if err != nil {
+			return nil, err
+		}
+		worlds = append(worlds, w)
+		return w.implWorld, nil
+	}, history)
+}
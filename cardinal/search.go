@@ -2,6 +2,7 @@ package cardinal
 
 import (
 	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 )
 
@@ -22,6 +23,12 @@ func (q *Search) Each(wCtx WorldContext, callback SearchCallBackFn) error {
 	})
 }
 
+// Where returns a copy of this search that only matches entities whose indexed field has the given value. The field is
+// either "Component.Field", or the name of a field that only one registered component indexes. See WithIndex.
+func (q *Search) Where(field string, value any) *Search {
+	return &Search{impl: q.impl.Where(field, value)}
+}
+
 // Count returns the number of entities that match this search.
 func (q *Search) Count(wCtx WorldContext) (int, error) {
 	return q.impl.Count(wCtx.getECSWorldContext())
@@ -31,3 +38,70 @@ func (q *Search) Count(wCtx WorldContext) (int, error) {
 func (q *Search) First(wCtx WorldContext) (EntityID, error) {
 	return q.impl.First(wCtx.getECSWorldContext())
 }
+
+// TypedQuery1 visits every entity that has component A, along with a copy of its value.
+type TypedQuery1[A metadata.Component] struct {
+	impl *ecs.TypedQuery1[A]
+}
+
+// TypedQuery2 visits every entity that has components A and B, along with copies of their values.
+type TypedQuery2[A, B metadata.Component] struct {
+	impl *ecs.TypedQuery2[A, B]
+}
+
+// TypedQuery3 visits every entity that has components A, B and C, along with copies of their values.
+type TypedQuery3[A, B, C metadata.Component] struct {
+	impl *ecs.TypedQuery3[A, B, C]
+}
+
+// Query1 returns a query over every entity that has component A. Component values are fetched an archetype at a time,
+// which is faster than calling GetComponent for every entity of a Search.
+func Query1[A metadata.Component](wCtx WorldContext) *TypedQuery1[A] {
+	return &TypedQuery1[A]{impl: ecs.Query1[A](wCtx.getECSWorldContext())}
+}
+
+// Query2 returns a query over every entity that has components A and B.
+func Query2[A, B metadata.Component](wCtx WorldContext) *TypedQuery2[A, B] {
+	return &TypedQuery2[A, B]{impl: ecs.Query2[A, B](wCtx.getECSWorldContext())}
+}
+
+// Query3 returns a query over every entity that has components A, B and C.
+func Query3[A, B, C metadata.Component](wCtx WorldContext) *TypedQuery3[A, B, C] {
+	return &TypedQuery3[A, B, C]{impl: ecs.Query3[A, B, C](wCtx.getECSWorldContext())}
+}
+
+// WriteBack makes Each save the component values that the callback changed.
+func (q *TypedQuery1[A]) WriteBack() *TypedQuery1[A] {
+	q.impl.WriteBack()
+	return q
+}
+
+// WriteBack makes Each save the component values that the callback changed.
+func (q *TypedQuery2[A, B]) WriteBack() *TypedQuery2[A, B] {
+	q.impl.WriteBack()
+	return q
+}
+
+// WriteBack makes Each save the component values that the callback changed.
+func (q *TypedQuery3[A, B, C]) WriteBack() *TypedQuery3[A, B, C] {
+	q.impl.WriteBack()
+	return q
+}
+
+// Each executes the given callback on every matching entity. If any call to callback returns false, no more entities
+// will be processed.
+func (q *TypedQuery1[A]) Each(callback func(EntityID, *A) bool) error {
+	return q.impl.Each(callback)
+}
+
+// Each executes the given callback on every matching entity. If any call to callback returns false, no more entities
+// will be processed.
+func (q *TypedQuery2[A, B]) Each(callback func(EntityID, *A, *B) bool) error {
+	return q.impl.Each(callback)
+}
+
+// Each executes the given callback on every matching entity. If any call to callback returns false, no more entities
+// will be processed.
+func (q *TypedQuery3[A, B, C]) Each(callback func(EntityID, *A, *B, *C) bool) error {
+	return q.impl.Each(callback)
+}
@@ -7,12 +7,17 @@ import (
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/runtime/middleware/untyped"
+	"github.com/rs/zerolog/log"
 	"net/http"
 	"pkg.world.dev/world-engine/cardinal/ecs"
 	"pkg.world.dev/world-engine/cardinal/ecs/cql"
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/filter"
 )
 
+// nextCursorHeader is the header of a /query/game/cql response that holds the cursor of the next page, if there is one.
+const nextCursorHeader = "X-Next-Cursor"
+
 // register query endpoints for swagger server.
 //
 //nolint:funlen,gocognit
@@ -87,6 +92,16 @@ func (handler *Handler) registerQueryHandlerSwagger(api *untyped.API) error {
 		getListTxReceiptsReplyFromRequest(handler.w),
 	)
 
+	stateHashHandler := createSwaggerQueryHandler[StateHashRequest, StateHashReply](
+		"StateHashRequest",
+		handler.getStateHashReply,
+	)
+
+	componentProofHandler := createSwaggerQueryHandler[ComponentProofRequest, ComponentProofReply](
+		"ComponentProofRequest",
+		handler.getComponentProofReply,
+	)
+
 	cqlHandler := runtime.OperationHandlerFunc(func(params interface{}) (interface{}, error) {
 		mapStruct, ok := params.(map[string]interface{})
 		if !ok {
@@ -108,39 +123,51 @@ func (handler *Handler) registerQueryHandlerSwagger(api *untyped.API) error {
 		if !ok {
 			return middleware.Error(http.StatusUnprocessableEntity, fmt.Errorf("json is invalid")), nil
 		}
-		resultFilter, err := cql.Parse(cqlString, handler.w.GetComponentByName)
+		cursor := ""
+		if cursorUntyped, ok := cqlRequest["cursor"]; ok {
+			if cursor, ok = cursorUntyped.(string); !ok {
+				return middleware.Error(http.StatusUnprocessableEntity, fmt.Errorf("json is invalid")), nil
+			}
+		}
+		query, err := cql.ParseQuery(cqlString, handler.w.GetComponentByName)
 		if err != nil {
 			return middleware.Error(http.StatusUnprocessableEntity, err), nil
 		}
 
-		result := make([]cql.QueryResponse, 0)
-
 		wCtx := ecs.NewReadOnlyWorldContext(handler.w)
-		err = ecs.NewSearch(resultFilter).Each(wCtx, func(id entity.ID) bool {
-			components, err := handler.w.StoreManager().GetComponentTypesForEntity(id)
-			if err != nil {
-				return false
-			}
-			resultElement := cql.QueryResponse{
-				ID:   id,
-				Data: make([]json.RawMessage, 0),
+		if query.Aggregates != nil {
+			if cursor != "" {
+				return middleware.Error(http.StatusUnprocessableEntity,
+					fmt.Errorf("%w: aggregate queries do not have cursors", cql.ErrInvalidCursor)), nil
 			}
+			return ecs.AggregateCQL(wCtx, query)
+		}
+		ids, nextCursor, err := ecs.QueryCQL(wCtx, query, cursor)
+		if errors.Is(err, cql.ErrInvalidCursor) {
+			return middleware.Error(http.StatusUnprocessableEntity, err), nil
+		} else if err != nil {
+			return nil, err
+		}
 
-			for _, c := range components {
-				data, err := ecs.GetRawJSONOfComponent(handler.w, c, id)
-				if err != nil {
-					return false
-				}
-				resultElement.Data = append(resultElement.Data, data)
+		result := make([]cql.QueryResponse, 0, len(ids))
+		for _, id := range ids {
+			resultElement, err := handler.getCQLQueryResponse(query, id)
+			if err != nil {
+				return nil, err
 			}
 			result = append(result, resultElement)
-			return true
-		})
-		if err != nil {
-			return nil, err
 		}
 
-		return result, nil
+		if nextCursor == "" {
+			return result, nil
+		}
+		return middleware.ResponderFunc(func(rw http.ResponseWriter, producer runtime.Producer) {
+			rw.Header().Set(nextCursorHeader, nextCursor)
+			rw.WriteHeader(http.StatusOK)
+			if err := producer.Produce(rw, result); err != nil {
+				log.Error().Err(err).Msg("failed to write cql response")
+			}
+		}), nil
 	})
 
 	api.RegisterOperation("POST", "/query/game/cql", cqlHandler)
@@ -148,6 +175,37 @@ func (handler *Handler) registerQueryHandlerSwagger(api *untyped.API) error {
 	api.RegisterOperation("POST", "/query/http/endpoints", listHandler)
 	api.RegisterOperation("POST", "/query/persona/signer", personaHandler)
 	api.RegisterOperation("POST", "/query/receipts/list", receiptsHandler)
+	api.RegisterOperation("POST", "/query/state/hash", stateHashHandler)
+	api.RegisterOperation("POST", "/query/state/proof", componentProofHandler)
 
 	return nil
 }
+
+// getCQLQueryResponse returns the components of the given entity that the query selects. If the query has a SELECT
+// clause, the data has one element per selected component, which is null if the entity does not have the component.
+func (handler *Handler) getCQLQueryResponse(query *cql.Query, id entity.ID) (cql.QueryResponse, error) {
+	components, err := handler.w.StoreManager().GetComponentTypesForEntity(id)
+	if err != nil {
+		return cql.QueryResponse{}, err
+	}
+	resultElement := cql.QueryResponse{
+		ID:   id,
+		Data: make([]json.RawMessage, 0, len(components)),
+	}
+	selected := components
+	if query.Select != nil {
+		selected = query.Select
+	}
+	for _, c := range selected {
+		if !filter.MatchComponentMetaData(components, c) {
+			resultElement.Data = append(resultElement.Data, json.RawMessage("null"))
+			continue
+		}
+		data, err := ecs.GetRawJSONOfComponent(handler.w, c, id)
+		if err != nil {
+			return cql.QueryResponse{}, err
+		}
+		resultElement.Data = append(resultElement.Data, data)
+	}
+	return resultElement, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/runtime/middleware/untyped"
+	"github.com/invopop/jsonschema"
 	"github.com/mitchellh/mapstructure"
 	"github.com/rs/zerolog/log"
 	"pkg.world.dev/world-engine/cardinal/ecs"
@@ -163,6 +164,14 @@ type EndpointsResult struct {
 	TxEndpoints    []string `json:"txEndpoints"`
 	QueryEndpoints []string `json:"queryEndpoints"`
 	DebugEndpoints []string `json:"debugEndpoints"`
+	// Events are the typed events that can be received on the /events websocket.
+	Events []EventDescription `json:"events"`
+}
+
+// EventDescription describes a typed event that can be received on the /events websocket.
+type EventDescription struct {
+	Name   string             `json:"name"`
+	Schema *jsonschema.Schema `json:"schema"`
 }
 
 func createAllEndpoints(world *ecs.World) (*EndpointsResult, error) {
@@ -189,12 +198,23 @@ func createAllEndpoints(world *ecs.World) (*EndpointsResult, error) {
 		"/query/persona/signer",
 		"/query/receipt/list",
 		"/query/game/cql",
+		"/query/state/hash",
+		"/query/state/proof",
 	)
+	evts := world.ListEvents()
+	eventDescriptions := make([]EventDescription, 0, len(evts))
+	for _, evt := range evts {
+		eventDescriptions = append(eventDescriptions, EventDescription{
+			Name:   evt.Name(),
+			Schema: evt.Schema(),
+		})
+	}
 	debugEndpoints := make([]string, 1)
 	debugEndpoints[0] = "/debug/state"
 	return &EndpointsResult{
 		TxEndpoints:    txEndpoints,
 		QueryEndpoints: queryEndpoints,
+		Events:         eventDescriptions,
 	}, nil
 }
 
@@ -3,6 +3,7 @@ package server_test
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"os/exec"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,7 +29,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"pkg.world.dev/world-engine/cardinal/ecs"
+	"pkg.world.dev/world-engine/cardinal/ecs/component/metadata"
 	"pkg.world.dev/world-engine/cardinal/ecs/cql"
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+	"pkg.world.dev/world-engine/cardinal/ecs/merkle"
 	"pkg.world.dev/world-engine/cardinal/server"
 	"pkg.world.dev/world-engine/sign"
 )
@@ -346,6 +351,10 @@ func TestHandleSwaggerServer(t *testing.T) {
 			return expectedReply, nil
 		})
 	assert.NilError(t, w.RegisterQueries(fooQuery))
+	type FooEvent struct {
+		Amount int `json:"amount"`
+	}
+	assert.NilError(t, w.RegisterEvents(ecs.NewEventType[FooEvent]("foo-happened")))
 
 	txh := testutils.MakeTestTransactionHandler(t, w, server.DisableSignatureVerification())
 
@@ -355,7 +364,7 @@ func TestHandleSwaggerServer(t *testing.T) {
 			"/tx/persona/create-persona", "/tx/game/authorize-persona-address", "/tx/game/send-energy"},
 		QueryEndpoints: []string{
 			"/query/game/foo", "/query/http/endpoints", "/query/persona/signer",
-			"/query/receipt/list", "/query/game/cql",
+			"/query/receipt/list", "/query/game/cql", "/query/state/hash", "/query/state/proof",
 		},
 	}
 	resp1, err := http.Post(txh.MakeHTTPURL("query/http/endpoints"), "application/json", nil)
@@ -364,6 +373,10 @@ func TestHandleSwaggerServer(t *testing.T) {
 	var endpointResult server.EndpointsResult
 	err = json.NewDecoder(resp1.Body).Decode(&endpointResult)
 	assert.NilError(t, err)
+	assert.Equal(t, 1, len(endpointResult.Events))
+	assert.Equal(t, "foo-happened", endpointResult.Events[0].Name)
+	assert.Assert(t, endpointResult.Events[0].Schema != nil)
+	endpointResult.Events = nil
 	assert.Assert(t, reflect.DeepEqual(endpointResult, expectedEndpointResult))
 
 	// Test /query/persona/signer
@@ -464,6 +477,9 @@ func TestHandleSwaggerServer(t *testing.T) {
 		{cql: "EXACT(beta)", expectedStatus: 200, amount: 0},
 		{cql: "!(CONTAINS(alpha) | CONTAINS(beta))", expectedStatus: 200, amount: 1},
 		{cql: "!CONTAINS(alpha) & CONTAINS(beta)", expectedStatus: 200, amount: 0},
+		{cql: "CONTAINS(beta) WHERE beta.something = 0", expectedStatus: 200, amount: bothCount},
+		{cql: "CONTAINS(alpha) WHERE alpha.something > 0 OR beta.something <= 0", expectedStatus: 200,
+			amount: bothCount},
 	} {
 		jsonQuery := struct{ CQL string }{v.cql}
 		jsonQueryBytes, err := json.Marshal(jsonQuery)
@@ -477,12 +493,126 @@ func TestHandleSwaggerServer(t *testing.T) {
 		assert.Equal(t, len(entities), v.amount)
 	}
 
-	jsonQuery := struct{ CQL string }{"blah"}
-	jsonQueryBytes, err := json.Marshal(jsonQuery)
-	assert.NilError(t, err)
-	resp8, err := http.Post(txh.MakeHTTPURL("query/game/cql"), "application/json", bytes.NewBuffer(jsonQueryBytes))
-	assert.NilError(t, err)
-	assert.Equal(t, resp8.StatusCode, 422)
+	for _, invalid := range []string{
+		"blah",
+		"CONTAINS(alpha) WHERE alpha.something < true",
+		"CONTAINS(alpha) WHERE alpha.missing = 0",
+		"SELECT SUM(beta.missing) FROM CONTAINS(alpha)",
+	} {
+		jsonQuery := struct{ CQL string }{invalid}
+		jsonQueryBytes, err := json.Marshal(jsonQuery)
+		assert.NilError(t, err)
+		resp8, err := http.Post(txh.MakeHTTPURL("query/game/cql"), "application/json", bytes.NewBuffer(jsonQueryBytes))
+		assert.NilError(t, err)
+		assert.Equal(t, resp8.StatusCode, 422)
+	}
+}
+
+func TestCQLProjectionOrderingAndPagination(t *testing.T) {
+	w := ecs.NewTestWorld(t)
+	assert.NilError(t, w.RegisterTransactions(ecs.NewTransactionType[SendEnergyTx, SendEnergyTxResult]("send-energy")))
+	assert.NilError(t, ecs.RegisterComponent[garbageStructAlpha](w))
+	assert.NilError(t, ecs.RegisterComponent[garbageStructBeta](w))
+	assert.NilError(t, w.LoadGameState())
+	wCtx := ecs.NewWorldContext(w)
+	var ids []entity.ID
+	for i := 0; i < 10; i++ {
+		comps := []metadata.Component{garbageStructAlpha{Something: i % 5}}
+		if i%2 == 0 {
+			comps = append(comps, garbageStructBeta{Something: i})
+		}
+		id, err := component.Create(wCtx, comps...)
+		assert.NilError(t, err)
+		ids = append(ids, id)
+	}
+	assert.NilError(t, w.Tick(context.Background()))
+	txh := testutils.MakeTestTransactionHandler(t, w, server.DisableSignatureVerification())
+
+	post := func(body map[string]string) *http.Response {
+		bz, err := json.Marshal(body)
+		assert.NilError(t, err)
+		resp, err := http.Post(txh.MakeHTTPURL("query/game/cql"), "application/json", bytes.NewBuffer(bz))
+		assert.NilError(t, err)
+		return resp
+	}
+	query := "SELECT beta FROM CONTAINS(alpha) WHERE alpha.something >= 1 ORDER BY alpha.something DESC LIMIT 3"
+	var got []cql.QueryResponse
+	cursor := ""
+	for page := 0; ; page++ {
+		resp := post(map[string]string{"CQL": query, "cursor": cursor})
+		assert.Equal(t, resp.StatusCode, 200)
+		var entities []cql.QueryResponse
+		assert.NilError(t, json.NewDecoder(resp.Body).Decode(&entities))
+		got = append(got, entities...)
+		cursor = resp.Header.Get("X-Next-Cursor")
+		if cursor == "" {
+			assert.Equal(t, 2, page)
+			break
+		}
+		assert.Equal(t, 3, len(entities))
+	}
+
+	// Entities with the same value are sorted by ID, and entities without beta have null data.
+	wantIDs := []entity.ID{ids[4], ids[9], ids[3], ids[8], ids[2], ids[7], ids[1], ids[6]}
+	assert.Equal(t, len(wantIDs), len(got))
+	for i, result := range got {
+		assert.Equal(t, wantIDs[i], result.ID)
+		assert.Equal(t, 1, len(result.Data))
+		if result.ID%2 == 0 {
+			assert.Equal(t, fmt.Sprintf(`{"something":%d}`, result.ID), strings.TrimSpace(string(result.Data[0])))
+		} else {
+			assert.Equal(t, "null", string(result.Data[0]))
+		}
+	}
+
+	// OFFSET skips entities, and cursors only work with the query they came from.
+	resp := post(map[string]string{"CQL": "CONTAINS(alpha) LIMIT 2 OFFSET 8"})
+	var entities []cql.QueryResponse
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&entities))
+	assert.Equal(t, 2, len(entities))
+	assert.Equal(t, ids[8], entities[0].ID)
+	assert.Equal(t, "", resp.Header.Get("X-Next-Cursor"))
+	resp = post(map[string]string{"CQL": query})
+	resp = post(map[string]string{"CQL": "CONTAINS(alpha) LIMIT 2", "cursor": resp.Header.Get("X-Next-Cursor")})
+	assert.Equal(t, resp.StatusCode, 422)
+
+	// Without ORDER BY, entities of different archetypes are paged in order of ID.
+	var pageIDs []entity.ID
+	cursor = ""
+	for {
+		resp = post(map[string]string{"CQL": "CONTAINS(alpha) WHERE alpha.something >= 3 LIMIT 2", "cursor": cursor})
+		assert.Equal(t, resp.StatusCode, 200)
+		entities = nil
+		assert.NilError(t, json.NewDecoder(resp.Body).Decode(&entities))
+		for _, result := range entities {
+			pageIDs = append(pageIDs, result.ID)
+		}
+		if cursor = resp.Header.Get("X-Next-Cursor"); cursor == "" {
+			break
+		}
+	}
+	assert.DeepEqual(t, []entity.ID{ids[3], ids[4], ids[8], ids[9]}, pageIDs)
+
+	// Aggregates return one row per group instead of entities.
+	for _, tc := range []struct {
+		cql  string
+		want string
+	}{
+		{cql: "COUNT(CONTAINS(alpha))", want: `[{"values":[10]}]`},
+		{cql: "COUNT(CONTAINS(beta)) WHERE alpha.something > 2", want: `[{"values":[2]}]`},
+		{cql: "SELECT COUNT(*) FROM CONTAINS(alpha) OFFSET 1", want: `[]`},
+		{
+			cql: "SELECT COUNT(*), SUM(beta.something), AVG(beta.something), MAX(alpha.something) FROM CONTAINS(alpha) " +
+				"GROUP BY alpha.something LIMIT 2",
+			want: `[{"group":[0],"values":[2,0,0,0]},{"group":[1],"values":[2,6,6,1]}]`,
+		},
+		{cql: "SELECT MIN(beta.something), SUM(beta.something) FROM CONTAINS(beta) WHERE alpha.something > 5",
+			want: `[{"values":[null,null]}]`},
+	} {
+		resp = post(map[string]string{"CQL": tc.cql})
+		assert.Equal(t, resp.StatusCode, 200)
+		assert.Equal(t, tc.want, strings.TrimSpace(mustReadBody(t, resp)), tc.cql)
+	}
 }
 
 func TestHandleWrappedTransactionWithNoSignatureVerification(t *testing.T) {
@@ -735,6 +865,8 @@ func TestCanListQueries(t *testing.T) {
 		"/query/persona/signer",
 		"/query/receipt/list",
 		"/query/game/cql",
+		"/query/state/hash",
+		"/query/state/proof",
 	}
 	assert.Equal(t, len(endpoints), len(gotEndpoints["queryEndpoints"]))
 	for i, e := range gotEndpoints["queryEndpoints"] {
@@ -804,6 +936,89 @@ func TestMalformedRequestToGetTransactionReceiptsProducesError(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func TestGetStateHash(t *testing.T) {
+	url := "query/state/hash"
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[garbageStructAlpha](world))
+	world.AddSystem(func(wCtx ecs.WorldContext) error {
+		_, err := component.Create(wCtx, garbageStructAlpha{Something: int(wCtx.CurrentTick())})
+		return err
+	})
+	assert.NilError(t, world.LoadGameState())
+	txh := testutils.MakeTestTransactionHandler(t, world, server.DisableSignatureVerification())
+	ctx := context.Background()
+	assert.NilError(t, world.Tick(ctx))
+	assert.NilError(t, world.Tick(ctx))
+
+	for tick := uint64(0); tick < 2; tick++ {
+		res := txh.Post(url, server.StateHashRequest{Tick: tick})
+		assert.Equal(t, 200, res.StatusCode)
+		var reply server.StateHashReply
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&reply))
+		want, err := world.StateHash(tick)
+		assert.NilError(t, err)
+		assert.Equal(t, tick, reply.Tick)
+		assert.Equal(t, hex.EncodeToString(want), reply.StateHash)
+	}
+
+	// The current tick has not been finalized yet.
+	res := txh.Post(url, server.StateHashRequest{Tick: world.CurrentTick()})
+	assert.Check(t, res.StatusCode != 200)
+}
+
+func TestGetComponentProof(t *testing.T) {
+	url := "query/state/proof"
+	world := ecs.NewTestWorld(t)
+	assert.NilError(t, ecs.RegisterComponent[garbageStructAlpha](world))
+	world.AddSystem(func(wCtx ecs.WorldContext) error {
+		_, err := component.Create(wCtx, garbageStructAlpha{Something: int(wCtx.CurrentTick())})
+		return err
+	})
+	assert.NilError(t, world.LoadGameState())
+	txh := testutils.MakeTestTransactionHandler(t, world, server.DisableSignatureVerification())
+	ctx := context.Background()
+	assert.NilError(t, world.Tick(ctx))
+	assert.NilError(t, world.Tick(ctx))
+
+	res := txh.Post(url, server.ComponentProofRequest{EntityID: 1, Component: garbageStructAlpha{}.Name()})
+	assert.Equal(t, 200, res.StatusCode)
+	var reply server.ComponentProofReply
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&reply))
+	assert.Equal(t, uint64(1), reply.Tick)
+	want, err := world.StateHash(1)
+	assert.NilError(t, err)
+	assert.Equal(t, hex.EncodeToString(want), reply.StateHash)
+	assert.Equal(t, "{\"something\":1}\n", reply.Value)
+
+	// The proof can be verified without trusting the server.
+	proof := &merkle.Proof{}
+	for _, hash := range reply.ValuePath {
+		bz, err := hex.DecodeString(hash)
+		assert.NilError(t, err)
+		proof.ValuePath = append(proof.ValuePath, bz)
+	}
+	for _, hash := range reply.TypePath {
+		bz, err := hex.DecodeString(hash)
+		assert.NilError(t, err)
+		proof.TypePath = append(proof.TypePath, bz)
+	}
+	assert.NilError(t, merkle.Verify(want, reply.ComponentTypeID, uint64(reply.EntityID), []byte(reply.Value), proof))
+	assert.Check(t, merkle.Verify(want, reply.ComponentTypeID, uint64(reply.EntityID), []byte("{}"), proof) != nil)
+
+	// Proofs can be built against the state hash of an older tick.
+	tick := uint64(0)
+	res = txh.Post(url, server.ComponentProofRequest{EntityID: 0, Component: garbageStructAlpha{}.Name(), Tick: &tick})
+	assert.Equal(t, 200, res.StatusCode)
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&reply))
+	assert.Equal(t, uint64(0), reply.Tick)
+	assert.Equal(t, "{\"something\":0}\n", reply.Value)
+
+	res = txh.Post(url, server.ComponentProofRequest{EntityID: 5, Component: garbageStructAlpha{}.Name()})
+	assert.Check(t, res.StatusCode != 200)
+	res = txh.Post(url, server.ComponentProofRequest{EntityID: 1, Component: "unknown"})
+	assert.Check(t, res.StatusCode != 200)
+}
+
 func TestTransactionReceiptReturnCorrectTickWindows(t *testing.T) {
 	url := "query/receipts/list"
 
@@ -1087,12 +1302,18 @@ func TestTransactionIDIsReturned(t *testing.T) {
 var _ shard.Adapter = &adapterMock{}
 
 type adapterMock struct {
-	called int
-	hold   chan bool
+	called     int
+	epochs     []uint64
+	timestamps []uint64
+	txs        int
+	hold       chan bool
 }
 
-func (a *adapterMock) Submit(_ context.Context, _ *sign.Transaction, _, _ uint64) error {
+func (a *adapterMock) SubmitEpoch(_ context.Context, epoch *shard.Epoch) error {
 	a.called++
+	a.epochs = append(a.epochs, epoch.Epoch)
+	a.timestamps = append(a.timestamps, epoch.Timestamp)
+	a.txs += len(epoch.Txs)
 	return nil
 }
 
@@ -1109,11 +1330,11 @@ func TestTransactionsSubmittedToChain(t *testing.T) {
 	type MoveTx struct {
 		Direction string
 	}
-	world := ecs.NewTestWorld(t)
+	adapter := adapterMock{}
+	world := ecs.NewTestWorld(t, ecs.WithAdapter(&adapter))
 	moveTx := ecs.NewTransactionType[MoveTx, MoveTx]("move")
 	assert.NilError(t, world.RegisterTransactions(moveTx))
 	assert.NilError(t, world.LoadGameState())
-	adapter := adapterMock{}
 	txh := testutils.MakeTestTransactionHandler(t, world, server.WithAdapter(&adapter),
 		server.DisableSignatureVerification())
 
@@ -1133,6 +1354,10 @@ func TestTransactionsSubmittedToChain(t *testing.T) {
 	resp, err := http.Post(txh.MakeHTTPURL(createPersonaEndpoint), "application/json", bytes.NewReader(bz))
 	assert.NilError(t, err)
 	assert.Equal(t, 200, resp.StatusCode)
+	// Transactions are submitted to the chain once the tick that processes them is done.
+	assert.Equal(t, adapter.called, 0)
+	assert.NilError(t, world.Tick(context.Background()))
+	assert.NilError(t, world.SubmitQueuedEpochs(context.Background()))
 	assert.Equal(t, adapter.called, 1)
 
 	sigPayload, err = sign.NewTransaction(privateKey, personaTag, world.Namespace().String(), 2,
@@ -1143,7 +1368,14 @@ func TestTransactionsSubmittedToChain(t *testing.T) {
 	resp, err = http.Post(txh.MakeHTTPURL(moveEndpoint), "application/json", bytes.NewReader(bz))
 	assert.NilError(t, err)
 	assert.Equal(t, 200, resp.StatusCode)
+	assert.NilError(t, world.Tick(context.Background()))
+	assert.NilError(t, world.SubmitQueuedEpochs(context.Background()))
 	assert.Equal(t, adapter.called, 2)
+	assert.Equal(t, adapter.txs, 2)
+	// Each transaction is submitted with the tick that processed it, and the time that tick was started.
+	assert.DeepEqual(t, []uint64{0, 1}, adapter.epochs)
+	assert.Check(t, adapter.timestamps[0] > 0)
+	assert.Check(t, adapter.timestamps[1] >= adapter.timestamps[0])
 }
 
 func TestTransactionNotSubmittedWhenRecovering(t *testing.T) {
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"pkg.world.dev/world-engine/cardinal/ecs/entity"
+)
+
+// StateHashRequest is the request body for the /query/state/hash endpoint.
+type StateHashRequest struct {
+	Tick uint64 `json:"tick" mapstructure:"tick"`
+}
+
+// StateHashReply contains the hex encoded state hash at the end of the requested tick. Two game shards that agree on
+// the state of a tick have the same state hash for that tick.
+type StateHashReply struct {
+	Tick      uint64 `json:"tick"`
+	StateHash string `json:"stateHash"`
+}
+
+func (handler *Handler) getStateHashReply(req *StateHashRequest) (*StateHashReply, error) {
+	hash, err := handler.w.StateHash(req.Tick)
+	if err != nil {
+		return nil, err
+	}
+	return &StateHashReply{
+		Tick:      req.Tick,
+		StateHash: hex.EncodeToString(hash),
+	}, nil
+}
+
+// ComponentProofRequest is the request body for the /query/state/proof endpoint.
+type ComponentProofRequest struct {
+	EntityID  entity.ID `json:"entityId" mapstructure:"entityId"`
+	Component string    `json:"component" mapstructure:"component"`
+	// Tick is the tick whose state hash the proof is against. The last finalized tick is used if it is not set.
+	Tick *uint64 `json:"tick,omitempty" mapstructure:"tick"`
+}
+
+// ComponentProofReply contains the value of a component at the end of the requested tick, and a Merkle proof
+// that the value is part of that tick's state hash. Hashes are hex encoded. Value is the component value exactly as it
+// was hashed, so it must not be re-encoded before it is verified. See the merkle package for how to verify the proof.
+type ComponentProofReply struct {
+	Tick            uint64    `json:"tick"`
+	StateHash       string    `json:"stateHash"`
+	ComponentTypeID uint64    `json:"componentTypeId"`
+	EntityID        entity.ID `json:"entityId"`
+	Value           string    `json:"value"`
+	ValuePath       []string  `json:"valuePath"`
+	TypePath        []string  `json:"typePath"`
+}
+
+func (handler *Handler) getComponentProofReply(req *ComponentProofRequest) (*ComponentProofReply, error) {
+	cType, err := handler.w.GetComponentByName(req.Component)
+	if err != nil {
+		return nil, err
+	}
+	var tick uint64
+	if req.Tick != nil {
+		tick = *req.Tick
+	} else if current := handler.w.CurrentTick(); current > 0 {
+		tick = current - 1
+	} else {
+		return nil, errors.New("no tick has been finalized yet")
+	}
+	proof, err := handler.w.GetComponentProof(cType, req.EntityID, tick)
+	if err != nil {
+		return nil, err
+	}
+	return &ComponentProofReply{
+		Tick:            proof.Tick,
+		StateHash:       hex.EncodeToString(proof.StateHash),
+		ComponentTypeID: uint64(proof.ComponentTypeID),
+		EntityID:        proof.EntityID,
+		Value:           string(proof.Value),
+		ValuePath:       encodeHashes(proof.Proof.ValuePath),
+		TypePath:        encodeHashes(proof.Proof.TypePath),
+	}, nil
+}
+
+func encodeHashes(hashes [][]byte) []string {
+	encoded := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		encoded = append(encoded, hex.EncodeToString(hash))
+	}
+	return encoded
+}
@@ -1,7 +1,6 @@
 package server
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -115,28 +114,19 @@ func (handler *Handler) registerTxHandlerSwagger(api *untyped.API) error {
 	return nil
 }
 
-// submitTransaction submits a transaction to the game world, as well as the blockchain.
+// submitTransaction submits a transaction to the game world. If the world has an adapter, the world submits the
+// transaction to the blockchain when the tick that processes it is started.
 func (handler *Handler) submitTransaction(txVal any, tx transaction.ITransaction, sp *sign.Transaction,
 ) (*TransactionReply, error) {
+	// if the world is recovering via adapter, we shouldn't accept transactions.
+	if handler.adapter != nil && handler.w.IsRecovering() {
+		return nil, errors.New("unable to submit transactions: game world is recovering state")
+	}
 	log.Debug().Msgf("submitting transaction %d: %v", tx.ID(), txVal)
 	tick, txHash := handler.w.AddTransaction(tx.ID(), txVal, sp)
 	txReply := &TransactionReply{
 		TxHash: string(txHash),
 		Tick:   tick,
 	}
-	// check if we have an adapter
-	if handler.adapter != nil {
-		// if the world is recovering via adapter, we shouldn't accept transactions.
-		if handler.w.IsRecovering() {
-			return nil, errors.New("unable to submit transactions: game world is recovering state")
-		}
-		log.Debug().Msgf("TX %d: tick %d: hash %s: submitted to base shard", tx.ID(), txReply.Tick, txReply.TxHash)
-		err := handler.adapter.Submit(context.Background(), sp, uint64(tx.ID()), txReply.Tick)
-		if err != nil {
-			return nil, fmt.Errorf("error submitting transaction to base shard: %w", err)
-		}
-	} else {
-		log.Debug().Msg("not submitting transaction to base shard")
-	}
 	return txReply, nil
 }
@@ -24,9 +24,29 @@ type Adapter interface {
 
 // WriteAdapter provides the functionality to send transactions to the EVM base shard.
 type WriteAdapter interface {
-	// Submit submits a transaction to the EVM base shard's game tx sequencer, where the tx data will be sequenced and
-	// stored on chain.
-	Submit(ctx context.Context, p *sign.Transaction, txID, epoch uint64) error
+	// SubmitEpoch submits every transaction of an epoch to the EVM base shard's game tx sequencer, where the tx data will
+	// be sequenced and stored on chain. The transactions replace any that were submitted for the same namespace and
+	// epoch before, so an epoch can safely be submitted again if a previous attempt failed.
+	SubmitEpoch(ctx context.Context, epoch *Epoch) error
+}
+
+// Epoch is a tick whose transactions and timestamp are stored on the EVM base shard, so the tick can be replayed during
+// recovery.
+type Epoch struct {
+	Namespace string
+	Epoch     uint64
+	// Timestamp is the unix time, in milliseconds, of the epoch's tick.
+	Timestamp uint64
+	// PrevStateHash is the state hash at the end of the previous tick. It may be nil.
+	PrevStateHash []byte
+	// Txs are the transactions of the epoch, in the order they were run.
+	Txs []EpochTransaction
+}
+
+// EpochTransaction is a transaction of an Epoch, along with the ID of its transaction type.
+type EpochTransaction struct {
+	TxID uint64
+	Tx   *sign.Transaction
 }
 
 // QueryAdapter provides the functionality to query transactions from the EVM base shard.
@@ -101,9 +121,18 @@ func NewAdapter(cfg AdapterConfig, opts ...Option) (Adapter, error) {
 	return a, nil
 }
 
-func (a adapterImpl) Submit(ctx context.Context, sp *sign.Transaction, txID uint64, epoch uint64) error {
-	req := &shardv1.SubmitShardTxRequest{Tx: transactionToProto(sp), Epoch: epoch, TxId: txID}
-	_, err := a.ShardSequencer.SubmitShardTx(ctx, req)
+func (a adapterImpl) SubmitEpoch(ctx context.Context, epoch *Epoch) error {
+	req := &shardv1.SubmitShardEpochRequest{
+		Namespace:     epoch.Namespace,
+		Epoch:         epoch.Epoch,
+		Timestamp:     epoch.Timestamp,
+		PrevStateHash: epoch.PrevStateHash,
+		Txs:           make([]*shardv1.EpochTransaction, 0, len(epoch.Txs)),
+	}
+	for _, tx := range epoch.Txs {
+		req.Txs = append(req.Txs, &shardv1.EpochTransaction{TxId: tx.TxID, Tx: transactionToProto(tx.Tx)})
+	}
+	_, err := a.ShardSequencer.SubmitShardEpoch(ctx, req)
 	return err
 }
 
@@ -83,3 +83,51 @@ func TestCanRegisterMultipleSystem(t *testing.T) {
 	assert.Check(t, firstSystemCalled)
 	assert.Check(t, secondSystemCalled)
 }
+
+type Mana struct {
+	Value int
+}
+
+func (Mana) Name() string { return "mana" }
+
+func TestSystemsWithDeclaredAccess(t *testing.T) {
+	world, doTick := testutils.MakeWorldAndTicker(t)
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[Mana](world))
+	cardinal.RegisterSystem(world, func(worldCtx cardinal.WorldContext) error {
+		q, err := worldCtx.NewSearch(cardinal.Contains(Health{}))
+		if err != nil {
+			return err
+		}
+		return q.Each(worldCtx, func(id cardinal.EntityID) bool {
+			return cardinal.UpdateComponent[Health](worldCtx, id, func(h *Health) *Health {
+				h.Value++
+				return h
+			}) == nil
+		})
+	}, cardinal.Writes(Health{}))
+	var manaErr error
+	cardinal.RegisterSystem(world, func(worldCtx cardinal.WorldContext) error {
+		q, err := worldCtx.NewSearch(cardinal.Contains(Mana{}))
+		if err != nil {
+			return err
+		}
+		return q.Each(worldCtx, func(id cardinal.EntityID) bool {
+			// Health was not declared, so it can't be changed here.
+			manaErr = cardinal.SetComponent[Health](worldCtx, id, &Health{Value: 100})
+			return manaErr == nil
+		})
+	}, cardinal.Reads(Mana{}))
+
+	worldCtx := testutils.WorldToWorldContext(world)
+	id, err := cardinal.Create(worldCtx, Health{}, Mana{})
+	assert.NilError(t, err)
+
+	doTick()
+	doTick()
+
+	health, err := cardinal.GetComponent[Health](worldCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, health.Value)
+	assert.Check(t, manaErr != nil)
+}
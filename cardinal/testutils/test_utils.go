@@ -27,7 +27,8 @@ func MakeTestTransactionHandler(t *testing.T, world *ecs.World, opts ...server.O
 	opts = append(opts, server.WithPort(port))
 	eventHub := events.CreateWebSocketEventHub()
 	world.SetEventHub(eventHub)
-	eventBuilder := events.CreateNewWebSocketBuilder("/events", events.CreateWebSocketEventHandler(eventHub))
+	eventBuilder := events.CreateNewWebSocketBuilder("/events", events.CreateWebSocketEventHandler(eventHub,
+		world.ParseChangeFilter, world.AuthenticateEventConnection, world.ReadEventLog))
 	txh, err := server.NewHandler(world, eventBuilder, opts...)
 	assert.NilError(t, err)
 
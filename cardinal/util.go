@@ -22,6 +22,14 @@ func toIQueryType(ins []AnyQueryType) []ecs.IQuery {
 	return out
 }
 
+func toIEventType(ins []AnyEventType) []ecs.IEvent {
+	out := make([]ecs.IEvent, 0, len(ins))
+	for _, e := range ins {
+		out = append(out, e.Convert())
+	}
+	return out
+}
+
 // separateOptions separates the given options into ecs options, server options, and cardinal (this package) options.
 // The different options are all grouped together to simplify the end user's experience, but under the hood different
 // options are meant for different sub-systems.
@@ -20,6 +20,7 @@ import (
 	"pkg.world.dev/world-engine/cardinal/ecs/entity"
 	"pkg.world.dev/world-engine/cardinal/ecs/receipt"
 	"pkg.world.dev/world-engine/cardinal/ecs/storage"
+	"pkg.world.dev/world-engine/cardinal/ecs/store"
 	"pkg.world.dev/world-engine/cardinal/ecs/transaction"
 	"pkg.world.dev/world-engine/cardinal/events"
 	"pkg.world.dev/world-engine/cardinal/evm"
@@ -45,16 +46,47 @@ type (
 	EntityID = entity.ID
 	TxHash   = transaction.TxHash
 	Receipt  = receipt.Receipt
+	// TickDiff is the set of entity and component changes that were committed at the end of a single tick.
+	TickDiff = store.TickDiff
+	// ComponentProof is a component value together with a proof that it is part of a tick's state hash.
+	ComponentProof = store.ComponentProof
 
 	// System is a function that process the transaction in the given transaction queue.
 	// Systems are automatically called during a world tick, and they must be registered
 	// with a world using AddSystem or AddSystems.
 	System func(WorldContext) error
+
+	// SystemOption configures how a system is scheduled. See Reads, Writes, InPhase, Before and After.
+	SystemOption = ecs.SystemOption
+
+	// Phase is a named stage of a tick. Every system in a phase finishes before any system in the next phase starts.
+	Phase = ecs.Phase
+
+	// ChildOf is a relation component that makes an entity a child of another entity. It must be registered with
+	// RegisterComponents before it is used.
+	ChildOf = ecs.ChildOf
+
+	// RemovePolicy decides what happens to the entities that refer to a removed entity. See RemoveWithPolicy.
+	RemovePolicy = ecs.RemovePolicy
+
+	// ComponentOption configures a component when it is registered. See WithIndex.
+	ComponentOption = ecs.ComponentOption
+)
+
+const (
+	PreUpdate  = ecs.PreUpdate
+	Update     = ecs.Update
+	PostUpdate = ecs.PostUpdate
+)
+
+const (
+	RemoveRestrict = ecs.RemoveRestrict
+	RemoveCascade  = ecs.RemoveCascade
+	RemoveDetach   = ecs.RemoveDetach
 )
 
 // NewWorld creates a new World object using Redis as the storage layer.
 func NewWorld(addr, password string, opts ...WorldOption) (*World, error) {
-	ecsOptions, serverOptions, cardinalOptions := separateOptions(opts)
 	log.Info().Msg("Running in normal mode, using external Redis")
 	if addr == "" {
 		return nil, errors.New("redis address is required")
@@ -69,14 +101,36 @@ func NewWorld(addr, password string, opts ...WorldOption) (*World, error) {
 		DB:       0,        // use default DB
 	}, "world")
 	log.Info().Msgf("redis address: %s", addr)
-	storeManager, err := ecb.NewManager(redisStore.Client)
+	return newWorld(ecb.NewRedisKVStore(redisStore.Client), &redisStore, opts...)
+}
+
+// NewEmbeddedWorld creates a new World object that persists all state (including nonces) to the given local
+// directory. No redis DB is required. The directory will be created if it does not exist.
+func NewEmbeddedWorld(dataDir string, opts ...WorldOption) (*World, error) {
+	log.Info().Msgf("Running in embedded mode, using data directory %q", dataDir)
+	if dataDir == "" {
+		return nil, errors.New("data directory is required")
+	}
+
+	kv, err := ecb.NewDiskKVStore(dataDir)
 	if err != nil {
 		return nil, err
 	}
+	return newWorld(kv, ecb.NewKVNonceStorage(kv), opts...)
+}
 
-	ecsWorld, err := ecs.NewWorld(&redisStore, storeManager, ecsOptions...)
+// newWorld creates a new World object that saves its state to the given KVStore and its nonces to the given
+// NonceStorage. The KVStore is closed if the World cannot be created.
+func newWorld(kv ecb.KVStore, nonceStore storage.NonceStorage, opts ...WorldOption) (*World, error) {
+	ecsOptions, serverOptions, cardinalOptions := separateOptions(opts)
+	storeManager, err := ecb.NewManagerWithKVStore(kv)
 	if err != nil {
-		return nil, err
+		return nil, errors.Join(err, kv.Close())
+	}
+
+	ecsWorld, err := ecs.NewWorld(nonceStore, storeManager, ecsOptions...)
+	if err != nil {
+		return nil, errors.Join(err, kv.Close())
 	}
 
 	world := &World{
@@ -92,7 +146,7 @@ func NewWorld(addr, password string, opts ...WorldOption) (*World, error) {
 	return world, nil
 }
 
-// NewMockWorld creates a World that uses an in-memory redis DB as the storage layer.
+// NewMockWorld creates a World that keeps all state in process memory. No redis DB is required.
 // This is only suitable for local development.
 func NewMockWorld(opts ...WorldOption) (*World, error) {
 	ecsOptions, serverOptions, cardinalOptions := separateOptions(opts)
@@ -149,9 +203,36 @@ func RemoveComponentFrom[T metadata.Component](wCtx WorldContext, id entity.ID)
 	return component.RemoveComponentFrom[T](wCtx.getECSWorldContext(), id)
 }
 
-// Remove removes the given entity id from the world.
+// Remove removes the given entity id from the world. An error is returned if another entity refers to it through a
+// relation such as ChildOf.
 func Remove(wCtx WorldContext, id EntityID) error {
-	return wCtx.getECSWorldContext().GetWorld().Remove(id)
+	return ecs.RemoveEntity(wCtx.getECSWorldContext(), id, RemoveRestrict)
+}
+
+// RemoveWithPolicy removes the given entity id from the world, and handles the entities that refer to it through a
+// relation according to the given policy.
+func RemoveWithPolicy(wCtx WorldContext, id EntityID, policy RemovePolicy) error {
+	return ecs.RemoveEntity(wCtx.getECSWorldContext(), id, policy)
+}
+
+// GetChildren returns the entities that are a ChildOf the given parent, sorted by ID.
+func GetChildren(wCtx WorldContext, parent EntityID) ([]EntityID, error) {
+	return ecs.GetChildren(wCtx.getECSWorldContext(), parent)
+}
+
+// GetParent returns the parent of the given entity. false is returned if the entity does not have a parent.
+func GetParent(wCtx WorldContext, child EntityID) (EntityID, bool, error) {
+	return ecs.GetParent(wCtx.getECSWorldContext(), child)
+}
+
+// SetParent makes child a ChildOf parent, replacing any parent it already had.
+func SetParent(wCtx WorldContext, child, parent EntityID) error {
+	return ecs.SetParent(wCtx.getECSWorldContext(), child, parent)
+}
+
+// RemoveParent removes the parent of the given entity.
+func RemoveParent(wCtx WorldContext, child EntityID) error {
+	return ecs.RemoveParent(wCtx.getECSWorldContext(), child)
 }
 
 func (w *World) handleShutdown() {
@@ -185,7 +266,8 @@ func (w *World) StartGame() error {
 	}
 	eventHub := events.CreateWebSocketEventHub()
 	w.implWorld.SetEventHub(eventHub)
-	eventBuilder := events.CreateNewWebSocketBuilder("/events", events.CreateWebSocketEventHandler(eventHub))
+	eventBuilder := events.CreateNewWebSocketBuilder("/events", events.CreateWebSocketEventHandler(eventHub,
+		w.implWorld.ParseChangeFilter, w.implWorld.AuthenticateEventConnection, w.implWorld.ReadEventLog))
 	handler, err := server.NewHandler(w.implWorld, eventBuilder, w.serverOptions...)
 	if err != nil {
 		return err
@@ -207,7 +289,7 @@ func (w *World) StartGame() error {
 	}
 
 	if w.tickChannel == nil {
-		w.tickChannel = time.Tick(time.Second) //nolint:staticcheck // its ok.
+		w.tickChannel = time.Tick(w.implWorld.TickInterval()) //nolint:staticcheck // its ok.
 	}
 	w.implWorld.StartGameLoop(context.Background(), w.tickChannel, w.tickDoneChannel)
 	gameManager := server.NewGameManager(w.implWorld, w.server)
@@ -251,18 +333,86 @@ func (w *World) ShutDown() error {
 
 func RegisterSystems(w *World, systems ...System) {
 	for _, system := range systems {
-		functionName := filepath.Base(runtime.FuncForPC(reflect.ValueOf(system).Pointer()).Name())
-		sys := system
-		w.implWorld.AddSystemWithName(func(wCtx ecs.WorldContext) error {
-			return sys(&worldContext{
-				implContext: wCtx,
-			})
-		}, functionName)
+		RegisterSystem(w, system)
 	}
 }
 
-func RegisterComponent[T metadata.Component](world *World) error {
-	return ecs.RegisterComponent[T](world.implWorld)
+// RegisterSystem adds a single system to the world. Systems run in the order they are registered, unless their phases
+// or Before and After constraints say otherwise. Systems that declare the components they use with Reads and Writes
+// run at the same time as other systems that don't write to those components. Such systems can only get and set the
+// components they declared, and they cannot create or remove entities or add or remove components. Systems that don't
+// declare anything can use any state, and never run at the same time as another system.
+func RegisterSystem(w *World, system System, opts ...SystemOption) {
+	functionName := filepath.Base(runtime.FuncForPC(reflect.ValueOf(system).Pointer()).Name())
+	w.implWorld.AddSystemWithOptions(func(wCtx ecs.WorldContext) error {
+		return system(&worldContext{
+			implContext: wCtx,
+		})
+	}, functionName, opts...)
+}
+
+// Reads declares that a system reads the given components.
+func Reads(components ...metadata.Component) SystemOption {
+	return ecs.Reads(components...)
+}
+
+// Writes declares that a system sets the value of the given components. Writing a component implies reading it.
+func Writes(components ...metadata.Component) SystemOption {
+	return ecs.Writes(components...)
+}
+
+// InPhase puts a system in the given phase. Systems are in the Update phase unless this option is used. The persona
+// systems that cardinal adds itself are in the PreUpdate phase.
+func InPhase(phase Phase) SystemOption {
+	return ecs.InPhase(phase)
+}
+
+// Before makes a system run before the systems with the given names. A system's name is the package and name of its
+// function, e.g. "game.MoveSystem".
+func Before(systemNames ...string) SystemOption {
+	return ecs.Before(systemNames...)
+}
+
+// After makes a system run after the systems with the given names. A system's name is the package and name of its
+// function, e.g. "game.MoveSystem".
+func After(systemNames ...string) SystemOption {
+	return ecs.After(systemNames...)
+}
+
+// EveryNTicks makes a system only run in ticks whose tick number is a multiple of n.
+func EveryNTicks(n uint64) SystemOption {
+	return ecs.EveryNTicks(n)
+}
+
+// Interval makes a system run about once every d. The interval is converted into a whole number of ticks using the
+// world's tick interval (see WithTickInterval), so the system runs in the same ticks every time the world is replayed.
+func Interval(d time.Duration) SystemOption {
+	return ecs.Interval(d)
+}
+
+// OnTransactions makes a system only run in ticks where at least one transaction of the given types is queued.
+func OnTransactions(txs ...AnyTransaction) SystemOption {
+	return ecs.OnTransactions(toITransactionType(txs)...)
+}
+
+// RegisterPhaseBefore adds a new phase that runs directly before an existing phase.
+func RegisterPhaseBefore(w *World, phase, before Phase) error {
+	return w.implWorld.AddPhaseBefore(phase, before)
+}
+
+// RegisterPhaseAfter adds a new phase that runs directly after an existing phase.
+func RegisterPhaseAfter(w *World, phase, after Phase) error {
+	return w.implWorld.AddPhaseAfter(phase, after)
+}
+
+func RegisterComponent[T metadata.Component](world *World, opts ...ComponentOption) error {
+	return ecs.RegisterComponent[T](world.implWorld, opts...)
+}
+
+// WithIndex indexes the given field of a component, so entities can be found by the value of the field with
+// Search.Where.
+func WithIndex(field string) ComponentOption {
+	return ecs.WithIndex(field)
 }
 
 // RegisterTransactions adds the given transactions to the game world. HTTP endpoints to queue up/execute these
@@ -277,6 +427,12 @@ func RegisterQueries(w *World, queries ...AnyQueryType) error {
 	return w.implWorld.RegisterQueries(toIQueryType(queries)...)
 }
 
+// RegisterEvents adds the given event types to the game world. Only registered event types can be emitted, and
+// the registered event types are listed, along with their schemas, by the /query/http/endpoints endpoint.
+func RegisterEvents(w *World, evts ...AnyEventType) error {
+	return w.implWorld.RegisterEvents(toIEventType(evts)...)
+}
+
 func (w *World) CurrentTick() uint64 {
 	return w.implWorld.CurrentTick()
 }
@@ -285,6 +441,31 @@ func (w *World) Tick(ctx context.Context) error {
 	return w.implWorld.Tick(ctx)
 }
 
+// StateHash returns the hash of the world's state at the end of the given tick. Worlds that processed the same
+// transactions have the same state hash, so comparing state hashes is a cheap way to check that two game shards agree.
+func (w *World) StateHash(tick uint64) ([]byte, error) {
+	return w.implWorld.StateHash(tick)
+}
+
+// GetComponentProof returns the value of the component T on the given entity at the end of the given tick, along with
+// a Merkle proof that the value is part of that tick's state hash. The proof can be checked with merkle.Verify without
+// trusting this world. Proofs can only be built for recently finalized ticks (see WithProofRetention).
+func GetComponentProof[T metadata.Component](w *World, id EntityID, tick uint64) (*ComponentProof, error) {
+	var t T
+	cType, err := w.implWorld.GetComponentByName(t.Name())
+	if err != nil {
+		return nil, err
+	}
+	return w.implWorld.GetComponentProof(cType, id, tick)
+}
+
+// SubscribeToTickDiffs registers a handler that is called with the state changes of every tick after the tick has
+// been committed to storage. The handler is called on the game loop's goroutine, so it should return quickly. The
+// returned function removes the subscription.
+func (w *World) SubscribeToTickDiffs(handler func(diff *TickDiff)) (unsubscribe func()) {
+	return w.implWorld.SubscribeToTickDiffs(handler)
+}
+
 func (w *World) Init(fn func(WorldContext)) {
 	ecsWorldCtx := ecs.NewWorldContext(w.implWorld)
 	fn(&worldContext{implContext: ecsWorldCtx})
@@ -1,6 +1,9 @@
 package cardinal
 
 import (
+	"math/rand"
+	"time"
+
 	"github.com/rs/zerolog"
 	"pkg.world.dev/world-engine/cardinal/ecs"
 	"pkg.world.dev/world-engine/cardinal/events"
@@ -10,7 +13,16 @@ type WorldContext interface {
 	NewSearch(filter Filter) (*Search, error)
 	CurrentTick() uint64
 	EmitEvent(event string)
+	// EmitEventTo emits an event that is only sent to clients that have authenticated as the given persona.
+	EmitEventTo(personaTag, event string)
 	Logger() *zerolog.Logger
+	// Rand returns a random number source for the current system. The numbers only depend on the namespace, the tick,
+	// the tick's transactions and the name of the system, so they are the same every time the tick is replayed.
+	Rand() *rand.Rand
+	// Timestamp returns the timestamp of the current tick. Ticks with transactions use the time they were started,
+	// and a tick without transactions is one tick interval (see WithTickInterval) after the previous tick, so
+	// recovering the world replays every tick with the timestamp it originally had.
+	Timestamp() time.Time
 	getECSWorldContext() ecs.WorldContext
 }
 
@@ -19,7 +31,11 @@ type worldContext struct {
 }
 
 func (wCtx *worldContext) EmitEvent(event string) {
-	wCtx.getECSWorldContext().GetWorld().EmitEvent(&events.Event{Message: event})
+	wCtx.getECSWorldContext().EmitEvent(&events.Event{Message: event})
+}
+
+func (wCtx *worldContext) EmitEventTo(personaTag, event string) {
+	wCtx.getECSWorldContext().EmitEvent(&events.Event{Message: event, PersonaTag: personaTag})
 }
 
 func (wCtx *worldContext) CurrentTick() uint64 {
@@ -30,6 +46,14 @@ func (wCtx *worldContext) Logger() *zerolog.Logger {
 	return wCtx.implContext.Logger()
 }
 
+func (wCtx *worldContext) Rand() *rand.Rand {
+	return wCtx.implContext.Rand()
+}
+
+func (wCtx *worldContext) Timestamp() time.Time {
+	return wCtx.implContext.Timestamp()
+}
+
 func (wCtx *worldContext) NewSearch(filter Filter) (*Search, error) {
 	ecsSearch, err := wCtx.implContext.NewSearch(filter.convertToFilterable())
 	if err != nil {
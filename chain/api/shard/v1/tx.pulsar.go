@@ -66,11 +66,13 @@ func (x *_SubmitShardTxRequest_4_list) IsValid() bool {
 }
 
 var (
-	md_SubmitShardTxRequest           protoreflect.MessageDescriptor
-	fd_SubmitShardTxRequest_sender    protoreflect.FieldDescriptor
-	fd_SubmitShardTxRequest_namespace protoreflect.FieldDescriptor
-	fd_SubmitShardTxRequest_epoch     protoreflect.FieldDescriptor
-	fd_SubmitShardTxRequest_txs       protoreflect.FieldDescriptor
+	md_SubmitShardTxRequest                 protoreflect.MessageDescriptor
+	fd_SubmitShardTxRequest_sender          protoreflect.FieldDescriptor
+	fd_SubmitShardTxRequest_namespace       protoreflect.FieldDescriptor
+	fd_SubmitShardTxRequest_epoch           protoreflect.FieldDescriptor
+	fd_SubmitShardTxRequest_txs             protoreflect.FieldDescriptor
+	fd_SubmitShardTxRequest_timestamp       protoreflect.FieldDescriptor
+	fd_SubmitShardTxRequest_prev_state_hash protoreflect.FieldDescriptor
 )
 
 func init() {
@@ -80,6 +82,8 @@ func init() {
 	fd_SubmitShardTxRequest_namespace = md_SubmitShardTxRequest.Fields().ByName("namespace")
 	fd_SubmitShardTxRequest_epoch = md_SubmitShardTxRequest.Fields().ByName("epoch")
 	fd_SubmitShardTxRequest_txs = md_SubmitShardTxRequest.Fields().ByName("txs")
+	fd_SubmitShardTxRequest_timestamp = md_SubmitShardTxRequest.Fields().ByName("timestamp")
+	fd_SubmitShardTxRequest_prev_state_hash = md_SubmitShardTxRequest.Fields().ByName("prev_state_hash")
 }
 
 var _ protoreflect.Message = (*fastReflection_SubmitShardTxRequest)(nil)
@@ -171,6 +175,18 @@ func (x *fastReflection_SubmitShardTxRequest) Range(f func(protoreflect.FieldDes
 			return
 		}
 	}
+	if x.Timestamp != uint64(0) {
+		value := protoreflect.ValueOfUint64(x.Timestamp)
+		if !f(fd_SubmitShardTxRequest_timestamp, value) {
+			return
+		}
+	}
+	if len(x.PrevStateHash) != 0 {
+		value := protoreflect.ValueOfBytes(x.PrevStateHash)
+		if !f(fd_SubmitShardTxRequest_prev_state_hash, value) {
+			return
+		}
+	}
 }
 
 // Has reports whether a field is populated.
@@ -194,6 +210,10 @@ func (x *fastReflection_SubmitShardTxRequest) Has(fd protoreflect.FieldDescripto
 		return x.Epoch != uint64(0)
 	case "shard.v1.SubmitShardTxRequest.txs":
 		return len(x.Txs) != 0
+	case "shard.v1.SubmitShardTxRequest.timestamp":
+		return x.Timestamp != uint64(0)
+	case "shard.v1.SubmitShardTxRequest.prev_state_hash":
+		return len(x.PrevStateHash) != 0
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -218,6 +238,10 @@ func (x *fastReflection_SubmitShardTxRequest) Clear(fd protoreflect.FieldDescrip
 		x.Epoch = uint64(0)
 	case "shard.v1.SubmitShardTxRequest.txs":
 		x.Txs = nil
+	case "shard.v1.SubmitShardTxRequest.timestamp":
+		x.Timestamp = uint64(0)
+	case "shard.v1.SubmitShardTxRequest.prev_state_hash":
+		x.PrevStateHash = nil
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -249,6 +273,12 @@ func (x *fastReflection_SubmitShardTxRequest) Get(descriptor protoreflect.FieldD
 		}
 		listValue := &_SubmitShardTxRequest_4_list{list: &x.Txs}
 		return protoreflect.ValueOfList(listValue)
+	case "shard.v1.SubmitShardTxRequest.timestamp":
+		value := x.Timestamp
+		return protoreflect.ValueOfUint64(value)
+	case "shard.v1.SubmitShardTxRequest.prev_state_hash":
+		value := x.PrevStateHash
+		return protoreflect.ValueOfBytes(value)
 	default:
 		if descriptor.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -279,6 +309,10 @@ func (x *fastReflection_SubmitShardTxRequest) Set(fd protoreflect.FieldDescripto
 		lv := value.List()
 		clv := lv.(*_SubmitShardTxRequest_4_list)
 		x.Txs = *clv.list
+	case "shard.v1.SubmitShardTxRequest.timestamp":
+		x.Timestamp = value.Uint()
+	case "shard.v1.SubmitShardTxRequest.prev_state_hash":
+		x.PrevStateHash = value.Bytes()
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -311,6 +345,10 @@ func (x *fastReflection_SubmitShardTxRequest) Mutable(fd protoreflect.FieldDescr
 		panic(fmt.Errorf("field namespace of message shard.v1.SubmitShardTxRequest is not mutable"))
 	case "shard.v1.SubmitShardTxRequest.epoch":
 		panic(fmt.Errorf("field epoch of message shard.v1.SubmitShardTxRequest is not mutable"))
+	case "shard.v1.SubmitShardTxRequest.timestamp":
+		panic(fmt.Errorf("field timestamp of message shard.v1.SubmitShardTxRequest is not mutable"))
+	case "shard.v1.SubmitShardTxRequest.prev_state_hash":
+		panic(fmt.Errorf("field prev_state_hash of message shard.v1.SubmitShardTxRequest is not mutable"))
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -333,6 +371,10 @@ func (x *fastReflection_SubmitShardTxRequest) NewField(fd protoreflect.FieldDesc
 	case "shard.v1.SubmitShardTxRequest.txs":
 		list := []*Transaction{}
 		return protoreflect.ValueOfList(&_SubmitShardTxRequest_4_list{list: &list})
+	case "shard.v1.SubmitShardTxRequest.timestamp":
+		return protoreflect.ValueOfUint64(uint64(0))
+	case "shard.v1.SubmitShardTxRequest.prev_state_hash":
+		return protoreflect.ValueOfBytes(nil)
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -419,6 +461,13 @@ func (x *fastReflection_SubmitShardTxRequest) ProtoMethods() *protoiface.Methods
 				n += 1 + l + runtime.Sov(uint64(l))
 			}
 		}
+		if x.Timestamp != 0 {
+			n += 1 + runtime.Sov(uint64(x.Timestamp))
+		}
+		l = len(x.PrevStateHash)
+		if l > 0 {
+			n += 1 + l + runtime.Sov(uint64(l))
+		}
 		if x.unknownFields != nil {
 			n += len(x.unknownFields)
 		}
@@ -448,6 +497,18 @@ func (x *fastReflection_SubmitShardTxRequest) ProtoMethods() *protoiface.Methods
 			i -= len(x.unknownFields)
 			copy(dAtA[i:], x.unknownFields)
 		}
+		if len(x.PrevStateHash) > 0 {
+			i -= len(x.PrevStateHash)
+			copy(dAtA[i:], x.PrevStateHash)
+			i = runtime.EncodeVarint(dAtA, i, uint64(len(x.PrevStateHash)))
+			i--
+			dAtA[i] = 0x32
+		}
+		if x.Timestamp != 0 {
+			i = runtime.EncodeVarint(dAtA, i, uint64(x.Timestamp))
+			i--
+			dAtA[i] = 0x28
+		}
 		if len(x.Txs) > 0 {
 			for iNdEx := len(x.Txs) - 1; iNdEx >= 0; iNdEx-- {
 				encoded, err := options.Marshal(x.Txs[iNdEx])
@@ -649,6 +710,59 @@ func (x *fastReflection_SubmitShardTxRequest) ProtoMethods() *protoiface.Methods
 					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, err
 				}
 				iNdEx = postIndex
+			case 5:
+				if wireType != 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+				}
+				x.Timestamp = 0
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					x.Timestamp |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+			case 6:
+				if wireType != 2 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field PrevStateHash", wireType)
+				}
+				var byteLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					byteLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if byteLen < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				postIndex := iNdEx + byteLen
+				if postIndex < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if postIndex > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				x.PrevStateHash = append(x.PrevStateHash[:0], dAtA[iNdEx:postIndex]...)
+				if x.PrevStateHash == nil {
+					x.PrevStateHash = []byte{}
+				}
+				iNdEx = postIndex
 			default:
 				iNdEx = preIndex
 				skippy, err := runtime.Skip(dAtA[iNdEx:])
@@ -1068,6 +1182,11 @@ type SubmitShardTxRequest struct {
 	Epoch uint64 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	// txs are the transactions that occurred in this tick.
 	Txs []*Transaction `protobuf:"bytes,4,rep,name=txs,proto3" json:"txs,omitempty"`
+	// timestamp is the unix time, in milliseconds, at which the epoch was started.
+	Timestamp uint64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// prev_state_hash is the state hash of the game shard at the end of the previous epoch, i.e. the state that this
+	// epoch's transactions were applied to. It is empty if the game shard does not compute state hashes.
+	PrevStateHash []byte `protobuf:"bytes,6,opt,name=prev_state_hash,json=prevStateHash,proto3" json:"prev_state_hash,omitempty"`
 }
 
 func (x *SubmitShardTxRequest) Reset() {
@@ -1118,6 +1237,20 @@ func (x *SubmitShardTxRequest) GetTxs() []*Transaction {
 	return nil
 }
 
+func (x *SubmitShardTxRequest) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SubmitShardTxRequest) GetPrevStateHash() []byte {
+	if x != nil {
+		return x.PrevStateHash
+	}
+	return nil
+}
+
 type SubmitShardTxResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1153,7 +1286,7 @@ var file_shard_v1_tx_proto_rawDesc = []byte{
 	0x6f, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73,
 	0x2f, 0x6d, 0x73, 0x67, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x73, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x1a, 0x14, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65,
-	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb2, 0x01, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x6d,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf8, 0x01, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x6d,
 	0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x12, 0x30, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x42, 0x18, 0xd2, 0xb4, 0x2d, 0x14, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x41, 0x64, 0x64,
@@ -1163,24 +1296,28 @@ var file_shard_v1_tx_proto_rawDesc = []byte{
 	0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
 	0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x27, 0x0a, 0x03, 0x74, 0x78, 0x73, 0x18, 0x04, 0x20,
 	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x74, 0x78, 0x73, 0x3a,
-	0x0b, 0x82, 0xe7, 0xb0, 0x2a, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x22, 0x17, 0x0a, 0x15,
-	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x5e, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x12, 0x50, 0x0a, 0x0d,
-	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x12, 0x1e, 0x2e,
-	0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53,
-	0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
-	0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53,
-	0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x05,
-	0x80, 0xe7, 0xb0, 0x2a, 0x01, 0x42, 0x7b, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x73, 0x68, 0x61,
-	0x72, 0x64, 0x2e, 0x76, 0x31, 0x42, 0x07, 0x54, 0x78, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01,
-	0x5a, 0x21, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73, 0x64, 0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61,
-	0x70, 0x69, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f, 0x76, 0x31, 0x3b, 0x73, 0x68, 0x61, 0x72,
-	0x64, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x58, 0x58, 0xaa, 0x02, 0x08, 0x53, 0x68, 0x61, 0x72,
-	0x64, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56, 0x31, 0xe2,
-	0x02, 0x14, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x53, 0x68, 0x61, 0x72, 0x64, 0x3a, 0x3a,
-	0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x74, 0x78, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x26, 0x0a,
+	0x0f, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x76, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x48, 0x61, 0x73, 0x68, 0x3a, 0x0b, 0x82, 0xe7, 0xb0, 0x2a, 0x06, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x22, 0x17, 0x0a, 0x15, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72,
+	0x64, 0x54, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x5e, 0x0a, 0x03, 0x4d,
+	0x73, 0x67, 0x12, 0x50, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72,
+	0x64, 0x54, 0x78, 0x12, 0x1e, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x05, 0x80, 0xe7, 0xb0, 0x2a, 0x01, 0x42, 0x7b, 0x0a, 0x0c, 0x63,
+	0x6f, 0x6d, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x42, 0x07, 0x54, 0x78, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73, 0x64,
+	0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f, 0x76,
+	0x31, 0x3b, 0x73, 0x68, 0x61, 0x72, 0x64, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x58, 0x58, 0xaa,
+	0x02, 0x08, 0x53, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x53, 0x68, 0x61,
+	0x72, 0x64, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56, 0x31,
+	0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x53,
+	0x68, 0x61, 0x72, 0x64, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -534,9 +534,11 @@ func (x *_Epoch_2_list) IsValid() bool {
 }
 
 var (
-	md_Epoch       protoreflect.MessageDescriptor
-	fd_Epoch_epoch protoreflect.FieldDescriptor
-	fd_Epoch_txs   protoreflect.FieldDescriptor
+	md_Epoch                 protoreflect.MessageDescriptor
+	fd_Epoch_epoch           protoreflect.FieldDescriptor
+	fd_Epoch_txs             protoreflect.FieldDescriptor
+	fd_Epoch_timestamp       protoreflect.FieldDescriptor
+	fd_Epoch_prev_state_hash protoreflect.FieldDescriptor
 )
 
 func init() {
@@ -544,6 +546,8 @@ func init() {
 	md_Epoch = File_shard_v1_types_proto.Messages().ByName("Epoch")
 	fd_Epoch_epoch = md_Epoch.Fields().ByName("epoch")
 	fd_Epoch_txs = md_Epoch.Fields().ByName("txs")
+	fd_Epoch_timestamp = md_Epoch.Fields().ByName("timestamp")
+	fd_Epoch_prev_state_hash = md_Epoch.Fields().ByName("prev_state_hash")
 }
 
 var _ protoreflect.Message = (*fastReflection_Epoch)(nil)
@@ -623,6 +627,18 @@ func (x *fastReflection_Epoch) Range(f func(protoreflect.FieldDescriptor, protor
 			return
 		}
 	}
+	if x.Timestamp != uint64(0) {
+		value := protoreflect.ValueOfUint64(x.Timestamp)
+		if !f(fd_Epoch_timestamp, value) {
+			return
+		}
+	}
+	if len(x.PrevStateHash) != 0 {
+		value := protoreflect.ValueOfBytes(x.PrevStateHash)
+		if !f(fd_Epoch_prev_state_hash, value) {
+			return
+		}
+	}
 }
 
 // Has reports whether a field is populated.
@@ -642,6 +658,10 @@ func (x *fastReflection_Epoch) Has(fd protoreflect.FieldDescriptor) bool {
 		return x.Epoch != uint64(0)
 	case "shard.v1.Epoch.txs":
 		return len(x.Txs) != 0
+	case "shard.v1.Epoch.timestamp":
+		return x.Timestamp != uint64(0)
+	case "shard.v1.Epoch.prev_state_hash":
+		return len(x.PrevStateHash) != 0
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -662,6 +682,10 @@ func (x *fastReflection_Epoch) Clear(fd protoreflect.FieldDescriptor) {
 		x.Epoch = uint64(0)
 	case "shard.v1.Epoch.txs":
 		x.Txs = nil
+	case "shard.v1.Epoch.timestamp":
+		x.Timestamp = uint64(0)
+	case "shard.v1.Epoch.prev_state_hash":
+		x.PrevStateHash = nil
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -687,6 +711,12 @@ func (x *fastReflection_Epoch) Get(descriptor protoreflect.FieldDescriptor) prot
 		}
 		listValue := &_Epoch_2_list{list: &x.Txs}
 		return protoreflect.ValueOfList(listValue)
+	case "shard.v1.Epoch.timestamp":
+		value := x.Timestamp
+		return protoreflect.ValueOfUint64(value)
+	case "shard.v1.Epoch.prev_state_hash":
+		value := x.PrevStateHash
+		return protoreflect.ValueOfBytes(value)
 	default:
 		if descriptor.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -713,6 +743,10 @@ func (x *fastReflection_Epoch) Set(fd protoreflect.FieldDescriptor, value protor
 		lv := value.List()
 		clv := lv.(*_Epoch_2_list)
 		x.Txs = *clv.list
+	case "shard.v1.Epoch.timestamp":
+		x.Timestamp = value.Uint()
+	case "shard.v1.Epoch.prev_state_hash":
+		x.PrevStateHash = value.Bytes()
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -741,6 +775,10 @@ func (x *fastReflection_Epoch) Mutable(fd protoreflect.FieldDescriptor) protoref
 		return protoreflect.ValueOfList(value)
 	case "shard.v1.Epoch.epoch":
 		panic(fmt.Errorf("field epoch of message shard.v1.Epoch is not mutable"))
+	case "shard.v1.Epoch.timestamp":
+		panic(fmt.Errorf("field timestamp of message shard.v1.Epoch is not mutable"))
+	case "shard.v1.Epoch.prev_state_hash":
+		panic(fmt.Errorf("field prev_state_hash of message shard.v1.Epoch is not mutable"))
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -759,6 +797,10 @@ func (x *fastReflection_Epoch) NewField(fd protoreflect.FieldDescriptor) protore
 	case "shard.v1.Epoch.txs":
 		list := []*Transaction{}
 		return protoreflect.ValueOfList(&_Epoch_2_list{list: &list})
+	case "shard.v1.Epoch.timestamp":
+		return protoreflect.ValueOfUint64(uint64(0))
+	case "shard.v1.Epoch.prev_state_hash":
+		return protoreflect.ValueOfBytes(nil)
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -837,6 +879,13 @@ func (x *fastReflection_Epoch) ProtoMethods() *protoiface.Methods {
 				n += 1 + l + runtime.Sov(uint64(l))
 			}
 		}
+		if x.Timestamp != 0 {
+			n += 1 + runtime.Sov(uint64(x.Timestamp))
+		}
+		l = len(x.PrevStateHash)
+		if l > 0 {
+			n += 1 + l + runtime.Sov(uint64(l))
+		}
 		if x.unknownFields != nil {
 			n += len(x.unknownFields)
 		}
@@ -866,6 +915,18 @@ func (x *fastReflection_Epoch) ProtoMethods() *protoiface.Methods {
 			i -= len(x.unknownFields)
 			copy(dAtA[i:], x.unknownFields)
 		}
+		if len(x.PrevStateHash) > 0 {
+			i -= len(x.PrevStateHash)
+			copy(dAtA[i:], x.PrevStateHash)
+			i = runtime.EncodeVarint(dAtA, i, uint64(len(x.PrevStateHash)))
+			i--
+			dAtA[i] = 0x22
+		}
+		if x.Timestamp != 0 {
+			i = runtime.EncodeVarint(dAtA, i, uint64(x.Timestamp))
+			i--
+			dAtA[i] = 0x18
+		}
 		if len(x.Txs) > 0 {
 			for iNdEx := len(x.Txs) - 1; iNdEx >= 0; iNdEx-- {
 				encoded, err := options.Marshal(x.Txs[iNdEx])
@@ -989,6 +1050,59 @@ func (x *fastReflection_Epoch) ProtoMethods() *protoiface.Methods {
 					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, err
 				}
 				iNdEx = postIndex
+			case 3:
+				if wireType != 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+				}
+				x.Timestamp = 0
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					x.Timestamp |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+			case 4:
+				if wireType != 2 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field PrevStateHash", wireType)
+				}
+				var byteLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					byteLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if byteLen < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				postIndex := iNdEx + byteLen
+				if postIndex < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if postIndex > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				x.PrevStateHash = append(x.PrevStateHash[:0], dAtA[iNdEx:postIndex]...)
+				if x.PrevStateHash == nil {
+					x.PrevStateHash = []byte{}
+				}
+				iNdEx = postIndex
 			default:
 				iNdEx = preIndex
 				skippy, err := runtime.Skip(dAtA[iNdEx:])
@@ -1091,6 +1205,11 @@ type Epoch struct {
 
 	Epoch uint64         `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	Txs   []*Transaction `protobuf:"bytes,2,rep,name=txs,proto3" json:"txs,omitempty"`
+	// timestamp is the unix time, in milliseconds, at which the epoch was started.
+	Timestamp uint64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// prev_state_hash is the state hash of the game shard at the end of the previous epoch. It is empty if the game
+	// shard did not submit one.
+	PrevStateHash []byte `protobuf:"bytes,4,opt,name=prev_state_hash,json=prevStateHash,proto3" json:"prev_state_hash,omitempty"`
 }
 
 func (x *Epoch) Reset() {
@@ -1127,6 +1246,20 @@ func (x *Epoch) GetTxs() []*Transaction {
 	return nil
 }
 
+func (x *Epoch) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Epoch) GetPrevStateHash() []byte {
+	if x != nil {
+		return x.PrevStateHash
+	}
+	return nil
+}
+
 var File_shard_v1_types_proto protoreflect.FileDescriptor
 
 var file_shard_v1_types_proto_rawDesc = []byte{
@@ -1137,20 +1270,25 @@ var file_shard_v1_types_proto_rawDesc = []byte{
 	0x74, 0x78, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x68, 0x61,
 	0x72, 0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
 	0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x46, 0x0a, 0x05, 0x45, 0x70,
-	0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x27, 0x0a, 0x03, 0x74, 0x78, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76,
-	0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x74,
-	0x78, 0x73, 0x42, 0x7e, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e,
-	0x76, 0x31, 0x42, 0x0a, 0x54, 0x79, 0x70, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01,
-	0x5a, 0x21, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73, 0x64, 0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61,
-	0x70, 0x69, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f, 0x76, 0x31, 0x3b, 0x73, 0x68, 0x61, 0x72,
-	0x64, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x58, 0x58, 0xaa, 0x02, 0x08, 0x53, 0x68, 0x61, 0x72,
-	0x64, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56, 0x31, 0xe2,
-	0x02, 0x14, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x53, 0x68, 0x61, 0x72, 0x64, 0x3a, 0x3a,
-	0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x8c, 0x01, 0x0a, 0x05, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x27, 0x0a, 0x03, 0x74, 0x78,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03,
+	0x74, 0x78, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x76,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x61, 0x73, 0x68, 0x42, 0x7e, 0x0a, 0x0c, 0x63, 0x6f, 0x6d,
+	0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x42, 0x0a, 0x54, 0x79, 0x70, 0x65, 0x73,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73,
+	0x64, 0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f,
+	0x76, 0x31, 0x3b, 0x73, 0x68, 0x61, 0x72, 0x64, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x58, 0x58,
+	0xaa, 0x02, 0x08, 0x53, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x53, 0x68,
+	0x61, 0x72, 0x64, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56,
+	0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09,
+	0x53, 0x68, 0x61, 0x72, 0x64, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -40,9 +40,9 @@ type Sequencer struct {
 // NewShardSequencer returns a new game shardsequencer server. It runs on a default port of 9601,
 // unless the SHARD_SEQUENCER_PORT environment variable is set.
 //
-// The sequencer exposes a single gRPC endpoint, SubmitShardTx, which will take in transactions from game shards,
-// indexed by namespace. At every block, the sequencer tx queue is flushed, and processed in the storage shard storage
-// module, persisting the data to the blockchain.
+// The sequencer exposes two gRPC endpoints, SubmitShardTx and SubmitShardEpoch, which take in transactions from game
+// shards, indexed by namespace. At every block, the sequencer tx queue is flushed, and processed in the storage shard
+// storage module, persisting the data to the blockchain.
 func NewShardSequencer(opts ...Option) *Sequencer {
 	addr := authtypes.NewModuleAddress(Name)
 	s := &Sequencer{
@@ -121,7 +121,35 @@ func (s *Sequencer) SubmitShardTx(_ context.Context, req *shard.SubmitShardTxReq
 		return nil, err
 	}
 
-	s.tq.AddTx(req.Tx.Namespace, req.Epoch, req.TxId, bz)
+	s.tq.AddTx(req.Tx.Namespace, req.Epoch, req.Timestamp, req.TxId, req.PrevStateHash, bz)
 
 	return &shard.SubmitShardTxResponse{}, nil
 }
+
+// SubmitShardEpoch adds every transaction of the epoch to the tx queue, replacing any transactions that were queued for
+// the epoch before.
+func (s *Sequencer) SubmitShardEpoch(_ context.Context, req *shard.SubmitShardEpochRequest) (
+	*shard.SubmitShardEpochResponse, error) {
+	zerolog.Logger.Info().Msgf("got epoch %d from shard: %s", req.Epoch, req.Namespace)
+	txs := make([]*types.Transaction, 0, len(req.Txs))
+	for _, tx := range req.Txs {
+		bz, err := proto.Marshal(tx.Tx)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, &types.Transaction{
+			TxId:                 tx.TxId,
+			GameShardTransaction: bz,
+		})
+	}
+
+	s.tq.SetEpoch(&types.SubmitShardTxRequest{
+		Namespace:     req.Namespace,
+		Epoch:         req.Epoch,
+		Txs:           txs,
+		Timestamp:     req.Timestamp,
+		PrevStateHash: req.PrevStateHash,
+	})
+
+	return &shard.SubmitShardEpochResponse{}, nil
+}
@@ -1,6 +1,7 @@
 package shard
 
 import (
+	"bytes"
 	"github.com/zyedidia/generic/queue"
 	"pkg.world.dev/world-engine/chain/x/shard/types"
 	"sync"
@@ -29,9 +30,11 @@ func (tc *TxQueue) GetRequestForNamespaceEpoch(ns string, epoch uint64) *types.S
 }
 
 // AddTx first checks if there are already transactions stored for the epoch in the request.
-// If there are, we simply append this request to txs.
+// If there are, we simply append this request to txs, unless the same transaction was already added.
 // If there aren't yet, we append the epoch number to epochQueue, then append to the txs map.
-func (tc *TxQueue) AddTx(namespace string, epoch, txID uint64, payload []byte) {
+// The timestamp is the time the epoch was started, and prevStateHash is the state hash the game shard had at the end of
+// the previous epoch. Both are the same for every transaction in the epoch.
+func (tc *TxQueue) AddTx(namespace string, epoch, timestamp, txID uint64, prevStateHash, payload []byte) {
 	tc.lock.Lock()
 	defer tc.lock.Unlock()
 	// if we have a brand-new namespace submitting transactions, we setup a new queue for it.
@@ -59,10 +62,19 @@ func (tc *TxQueue) AddTx(namespace string, epoch, txID uint64, payload []byte) {
 	// if we don't have a request for this epoch yet, instantiate one.
 	if tc.ntx[namespace].txs[epoch] == nil {
 		tc.ntx[namespace].txs[epoch] = &types.SubmitShardTxRequest{
-			Sender:    tc.moduleAddr,
-			Namespace: namespace,
-			Epoch:     epoch,
-			Txs:       make([]*types.Transaction, 0),
+			Sender:        tc.moduleAddr,
+			Namespace:     namespace,
+			Epoch:         epoch,
+			Txs:           make([]*types.Transaction, 0),
+			Timestamp:     timestamp,
+			PrevStateHash: prevStateHash,
+		}
+	}
+
+	// a game shard may submit a transaction again if it did not get a response, so skip duplicates.
+	for _, tx := range tc.ntx[namespace].txs[epoch].Txs {
+		if tx.TxId == txID && bytes.Equal(tx.GameShardTransaction, payload) {
+			return
 		}
 	}
 
@@ -73,6 +85,29 @@ func (tc *TxQueue) AddTx(namespace string, epoch, txID uint64, payload []byte) {
 	})
 }
 
+// SetEpoch queues every transaction of an epoch at once. The request replaces any request for the same namespace and
+// epoch that is still queued, so an epoch that is submitted again is only stored once. Otherwise, the request is added
+// to the outbox right away, after the namespace's pending epoch from AddTx, if any.
+func (tc *TxQueue) SetEpoch(req *types.SubmitShardTxRequest) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	req.Sender = tc.moduleAddr
+	if q := tc.ntx[req.Namespace]; q != nil && !q.epochQueue.Empty() {
+		prev := q.epochQueue.Dequeue()
+		if prev != req.Epoch {
+			tc.outbox = append(tc.outbox, q.txs[prev])
+		}
+		delete(q.txs, prev)
+	}
+	for i, queued := range tc.outbox {
+		if queued.Namespace == req.Namespace && queued.Epoch == req.Epoch {
+			tc.outbox[i] = req
+			return
+		}
+	}
+	tc.outbox = append(tc.outbox, req)
+}
+
 // GetTxs simply copies the transactions in the outbox, clears the outbox, then returns the copy.
 func (tc *TxQueue) GetTxs() []*types.SubmitShardTxRequest {
 	tc.lock.Lock()
@@ -17,21 +17,63 @@ func TestAddTx(t *testing.T) {
 
 	namespace := "foobar"
 	epoch := uint64(3)
-	txq.AddTx(namespace, epoch, 2, []byte("hi"))
-	txq.AddTx(namespace, epoch, 2, []byte("hello"))
+	timestamp := uint64(1700000000000)
+	stateHash := []byte("state-hash")
+	txq.AddTx(namespace, epoch, timestamp, 2, stateHash, []byte("hi"))
+	txq.AddTx(namespace, epoch, timestamp, 2, stateHash, []byte("hello"))
 	// add a random bogus transaction for good measure
-	txq.AddTx("bogus", 40, 2, []byte("HI"))
+	txq.AddTx("bogus", 40, timestamp, 2, nil, []byte("HI"))
 	// at this point, outbox should be empty, and there should be 2 txs in the queue.
 	assert.Equal(t, len(txq.outbox), 0)
 	req := txq.GetRequestForNamespaceEpoch(namespace, epoch)
 	assert.Equal(t, len(req.Txs), 2)
+	assert.Equal(t, req.Timestamp, timestamp)
+	assert.DeepEqual(t, req.PrevStateHash, stateHash)
 
 	// now we add a tx from a different epoch
 	newEpoch := uint64(4)
-	txq.AddTx(namespace, newEpoch, 3, []byte("foo"))
+	txq.AddTx(namespace, newEpoch, timestamp+1000, 3, nil, []byte("foo"))
 	assert.Equal(t, len(txq.outbox), 1)
 	// txs in this namespace should only have one item
 	assert.Equal(t, len(txq.ntx[namespace].txs), 1)
 	// top of the queue should be new epoch
 	assert.Equal(t, txq.ntx[namespace].epochQueue.Dequeue(), newEpoch)
 }
+
+func TestSetEpochReplacesQueuedEpoch(t *testing.T) {
+	txq := TxQueue{
+		lock:       sync.Mutex{},
+		ntx:        make(NamespacedTxs, 0),
+		outbox:     make([]*types.SubmitShardTxRequest, 0),
+		moduleAddr: "foo",
+	}
+
+	namespace := "foobar"
+	txq.AddTx(namespace, 1, 1000, 2, nil, []byte("hi"))
+	txq.AddTx(namespace, 1, 1000, 2, nil, []byte("hi"))
+	assert.Equal(t, len(txq.GetRequestForNamespaceEpoch(namespace, 1).Txs), 1)
+
+	epoch := func(n uint64, payloads ...string) *types.SubmitShardTxRequest {
+		req := &types.SubmitShardTxRequest{Namespace: namespace, Epoch: n, Timestamp: 1000 * n}
+		for _, p := range payloads {
+			req.Txs = append(req.Txs, &types.Transaction{TxId: 2, GameShardTransaction: []byte(p)})
+		}
+		return req
+	}
+	// the pending epoch from AddTx is flushed first, so the epochs stay in order.
+	txq.SetEpoch(epoch(2, "hello"))
+	assert.Equal(t, len(txq.outbox), 2)
+	assert.Equal(t, txq.outbox[0].Epoch, uint64(1))
+	assert.Equal(t, txq.outbox[1].Epoch, uint64(2))
+	assert.Equal(t, txq.outbox[1].Sender, "foo")
+
+	// submitting an epoch again replaces it instead of adding its transactions twice.
+	txq.SetEpoch(epoch(2, "hello", "world"))
+	txs := txq.GetTxs()
+	assert.Equal(t, len(txs), 2)
+	assert.Equal(t, len(txs[1].Txs), 2)
+
+	// the epoch was already flushed, so it is queued again and replaces the stored epoch in the keeper.
+	txq.SetEpoch(epoch(2, "hello", "world"))
+	assert.Equal(t, len(txq.GetTxs()), 1)
+}
@@ -61,10 +61,12 @@ func (s *TestSuite) TestSubmitTransactions() {
 	_, err = s.keeper.SubmitShardTx(
 		s.ctx,
 		&types.SubmitShardTxRequest{
-			Sender:    s.auth,
-			Namespace: tx.Namespace,
-			Epoch:     epoch,
-			Txs:       txs,
+			Sender:        s.auth,
+			Namespace:     tx.Namespace,
+			Epoch:         epoch,
+			Txs:           txs,
+			Timestamp:     1700000000000,
+			PrevStateHash: []byte("state-hash"),
 		},
 	)
 	s.Require().NoError(err)
@@ -90,6 +92,41 @@ func (s *TestSuite) TestSubmitTransactions() {
 	s.Require().Len(res.Epochs, 1)
 	// should have equal amount of txs within the epoch.
 	s.Require().Len(res.Epochs[0].Txs, len(txs))
+	// the epoch's timestamp should be saved alongside its transactions.
+	s.Require().Equal(uint64(1700000000000), res.Epochs[0].Timestamp)
+	s.Require().Equal([]byte("state-hash"), res.Epochs[0].PrevStateHash)
+}
+
+func (s *TestSuite) TestQueryTransactionsFromStartEpoch() {
+	namespace := "foo"
+	for epoch := uint64(1); epoch <= 5; epoch++ {
+		_, err := s.keeper.SubmitShardTx(s.ctx, &types.SubmitShardTxRequest{
+			Sender:    s.auth,
+			Namespace: namespace,
+			Epoch:     epoch,
+			Txs:       []*types.Transaction{{1, []byte("foo")}},
+		})
+		s.Require().NoError(err)
+	}
+
+	res, err := s.keeper.Transactions(s.ctx, &types.QueryTransactionsRequest{
+		Namespace:  namespace,
+		StartEpoch: 3,
+	})
+	s.Require().NoError(err)
+	// only epochs 3, 4, and 5 should be returned.
+	s.Require().Len(res.Epochs, 3)
+	for i, e := range res.Epochs {
+		s.Require().Equal(uint64(3+i), e.Epoch)
+	}
+
+	// a start epoch past the last submitted epoch should return nothing.
+	res, err = s.keeper.Transactions(s.ctx, &types.QueryTransactionsRequest{
+		Namespace:  namespace,
+		StartEpoch: 6,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(res.Epochs, 0)
 }
 
 func (s *TestSuite) TestSubmitBatch_Unauthorized() {
@@ -19,8 +19,10 @@ func (k *Keeper) SubmitShardTx(ctx context.Context, msg *types.SubmitShardTxRequ
 	sdkCtx := sdk.UnwrapSDKContext(ctx)
 
 	err := k.saveTransactions(sdkCtx, msg.Namespace, &types.Epoch{
-		Epoch: msg.Epoch,
-		Txs:   msg.Txs,
+		Epoch:         msg.Epoch,
+		Txs:           msg.Txs,
+		Timestamp:     msg.Timestamp,
+		PrevStateHash: msg.PrevStateHash,
 	})
 	if err != nil {
 		return nil, err
@@ -1,7 +1,9 @@
 package keeper
 
 import (
+	"bytes"
 	"context"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -17,6 +19,11 @@ func (k *Keeper) Transactions(ctx context.Context, req *types.QueryTransactionsR
 		return nil, sdkerrors.ErrInvalidRequest.Wrap("namespace required but not supplied")
 	}
 	key, limit := types.ExtractPageRequest(req.Page)
+	// transactions are keyed by epoch, so skipping the epochs before StartEpoch only requires moving the start key.
+	// a page key that is already past StartEpoch is left alone so pagination continues to work.
+	if startKey := k.getTransactionKey(req.StartEpoch); bytes.Compare(key, startKey) < 0 {
+		key = startKey
+	}
 	res := types.QueryTransactionsResponse{
 		Epochs: make([]*types.Epoch, 0, limit),
 		Page:   &types.PageResponse{},
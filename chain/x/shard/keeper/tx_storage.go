@@ -48,6 +48,8 @@ func (k *Keeper) iterateTransactions(
 	}
 }
 
+// saveTransactions stores the epoch under the namespace. It replaces any epoch that was stored with the same number, so
+// a game shard that submits an epoch again does not duplicate its transactions.
 func (k *Keeper) saveTransactions(ctx sdk.Context, ns string, e *types.Epoch) error {
 	k.saveNamespace(ctx, ns)
 	store := k.transactionStore(ctx, ns)
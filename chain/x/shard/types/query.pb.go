@@ -34,6 +34,8 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type QueryTransactionsRequest struct {
 	Namespace string       `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	Page      *PageRequest `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	// start_epoch is the lowest epoch to return. epochs before start_epoch are skipped.
+	StartEpoch uint64 `protobuf:"varint,3,opt,name=start_epoch,json=startEpoch,proto3" json:"start_epoch,omitempty"`
 }
 
 func (m *QueryTransactionsRequest) Reset()         { *m = QueryTransactionsRequest{} }
@@ -83,6 +85,13 @@ func (m *QueryTransactionsRequest) GetPage() *PageRequest {
 	return nil
 }
 
+func (m *QueryTransactionsRequest) GetStartEpoch() uint64 {
+	if m != nil {
+		return m.StartEpoch
+	}
+	return 0
+}
+
 type QueryTransactionsResponse struct {
 	// epochs contains the transactions. Each entry contains an epoch, and a list of txs that occurred in that epoch.
 	Epochs []*Epoch `protobuf:"bytes,1,rep,name=epochs,proto3" json:"epochs,omitempty"`
@@ -252,31 +261,32 @@ func init() {
 func init() { proto.RegisterFile("shard/v1/query.proto", fileDescriptor_1088f6b90570984a) }
 
 var fileDescriptor_1088f6b90570984a = []byte{
-	// 373 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x51, 0xcd, 0x4e, 0xea, 0x40,
-	0x14, 0xa6, 0x97, 0x0b, 0xb9, 0x0c, 0xdc, 0x68, 0x26, 0xa8, 0x85, 0x90, 0xa6, 0xa9, 0x0b, 0xd1,
-	0xc4, 0x4e, 0xc0, 0xf8, 0x02, 0x26, 0x26, 0x2e, 0xb5, 0x31, 0x31, 0x71, 0xa3, 0x63, 0x39, 0x19,
-	0x1a, 0xe8, 0xcc, 0xd0, 0x29, 0x28, 0x6f, 0xe1, 0x63, 0xb9, 0x64, 0xe9, 0xd2, 0xc0, 0x8b, 0x98,
-	0xce, 0x94, 0x14, 0x7f, 0x77, 0xe7, 0x7c, 0x73, 0xce, 0xf7, 0x33, 0x07, 0x35, 0xd5, 0x90, 0x26,
-	0x03, 0x32, 0xeb, 0x91, 0xc9, 0x14, 0x92, 0xb9, 0x2f, 0x13, 0x91, 0x0a, 0xfc, 0x4f, 0xa3, 0xfe,
-	0xac, 0xd7, 0x6e, 0x85, 0x42, 0xc5, 0x42, 0xdd, 0x69, 0x9c, 0x98, 0xc6, 0x0c, 0xb5, 0xf7, 0x4c,
-	0x47, 0x62, 0xc5, 0xb2, 0xfd, 0x58, 0xb1, 0xfc, 0xa1, 0xc9, 0x04, 0x13, 0x66, 0x21, 0xab, 0x72,
-	0xb4, 0xc3, 0x84, 0x60, 0x63, 0x20, 0x54, 0x46, 0x84, 0x72, 0x2e, 0x52, 0x9a, 0x46, 0x82, 0xaf,
-	0xc9, 0x0a, 0x1f, 0xe9, 0x5c, 0x42, 0x8e, 0x7a, 0x21, 0xb2, 0xaf, 0x32, 0x5b, 0xd7, 0x09, 0xe5,
-	0x8a, 0x86, 0x7a, 0x21, 0x80, 0xc9, 0x14, 0x54, 0x8a, 0x3b, 0xa8, 0xc6, 0x69, 0x0c, 0x4a, 0xd2,
-	0x10, 0x6c, 0xcb, 0xb5, 0xba, 0xb5, 0xa0, 0x00, 0xf0, 0x21, 0xfa, 0x2b, 0x29, 0x03, 0xfb, 0x8f,
-	0x6b, 0x75, 0xeb, 0xfd, 0x1d, 0x7f, 0x1d, 0xc8, 0xbf, 0xa4, 0x0c, 0x72, 0x8a, 0x40, 0x8f, 0x78,
-	0x12, 0xb5, 0xbe, 0x11, 0x51, 0x52, 0x70, 0x05, 0xf8, 0x00, 0x55, 0x41, 0x8a, 0x70, 0xa8, 0x6c,
-	0xcb, 0x2d, 0x77, 0xeb, 0xfd, 0xad, 0x82, 0xe9, 0x3c, 0xc3, 0x83, 0xfc, 0x19, 0x1f, 0x7d, 0x10,
-	0xdc, 0xfd, 0x2c, 0x68, 0xe8, 0x72, 0xc5, 0x53, 0x54, 0xdf, 0xb0, 0x81, 0xb7, 0x51, 0x79, 0x04,
-	0x73, 0x9d, 0xa1, 0x11, 0x64, 0x25, 0x6e, 0xa2, 0xca, 0x38, 0x8a, 0xa3, 0x54, 0xb3, 0xfd, 0x0f,
-	0x4c, 0xe3, 0xb9, 0xa8, 0xb1, 0x49, 0xf6, 0x75, 0xaf, 0x7f, 0x8f, 0x2a, 0x3a, 0x0a, 0xbe, 0x41,
-	0x8d, 0xcd, 0x38, 0xd8, 0x2b, 0xfc, 0xfc, 0xf4, 0xa1, 0xed, 0xfd, 0x5f, 0x67, 0x8c, 0xe6, 0xd9,
-	0xc5, 0xcb, 0xd2, 0xb1, 0x16, 0x4b, 0xc7, 0x7a, 0x5b, 0x3a, 0xd6, 0xf3, 0xca, 0x29, 0x2d, 0x56,
-	0x4e, 0xe9, 0x75, 0xe5, 0x94, 0x6e, 0x7d, 0x39, 0x62, 0xfe, 0xa3, 0x48, 0xc6, 0x03, 0x7f, 0x00,
-	0x33, 0xa2, 0xab, 0x63, 0xe0, 0x2c, 0xe2, 0x40, 0xc2, 0x21, 0x8d, 0x38, 0x79, 0x22, 0xe6, 0xc8,
-	0xfa, 0xc2, 0x0f, 0x55, 0x7d, 0xe2, 0x93, 0xf7, 0x00, 0x00, 0x00, 0xff, 0xff, 0x94, 0x55, 0x85,
-	0x19, 0x82, 0x02, 0x00, 0x00,
+	// 395 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0x5d, 0x6b, 0xdb, 0x30,
+	0x14, 0x8d, 0x96, 0x0f, 0x16, 0x39, 0x63, 0x43, 0x64, 0x9b, 0x13, 0x82, 0x67, 0xbc, 0x87, 0x79,
+	0x83, 0x59, 0x24, 0x63, 0x7f, 0x60, 0x30, 0xd8, 0xe3, 0x6a, 0x0a, 0x85, 0xbe, 0xa4, 0xaa, 0x23,
+	0x14, 0x93, 0x58, 0x52, 0x2c, 0x25, 0x6d, 0xde, 0xfb, 0x03, 0xfa, 0xb3, 0xfa, 0x98, 0xc7, 0x3e,
+	0x96, 0xe4, 0x8f, 0x14, 0x4b, 0x0e, 0x4e, 0x3f, 0xdf, 0xee, 0x3d, 0xba, 0xe7, 0xdc, 0x73, 0xc4,
+	0x85, 0x5d, 0x35, 0x25, 0xf9, 0x04, 0xaf, 0x86, 0x78, 0xb1, 0xa4, 0xf9, 0x3a, 0x92, 0xb9, 0xd0,
+	0x02, 0xbd, 0x35, 0x68, 0xb4, 0x1a, 0xf6, 0x7b, 0x89, 0x50, 0x99, 0x50, 0x63, 0x83, 0x63, 0xdb,
+	0xd8, 0xa1, 0xfe, 0x67, 0xdb, 0xe1, 0x4c, 0xb1, 0x82, 0x9f, 0x29, 0x56, 0x3e, 0x74, 0x99, 0x60,
+	0xc2, 0x12, 0x8a, 0xaa, 0x44, 0x07, 0x4c, 0x08, 0x36, 0xa7, 0x98, 0xc8, 0x14, 0x13, 0xce, 0x85,
+	0x26, 0x3a, 0x15, 0x7c, 0x2f, 0x56, 0xf9, 0xd0, 0x6b, 0x49, 0x4b, 0x34, 0xb8, 0x02, 0xd0, 0x3d,
+	0x2a, 0x7c, 0x1d, 0xe7, 0x84, 0x2b, 0x92, 0x18, 0x46, 0x4c, 0x17, 0x4b, 0xaa, 0x34, 0x1a, 0xc0,
+	0x36, 0x27, 0x19, 0x55, 0x92, 0x24, 0xd4, 0x05, 0x3e, 0x08, 0xdb, 0x71, 0x05, 0xa0, 0xef, 0xb0,
+	0x21, 0x09, 0xa3, 0xee, 0x1b, 0x1f, 0x84, 0xce, 0xe8, 0x63, 0xb4, 0x4f, 0x14, 0xfd, 0x27, 0x8c,
+	0x96, 0x12, 0xb1, 0x19, 0x41, 0x5f, 0xa0, 0xa3, 0x34, 0xc9, 0xf5, 0x98, 0x4a, 0x91, 0x4c, 0xdd,
+	0xba, 0x0f, 0xc2, 0x46, 0x0c, 0x0d, 0xf4, 0xb7, 0x40, 0x02, 0x09, 0x7b, 0xcf, 0xb8, 0x50, 0x52,
+	0x70, 0x45, 0xd1, 0x37, 0xd8, 0x32, 0x3c, 0xe5, 0x02, 0xbf, 0x1e, 0x3a, 0xa3, 0xf7, 0xd5, 0x2a,
+	0xc3, 0x8e, 0xcb, 0x67, 0xf4, 0xe3, 0x81, 0xa3, 0x4f, 0x8f, 0x1d, 0x59, 0x39, 0x6b, 0x29, 0xf8,
+	0x0d, 0x9d, 0x03, 0x9f, 0xe8, 0x03, 0xac, 0xcf, 0xe8, 0xda, 0x84, 0xec, 0xc4, 0x45, 0x89, 0xba,
+	0xb0, 0x39, 0x4f, 0xb3, 0x54, 0x1b, 0xb5, 0x77, 0xb1, 0x6d, 0x02, 0x1f, 0x76, 0x0e, 0xc5, 0x9e,
+	0xf2, 0x46, 0x67, 0xb0, 0x69, 0xa2, 0xa0, 0x13, 0xd8, 0x39, 0x8c, 0x83, 0x82, 0xca, 0xcf, 0x4b,
+	0x3f, 0xde, 0xff, 0xfa, 0xea, 0x8c, 0xdd, 0xf9, 0xe7, 0xdf, 0xcd, 0xd6, 0x03, 0x9b, 0xad, 0x07,
+	0xee, 0xb6, 0x1e, 0xb8, 0xde, 0x79, 0xb5, 0xcd, 0xce, 0xab, 0xdd, 0xee, 0xbc, 0xda, 0x69, 0x24,
+	0x67, 0x2c, 0xba, 0x10, 0xf9, 0x7c, 0x12, 0x4d, 0xe8, 0x0a, 0x9b, 0xea, 0x27, 0xe5, 0x2c, 0xe5,
+	0x14, 0x27, 0x53, 0x92, 0x72, 0x7c, 0x89, 0xed, 0x19, 0x98, 0x1b, 0x38, 0x6f, 0x99, 0x23, 0xf8,
+	0x75, 0x3f, 0x00, 0xe7, 0x08, 0xbd, 0x02, 0xa4, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -379,6 +389,11 @@ func (m *QueryTransactionsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error
 	_ = i
 	var l int
 	_ = l
+	if m.StartEpoch != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.StartEpoch))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.Page != nil {
 		{
 			size, err := m.Page.MarshalToSizedBuffer(dAtA[:i])
@@ -540,6 +555,9 @@ func (m *QueryTransactionsRequest) Size() (n int) {
 		l = m.Page.Size()
 		n += 1 + l + sovQuery(uint64(l))
 	}
+	if m.StartEpoch != 0 {
+		n += 1 + sovQuery(uint64(m.StartEpoch))
+	}
 	return n
 }
 
@@ -694,6 +712,25 @@ func (m *QueryTransactionsRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartEpoch", wireType)
+			}
+			m.StartEpoch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartEpoch |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
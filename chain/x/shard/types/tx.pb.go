@@ -40,6 +40,11 @@ type SubmitShardTxRequest struct {
 	Epoch uint64 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	// txs are the transactions that occurred in this tick.
 	Txs []*Transaction `protobuf:"bytes,4,rep,name=txs,proto3" json:"txs,omitempty"`
+	// timestamp is the unix time, in milliseconds, at which the epoch was started.
+	Timestamp uint64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// prev_state_hash is the state hash of the game shard at the end of the previous epoch, i.e. the state that this
+	// epoch's transactions were applied to. It is empty if the game shard does not compute state hashes.
+	PrevStateHash []byte `protobuf:"bytes,6,opt,name=prev_state_hash,json=prevStateHash,proto3" json:"prev_state_hash,omitempty"`
 }
 
 func (m *SubmitShardTxRequest) Reset()         { *m = SubmitShardTxRequest{} }
@@ -103,6 +108,20 @@ func (m *SubmitShardTxRequest) GetTxs() []*Transaction {
 	return nil
 }
 
+func (m *SubmitShardTxRequest) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *SubmitShardTxRequest) GetPrevStateHash() []byte {
+	if m != nil {
+		return m.PrevStateHash
+	}
+	return nil
+}
+
 type SubmitShardTxResponse struct {
 }
 
@@ -147,29 +166,32 @@ func init() {
 func init() { proto.RegisterFile("shard/v1/tx.proto", fileDescriptor_2ea9067d7c94eab8) }
 
 var fileDescriptor_2ea9067d7c94eab8 = []byte{
-	// 347 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0xcd, 0x4e, 0xea, 0x40,
-	0x14, 0x66, 0x6e, 0x81, 0x5c, 0x86, 0xdc, 0xc5, 0x6d, 0x4a, 0xa8, 0x8d, 0xa9, 0x84, 0x8d, 0x84,
-	0x84, 0x19, 0xc1, 0x9d, 0x3b, 0x59, 0xb9, 0x31, 0x31, 0x85, 0x95, 0x0b, 0x4d, 0x69, 0x27, 0xa5,
-	0xd1, 0xce, 0xd4, 0x39, 0x03, 0xd6, 0x9d, 0xf1, 0x09, 0x7c, 0x14, 0x62, 0x7c, 0x08, 0x97, 0xc4,
-	0x95, 0x4b, 0x03, 0x0b, 0x5e, 0xc3, 0xf4, 0xc7, 0x10, 0x8d, 0xee, 0xe6, 0xfb, 0x39, 0xdf, 0x99,
-	0x73, 0x0e, 0xfe, 0x0f, 0x53, 0x57, 0xfa, 0x74, 0xde, 0xa7, 0x2a, 0x21, 0xb1, 0x14, 0x4a, 0xe8,
-	0x7f, 0x33, 0x8a, 0xcc, 0xfb, 0xd6, 0x8e, 0x27, 0x20, 0x12, 0x70, 0x99, 0xf1, 0x34, 0x07, 0xb9,
-	0xc9, 0x6a, 0xe6, 0x88, 0x46, 0x10, 0xa4, 0xc5, 0x11, 0x04, 0x85, 0x60, 0x6c, 0x03, 0xef, 0x62,
-	0x56, 0xd8, 0xdb, 0x4f, 0x08, 0x1b, 0xa3, 0xd9, 0x24, 0x0a, 0xd5, 0x28, 0x95, 0xc7, 0x89, 0xc3,
-	0x6e, 0x66, 0x0c, 0x94, 0x7e, 0x80, 0xab, 0xc0, 0xb8, 0xcf, 0xa4, 0x89, 0x5a, 0xa8, 0x53, 0x1b,
-	0x9a, 0xaf, 0xcf, 0x3d, 0xa3, 0xe8, 0x74, 0xec, 0xfb, 0x92, 0x01, 0x8c, 0x94, 0x0c, 0x79, 0xe0,
-	0x14, 0x3e, 0x7d, 0x17, 0xd7, 0xb8, 0x1b, 0x31, 0x88, 0x5d, 0x8f, 0x99, 0x7f, 0xd2, 0x22, 0x67,
-	0x4b, 0xe8, 0x06, 0xae, 0xb0, 0x58, 0x78, 0x53, 0x53, 0x6b, 0xa1, 0x4e, 0xd9, 0xc9, 0x81, 0xbe,
-	0x8f, 0x35, 0x95, 0x80, 0x59, 0x6e, 0x69, 0x9d, 0xfa, 0xa0, 0x41, 0x3e, 0x07, 0x24, 0x63, 0xe9,
-	0x72, 0x70, 0x3d, 0x15, 0x0a, 0xee, 0xa4, 0x8e, 0xa3, 0xfa, 0xc3, 0x66, 0xd1, 0x2d, 0x3a, 0xb5,
-	0x9b, 0xb8, 0xf1, 0xed, 0xcf, 0x10, 0x0b, 0x0e, 0x6c, 0x70, 0x81, 0xb5, 0x53, 0x08, 0xf4, 0x33,
-	0xfc, 0xef, 0x8b, 0xae, 0xdb, 0xdb, 0xe4, 0x9f, 0x86, 0xb5, 0xf6, 0x7e, 0xd5, 0xf3, 0x60, 0xab,
-	0x72, 0xbf, 0x59, 0x74, 0xd1, 0xf0, 0xe4, 0x65, 0x65, 0xa3, 0xe5, 0xca, 0x46, 0xef, 0x2b, 0x1b,
-	0x3d, 0xae, 0xed, 0xd2, 0x72, 0x6d, 0x97, 0xde, 0xd6, 0x76, 0xe9, 0x9c, 0xc4, 0x57, 0x01, 0xb9,
-	0x15, 0xf2, 0xda, 0x27, 0x3e, 0x9b, 0xd3, 0xec, 0xd5, 0x63, 0x3c, 0x08, 0x39, 0xa3, 0xde, 0xd4,
-	0x0d, 0x39, 0x4d, 0x68, 0x7e, 0x80, 0x6c, 0xfb, 0x93, 0x6a, 0xb6, 0xfe, 0xc3, 0x8f, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xd9, 0xdb, 0xfd, 0x11, 0xe7, 0x01, 0x00, 0x00,
+	// 389 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xbf, 0x6e, 0xd4, 0x40,
+	0x10, 0xc6, 0x6f, 0x71, 0xee, 0x44, 0x36, 0x44, 0x88, 0x95, 0xa3, 0x18, 0x0b, 0x19, 0x2b, 0x05,
+	0x58, 0x91, 0xe2, 0x25, 0xa1, 0xa3, 0x23, 0x55, 0x1a, 0x24, 0x64, 0xa7, 0xa2, 0xe0, 0xb4, 0xb1,
+	0x47, 0xb6, 0x05, 0xde, 0x5d, 0x76, 0x36, 0xc6, 0x74, 0x88, 0x27, 0xe0, 0x51, 0x52, 0xf0, 0x10,
+	0x94, 0x27, 0x2a, 0x4a, 0x74, 0x57, 0xdc, 0x2b, 0x50, 0x22, 0xff, 0x41, 0x16, 0x08, 0x3a, 0xcf,
+	0xef, 0xfb, 0xe6, 0xf3, 0xcc, 0x0e, 0xbd, 0x87, 0xa5, 0x30, 0x39, 0x6f, 0x4e, 0xb9, 0x6d, 0x63,
+	0x6d, 0x94, 0x55, 0xec, 0x76, 0x8f, 0xe2, 0xe6, 0xd4, 0xbf, 0x9f, 0x29, 0xac, 0x15, 0x2e, 0x7b,
+	0xce, 0x87, 0x62, 0x30, 0xf9, 0x87, 0x43, 0xc5, 0x6b, 0x2c, 0xba, 0xe6, 0x1a, 0x8b, 0x51, 0x70,
+	0xa7, 0xc0, 0x0f, 0x1a, 0x46, 0xfb, 0xd1, 0x4f, 0x42, 0xdd, 0xf4, 0xfa, 0xaa, 0xae, 0x6c, 0xda,
+	0xc9, 0x97, 0x6d, 0x02, 0xef, 0xae, 0x01, 0x2d, 0x7b, 0x42, 0x17, 0x08, 0x32, 0x07, 0xe3, 0x91,
+	0x90, 0x44, 0xbb, 0xe7, 0xde, 0xb7, 0x2f, 0x27, 0xee, 0xf8, 0xa7, 0xe7, 0x79, 0x6e, 0x00, 0x31,
+	0xb5, 0xa6, 0x92, 0x45, 0x32, 0xfa, 0xd8, 0x03, 0xba, 0x2b, 0x45, 0x0d, 0xa8, 0x45, 0x06, 0xde,
+	0xad, 0xae, 0x29, 0x99, 0x00, 0x73, 0xe9, 0x1c, 0xb4, 0xca, 0x4a, 0xcf, 0x09, 0x49, 0xb4, 0x93,
+	0x0c, 0x05, 0x7b, 0x4c, 0x1d, 0xdb, 0xa2, 0xb7, 0x13, 0x3a, 0xd1, 0xde, 0xd9, 0x41, 0xfc, 0x7b,
+	0xc1, 0xf8, 0xd2, 0x08, 0x89, 0x22, 0xb3, 0x95, 0x92, 0x49, 0xe7, 0xe8, 0xc2, 0x6d, 0x55, 0x03,
+	0x5a, 0x51, 0x6b, 0x6f, 0xde, 0x47, 0x4c, 0x80, 0x3d, 0xa2, 0x77, 0xb5, 0x81, 0x66, 0x89, 0x56,
+	0x58, 0x58, 0x96, 0x02, 0x4b, 0x6f, 0x11, 0x92, 0xe8, 0x4e, 0xb2, 0xdf, 0xe1, 0xb4, 0xa3, 0x17,
+	0x02, 0xcb, 0x67, 0x7b, 0x9f, 0xb6, 0x37, 0xc7, 0xe3, 0xbc, 0x47, 0x87, 0xf4, 0xe0, 0xaf, 0xcd,
+	0x51, 0x2b, 0x89, 0x70, 0xf6, 0x9a, 0x3a, 0x2f, 0xb0, 0x60, 0x2f, 0xe9, 0xfe, 0x1f, 0x3a, 0x0b,
+	0xa6, 0xf9, 0xfe, 0xf5, 0x64, 0xfe, 0xc3, 0xff, 0xea, 0x43, 0xb0, 0x3f, 0xff, 0xb8, 0xbd, 0x39,
+	0x26, 0xe7, 0x17, 0x5f, 0xd7, 0x01, 0x59, 0xad, 0x03, 0xf2, 0x63, 0x1d, 0x90, 0xcf, 0x9b, 0x60,
+	0xb6, 0xda, 0x04, 0xb3, 0xef, 0x9b, 0x60, 0xf6, 0x2a, 0xd6, 0x6f, 0x8a, 0xf8, 0xbd, 0x32, 0x6f,
+	0xf3, 0x38, 0x87, 0x86, 0xf7, 0x5f, 0x27, 0x20, 0x8b, 0x4a, 0x02, 0xcf, 0x4a, 0x51, 0x49, 0xde,
+	0xf2, 0xe1, 0x8c, 0xfd, 0x0d, 0xaf, 0x16, 0xfd, 0x11, 0x9f, 0xfe, 0x1a, 0x00, 0x10, 0x66, 0x59,
+	0x4e, 0x2d, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -272,6 +294,18 @@ func (m *SubmitShardTxRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.PrevStateHash) > 0 {
+		i -= len(m.PrevStateHash)
+		copy(dAtA[i:], m.PrevStateHash)
+		i = encodeVarintTx(dAtA, i, uint64(len(m.PrevStateHash)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintTx(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.Txs) > 0 {
 		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -365,6 +399,13 @@ func (m *SubmitShardTxRequest) Size() (n int) {
 			n += 1 + l + sovTx(uint64(l))
 		}
 	}
+	if m.Timestamp != 0 {
+		n += 1 + sovTx(uint64(m.Timestamp))
+	}
+	l = len(m.PrevStateHash)
+	if l > 0 {
+		n += 1 + l + sovTx(uint64(l))
+	}
 	return n
 }
 
@@ -529,6 +570,59 @@ func (m *SubmitShardTxRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrevStateHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PrevStateHash = append(m.PrevStateHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.PrevStateHash == nil {
+				m.PrevStateHash = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTx(dAtA[iNdEx:])
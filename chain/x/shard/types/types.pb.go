@@ -81,6 +81,11 @@ func (m *Transaction) GetGameShardTransaction() []byte {
 type Epoch struct {
 	Epoch uint64         `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	Txs   []*Transaction `protobuf:"bytes,2,rep,name=txs,proto3" json:"txs,omitempty"`
+	// timestamp is the unix time, in milliseconds, at which the epoch was started.
+	Timestamp uint64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// prev_state_hash is the state hash of the game shard at the end of the previous epoch. It is empty if the game
+	// shard did not submit one.
+	PrevStateHash []byte `protobuf:"bytes,4,opt,name=prev_state_hash,json=prevStateHash,proto3" json:"prev_state_hash,omitempty"`
 }
 
 func (m *Epoch) Reset()         { *m = Epoch{} }
@@ -130,6 +135,20 @@ func (m *Epoch) GetTxs() []*Transaction {
 	return nil
 }
 
+func (m *Epoch) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Epoch) GetPrevStateHash() []byte {
+	if m != nil {
+		return m.PrevStateHash
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Transaction)(nil), "shard.v1.Transaction")
 	proto.RegisterType((*Epoch)(nil), "shard.v1.Epoch")
@@ -138,22 +157,25 @@ func init() {
 func init() { proto.RegisterFile("shard/v1/types.proto", fileDescriptor_0a60f84bb846c47b) }
 
 var fileDescriptor_0a60f84bb846c47b = []byte{
-	// 234 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x29, 0xce, 0x48, 0x2c,
-	0x4a, 0xd1, 0x2f, 0x33, 0xd4, 0x2f, 0xa9, 0x2c, 0x48, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
-	0x17, 0xe2, 0x00, 0x8b, 0xea, 0x95, 0x19, 0x2a, 0x45, 0x70, 0x71, 0x87, 0x14, 0x25, 0xe6, 0x15,
-	0x27, 0x26, 0x97, 0x64, 0xe6, 0xe7, 0x09, 0x09, 0x73, 0xb1, 0x96, 0x54, 0xc4, 0x67, 0xa6, 0x48,
-	0x30, 0x2a, 0x30, 0x6a, 0xb0, 0x04, 0xb1, 0x94, 0x54, 0x78, 0xa6, 0x08, 0x99, 0x70, 0x89, 0xa5,
-	0x27, 0xe6, 0xa6, 0xc6, 0x83, 0x35, 0xc5, 0x97, 0x20, 0x94, 0x4b, 0x30, 0x29, 0x30, 0x6a, 0xf0,
-	0x04, 0x89, 0x80, 0x64, 0x83, 0x41, 0x92, 0x48, 0x46, 0x29, 0xb9, 0x71, 0xb1, 0xba, 0x16, 0xe4,
-	0x27, 0x67, 0x08, 0x89, 0x70, 0xb1, 0xa6, 0x82, 0x18, 0x50, 0x33, 0x21, 0x1c, 0x21, 0x75, 0x2e,
-	0xe6, 0x92, 0x8a, 0x62, 0x09, 0x26, 0x05, 0x66, 0x0d, 0x6e, 0x23, 0x51, 0x3d, 0x98, 0x83, 0xf4,
-	0x90, 0x8c, 0x08, 0x02, 0xa9, 0x70, 0xf2, 0x38, 0xf1, 0x48, 0x8e, 0xf1, 0xc2, 0x23, 0x39, 0xc6,
-	0x07, 0x8f, 0xe4, 0x18, 0x27, 0x3c, 0x96, 0x63, 0xb8, 0xf0, 0x58, 0x8e, 0xe1, 0xc6, 0x63, 0x39,
-	0x86, 0x28, 0xbd, 0x82, 0xec, 0x74, 0xbd, 0xf2, 0xfc, 0xa2, 0x9c, 0x14, 0xbd, 0x94, 0xd4, 0x32,
-	0x7d, 0x30, 0x4b, 0x37, 0x35, 0x2f, 0x3d, 0x33, 0x2f, 0x55, 0x3f, 0x39, 0x23, 0x31, 0x33, 0x4f,
-	0xbf, 0x42, 0x1f, 0xe2, 0x7d, 0xb0, 0xdf, 0x93, 0xd8, 0xc0, 0x9e, 0x37, 0x06, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x7a, 0xdd, 0x95, 0x57, 0x14, 0x01, 0x00, 0x00,
+	// 280 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xb1, 0x4a, 0xc4, 0x30,
+	0x18, 0xc7, 0x9b, 0x6b, 0x2b, 0x9a, 0x53, 0x84, 0x58, 0xa5, 0x83, 0x84, 0x72, 0x83, 0x76, 0x31,
+	0xe1, 0xd4, 0x27, 0x10, 0x84, 0x73, 0xed, 0x39, 0x88, 0x4b, 0x89, 0x6d, 0x68, 0x8a, 0xb6, 0x0d,
+	0x4d, 0xa8, 0xf5, 0x1d, 0x1c, 0x7c, 0x2c, 0xc7, 0x1b, 0x1d, 0xa5, 0x7d, 0x11, 0x49, 0xce, 0xe3,
+	0x6e, 0xfb, 0xf2, 0xfb, 0xbe, 0xfc, 0xf8, 0xf3, 0x87, 0x81, 0x12, 0xac, 0xcd, 0x69, 0x37, 0xa7,
+	0xfa, 0x43, 0x72, 0x45, 0x64, 0xdb, 0xe8, 0x06, 0xed, 0x5b, 0x4a, 0xba, 0xf9, 0xec, 0x09, 0x4e,
+	0x1f, 0x5b, 0x56, 0x2b, 0x96, 0xe9, 0xb2, 0xa9, 0xd1, 0x09, 0xf4, 0x75, 0x9f, 0x96, 0x79, 0x08,
+	0x22, 0x10, 0x7b, 0x89, 0xa7, 0xfb, 0x87, 0x1c, 0xdd, 0xc2, 0xb3, 0x82, 0x55, 0x3c, 0xb5, 0x9f,
+	0x52, 0xbd, 0x3d, 0x0f, 0x27, 0x11, 0x88, 0x0f, 0x93, 0xc0, 0x6c, 0x97, 0x66, 0xb9, 0xa3, 0x9a,
+	0x7d, 0x02, 0xe8, 0xdf, 0xcb, 0x26, 0x13, 0x28, 0x80, 0x3e, 0x37, 0xc3, 0xbf, 0x74, 0xfd, 0x40,
+	0x97, 0xd0, 0xd5, 0xbd, 0x0a, 0x27, 0x91, 0x1b, 0x4f, 0xaf, 0x4f, 0xc9, 0x26, 0x11, 0xd9, 0x71,
+	0x24, 0xe6, 0x02, 0x9d, 0xc3, 0x03, 0x5d, 0x56, 0x5c, 0x69, 0x56, 0xc9, 0xd0, 0xb5, 0x8a, 0x2d,
+	0x40, 0x17, 0xf0, 0x58, 0xb6, 0xbc, 0x4b, 0x95, 0x66, 0x9a, 0xa7, 0x82, 0x29, 0x11, 0x7a, 0x36,
+	0xd5, 0x91, 0xc1, 0x4b, 0x43, 0x17, 0x4c, 0x89, 0xbb, 0xc5, 0xf7, 0x80, 0xc1, 0x6a, 0xc0, 0xe0,
+	0x77, 0xc0, 0xe0, 0x6b, 0xc4, 0xce, 0x6a, 0xc4, 0xce, 0xcf, 0x88, 0x9d, 0x67, 0x22, 0x5f, 0x0b,
+	0xf2, 0xde, 0xb4, 0x6f, 0x39, 0xc9, 0x79, 0x47, 0xed, 0x74, 0xc5, 0xeb, 0xa2, 0xac, 0x39, 0xcd,
+	0x04, 0x2b, 0x6b, 0xda, 0xd3, 0x75, 0x8b, 0xb6, 0xc2, 0x97, 0x3d, 0xdb, 0xe1, 0xcd, 0xdf, 0x00,
+	0xfe, 0xba, 0xf7, 0x2f, 0x5b, 0x01, 0x00, 0x00,
 }
 
 func (m *Transaction) Marshal() (dAtA []byte, err error) {
@@ -211,6 +233,18 @@ func (m *Epoch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.PrevStateHash) > 0 {
+		i -= len(m.PrevStateHash)
+		copy(dAtA[i:], m.PrevStateHash)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.PrevStateHash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.Txs) > 0 {
 		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -275,6 +309,13 @@ func (m *Epoch) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
+	if m.Timestamp != 0 {
+		n += 1 + sovTypes(uint64(m.Timestamp))
+	}
+	l = len(m.PrevStateHash)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -469,6 +510,59 @@ func (m *Epoch) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrevStateHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PrevStateHash = append(m.PrevStateHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.PrevStateHash == nil {
+				m.PrevStateHash = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
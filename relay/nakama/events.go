@@ -1,6 +1,9 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -10,19 +13,100 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/heroiclabs/nakama-common/runtime"
+	"pkg.world.dev/world-engine/sign"
 )
 
+// Event is a single event received from cardinal. Plain string events only have a message. Typed events and events
+// that target a persona are JSON encoded, and also have the tick they were emitted in. Typed events also have a name
+// and a payload.
 type Event struct {
-	message string
+	Message    string          `json:"message"`
+	Name       string          `json:"name"`
+	Payload    json.RawMessage `json:"payload"`
+	Tick       uint64          `json:"tick"`
+	EntityID   *uint64         `json:"entityId,omitempty"`
+	PersonaTag string          `json:"personaTag,omitempty"`
+}
+
+// parseEvent decodes a message from cardinal's /events websocket. Messages that are not JSON objects are plain string
+// events. An error is returned for JSON objects that cannot be decoded as an event, since they may target a persona
+// that can then not be told apart.
+func parseEvent(message []byte) (*Event, error) {
+	trimmed := bytes.TrimSpace(message)
+	if len(trimmed) == 0 || trimmed[0] != '{' || !json.Valid(trimmed) {
+		return &Event{Message: string(message)}, nil
+	}
+	event := &Event{}
+	if err := json.Unmarshal(trimmed, event); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	if !event.IsTyped() && event.PersonaTag == "" {
+		// A plain string event that happens to be a JSON object.
+		return &Event{Message: string(message)}, nil
+	}
+	return event, nil
+}
+
+// authenticationMessage is cardinal's response to an event handshake.
+type authenticationMessage struct {
+	Authenticated *struct {
+		SignerAddress string `json:"signerAddress"`
+	} `json:"authenticated"`
+	Error string `json:"error"`
+}
+
+// parseAuthenticationMessage returns the decoded message, and true if the given message is a response to an event
+// handshake rather than an event.
+func parseAuthenticationMessage(message []byte) (*authenticationMessage, bool) {
+	msg := &authenticationMessage{}
+	if err := json.Unmarshal(message, msg); err != nil {
+		return nil, false
+	}
+	return msg, msg.Authenticated != nil || msg.Error != ""
+}
+
+// IsTyped returns true if the event was emitted from a registered cardinal event type.
+func (e *Event) IsTyped() bool {
+	return e.Name != ""
+}
+
+// notificationContent returns the content of the nakama notification for this event.
+func (e *Event) notificationContent() map[string]any {
+	if !e.IsTyped() && e.PersonaTag == "" {
+		return map[string]any{"message": e.Message}
+	}
+	content := map[string]any{"tick": e.Tick}
+	if e.IsTyped() {
+		content["name"] = e.Name
+		content["payload"] = e.Payload
+	} else {
+		content["message"] = e.Message
+	}
+	if e.EntityID != nil {
+		content["entityId"] = *e.EntityID
+	}
+	if e.PersonaTag != "" {
+		content["personaTag"] = e.PersonaTag
+	}
+	return content
 }
 
 type EventHub struct {
 	inputConnection *websocket.Conn
 	channels        *sync.Map // map[string]chan *Event
 	didShutdown     atomic.Bool
+	// authenticate sends the event handshake on the given connection. It is nil until Authenticate is called.
+	authenticate func(conn *websocket.Conn) error
 }
 
-func createEventHub(logger runtime.Logger) (*EventHub, error) {
+const (
+	dialRetryDelay = 2 * time.Second
+	// reconnectAttempts is how many times Dispatch tries to reconnect to cardinal after the connection is lost.
+	reconnectAttempts = 30
+)
+
+// dialEvents connects to cardinal's /events websocket, waiting for cardinal's host to be found.
+func dialEvents(logger runtime.Logger) (*websocket.Conn, error) {
 	url := makeWebSocketURL(eventEndpoint)
 	webSocketConnection, _, err := websocket.DefaultDialer.Dial(url, nil) //nolint:bodyclose // no need.
 	for err != nil {
@@ -30,12 +114,20 @@ func createEventHub(logger runtime.Logger) (*EventHub, error) {
 			// sleep a little try again...
 			logger.Info("No host found.")
 			logger.Info(err.Error())
-			time.Sleep(2 * time.Second)                                          //nolint: gomnd // its ok.
+			time.Sleep(dialRetryDelay)
 			webSocketConnection, _, err = websocket.DefaultDialer.Dial(url, nil) //nolint:bodyclose // no need.
 		} else {
 			return nil, err
 		}
 	}
+	return webSocketConnection, nil
+}
+
+func createEventHub(logger runtime.Logger) (*EventHub, error) {
+	webSocketConnection, err := dialEvents(logger)
+	if err != nil {
+		return nil, err
+	}
 	channelMap := sync.Map{}
 	res := EventHub{
 		inputConnection: webSocketConnection,
@@ -46,6 +138,49 @@ func createEventHub(logger runtime.Logger) (*EventHub, error) {
 	return &res, nil
 }
 
+// Authenticate signs an event handshake with the relay's private key. The relay is the signer of every persona it has
+// created, so cardinal will then also send the events that target those personas. Cardinal forgets the identity of a
+// connection once it is closed, so the handshake is sent again every time the hub reconnects.
+func (eh *EventHub) Authenticate(ctx context.Context, nk runtime.NakamaModule) error {
+	eh.authenticate = func(conn *websocket.Conn) error {
+		pk, nonce, err := getPrivateKeyAndANonce(ctx, nk)
+		if err != nil {
+			return err
+		}
+		handshake := map[string]any{"signerAddress": getSignerAddress()}
+		sp, err := sign.NewSystemTransaction(pk, globalNamespace, nonce, handshake)
+		if err != nil {
+			return err
+		}
+		return conn.WriteJSON(map[string]any{"auth": sp})
+	}
+	return eh.authenticate(eh.inputConnection)
+}
+
+// reconnect replaces the lost connection to cardinal with a new one, and authenticates the new connection if the hub
+// was authenticated before.
+func (eh *EventHub) reconnect(logger runtime.Logger) error {
+	_ = eh.inputConnection.Close()
+	var err error
+	for i := 0; i < reconnectAttempts && !eh.didShutdown.Load(); i++ {
+		time.Sleep(dialRetryDelay)
+		var conn *websocket.Conn
+		conn, err = dialEvents(logger)
+		if err != nil {
+			logger.Info("failed to reconnect to cardinal events: %s", err.Error())
+			continue
+		}
+		if eh.authenticate != nil {
+			if err = eh.authenticate(conn); err != nil {
+				return errors.Join(err, conn.Close())
+			}
+		}
+		eh.inputConnection = conn
+		return nil
+	}
+	return err
+}
+
 func (eh *EventHub) Subscribe(session string) chan *Event {
 	channel := make(chan *Event)
 	eh.channels.Store(session, channel)
@@ -70,19 +205,38 @@ func (eh *EventHub) Shutdown() {
 }
 
 // dispatch continually drains eh.inputConnection (events from cardinal) and sends copies to all subscribed channels.
-// This function is meant to be called in a goroutine.
+// If the connection is lost, it reconnects and authenticates again. This function is meant to be called in a goroutine.
 func (eh *EventHub) Dispatch(log runtime.Logger) error {
 	var err error
 	for !eh.didShutdown.Load() {
 		messageType, message, err := eh.inputConnection.ReadMessage() // will block
 		if err != nil {
-			eh.Shutdown()
+			if eh.didShutdown.Load() {
+				continue
+			}
+			log.Error("lost connection to cardinal events: %s", err.Error())
+			if err = eh.reconnect(log); err != nil {
+				log.Error("failed to reconnect to cardinal events: %s", err.Error())
+				eh.Shutdown()
+			}
 			continue
 		}
 		if messageType != websocket.TextMessage {
 			eh.Shutdown()
 			continue
 		}
+		if authMsg, ok := parseAuthenticationMessage(message); ok {
+			if authMsg.Error != "" {
+				log.Error("event handshake failed: %s", authMsg.Error)
+			}
+			continue
+		}
+		event, err := parseEvent(message)
+		if err != nil {
+			// The event may target a persona, so it is dropped rather than sent to everybody.
+			log.Error("dropping event: %s", err.Error())
+			continue
+		}
 		eh.channels.Range(func(key any, value any) bool {
 			channel, ok := value.(chan *Event)
 			if !ok {
@@ -90,7 +244,7 @@ func (eh *EventHub) Dispatch(log runtime.Logger) error {
 				eh.Shutdown()
 				return false
 			}
-			channel <- &Event{message: string(message)}
+			channel <- event
 			return true
 		})
 		if err != nil {
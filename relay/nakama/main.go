@@ -78,10 +78,6 @@ func InitModule(
 
 	initReceiptDispatcher(logger)
 
-	if err := initEventHub(ctx, logger, nk); err != nil {
-		return fmt.Errorf("failed to init event hub: %w", err)
-	}
-
 	if err := initReceiptMatch(ctx, logger, db, nk, initializer); err != nil {
 		return fmt.Errorf("unable to init match for receipt streaming: %w", err)
 	}
@@ -96,6 +92,11 @@ func InitModule(
 		return fmt.Errorf("failed to init persona tag assignment map: %w", err)
 	}
 
+	// The event hub authenticates with the private key, and routes events using the persona tag assignments.
+	if err := initEventHub(ctx, logger, nk); err != nil {
+		return fmt.Errorf("failed to init event hub: %w", err)
+	}
+
 	ptv := initPersonaTagVerifier(logger, nk, globalReceiptsDispatcher)
 
 	if err := initPersonaTagEndpoints(logger, initializer, ptv, notifier); err != nil {
@@ -132,6 +133,9 @@ func initEventHub(ctx context.Context, log runtime.Logger, nk runtime.NakamaModu
 	if err != nil {
 		return err
 	}
+	if err = eventHub.Authenticate(ctx, nk); err != nil {
+		return err
+	}
 	go func() {
 		err := eventHub.Dispatch(log)
 		if err != nil {
@@ -139,12 +143,11 @@ func initEventHub(ctx context.Context, log runtime.Logger, nk runtime.NakamaModu
 		}
 	}()
 
-	// for now send to everybody via notifications.
+	// events that target a persona are sent to the user that owns the persona, and all others are sent to everybody.
 	go func() {
 		channel := eventHub.Subscribe("main")
 		for event := range channel {
-			err := nk.NotificationSendAll(ctx, "event", map[string]interface{}{"message": event.message}, 1, true)
-			if err != nil {
+			if err := sendEventNotification(ctx, nk, event); err != nil {
 				log.Error("error sending notifications: %s", err.Error())
 			}
 		}
@@ -153,6 +156,21 @@ func initEventHub(ctx context.Context, log runtime.Logger, nk runtime.NakamaModu
 	return nil
 }
 
+// sendEventNotification sends the given event to the user that owns the persona it targets. Events that do not target a
+// persona are sent to everybody, and events that target a persona this relay does not know of are dropped.
+func sendEventNotification(ctx context.Context, nk runtime.NakamaModule, event *Event) error {
+	if event.PersonaTag == "" {
+		return nk.NotificationSendAll(ctx, "event", event.notificationContent(), 1, true)
+	}
+	val, ok := globalPersonaTagAssignment.Load(event.PersonaTag)
+	if !ok {
+		// This persona was not created through this relay.
+		return nil
+	}
+	userID, _ := val.(string)
+	return nk.NotificationSend(ctx, userID, "event", event.notificationContent(), 1, "", true)
+}
+
 func initReceiptMatch(ctx context.Context, logger runtime.Logger, _ *sql.DB, nk runtime.NakamaModule,
 	initializer runtime.Initializer) error {
 	err := initializer.RegisterMatch("lobby", func(ctx context.Context, logger runtime.Logger, db *sql.DB,
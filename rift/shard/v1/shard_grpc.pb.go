@@ -24,6 +24,9 @@ const _ = grpc.SupportPackageIsVersion7
 type ShardHandlerClient interface {
 	// SubmitCardinalBatch handles receiving transactions from a game shard and persisting them to the chain.
 	SubmitShardTx(ctx context.Context, in *SubmitShardTxRequest, opts ...grpc.CallOption) (*SubmitShardTxResponse, error)
+	// SubmitShardEpoch handles receiving every transaction of an epoch at once. The transactions replace any that were
+	// submitted for the epoch before, so an epoch can be submitted again without duplicating its transactions.
+	SubmitShardEpoch(ctx context.Context, in *SubmitShardEpochRequest, opts ...grpc.CallOption) (*SubmitShardEpochResponse, error)
 }
 
 type shardHandlerClient struct {
@@ -43,12 +46,24 @@ func (c *shardHandlerClient) SubmitShardTx(ctx context.Context, in *SubmitShardT
 	return out, nil
 }
 
+func (c *shardHandlerClient) SubmitShardEpoch(ctx context.Context, in *SubmitShardEpochRequest, opts ...grpc.CallOption) (*SubmitShardEpochResponse, error) {
+	out := new(SubmitShardEpochResponse)
+	err := c.cc.Invoke(ctx, "/world.engine.shard.v1.ShardHandler/SubmitShardEpoch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShardHandlerServer is the server API for ShardHandler service.
 // All implementations must embed UnimplementedShardHandlerServer
 // for forward compatibility
 type ShardHandlerServer interface {
 	// SubmitCardinalBatch handles receiving transactions from a game shard and persisting them to the chain.
 	SubmitShardTx(context.Context, *SubmitShardTxRequest) (*SubmitShardTxResponse, error)
+	// SubmitShardEpoch handles receiving every transaction of an epoch at once. The transactions replace any that were
+	// submitted for the epoch before, so an epoch can be submitted again without duplicating its transactions.
+	SubmitShardEpoch(context.Context, *SubmitShardEpochRequest) (*SubmitShardEpochResponse, error)
 	mustEmbedUnimplementedShardHandlerServer()
 }
 
@@ -59,6 +74,9 @@ type UnimplementedShardHandlerServer struct {
 func (UnimplementedShardHandlerServer) SubmitShardTx(context.Context, *SubmitShardTxRequest) (*SubmitShardTxResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitShardTx not implemented")
 }
+func (UnimplementedShardHandlerServer) SubmitShardEpoch(context.Context, *SubmitShardEpochRequest) (*SubmitShardEpochResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitShardEpoch not implemented")
+}
 func (UnimplementedShardHandlerServer) mustEmbedUnimplementedShardHandlerServer() {}
 
 // UnsafeShardHandlerServer may be embedded to opt out of forward compatibility for this service.
@@ -90,6 +108,24 @@ func _ShardHandler_SubmitShardTx_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShardHandler_SubmitShardEpoch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitShardEpochRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShardHandlerServer).SubmitShardEpoch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/world.engine.shard.v1.ShardHandler/SubmitShardEpoch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShardHandlerServer).SubmitShardEpoch(ctx, req.(*SubmitShardEpochRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShardHandler_ServiceDesc is the grpc.ServiceDesc for ShardHandler service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,6 +137,10 @@ var ShardHandler_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SubmitShardTx",
 			Handler:    _ShardHandler_SubmitShardTx_Handler,
 		},
+		{
+			MethodName: "SubmitShardEpoch",
+			Handler:    _ShardHandler_SubmitShardEpoch_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shard/v1/shard.proto",